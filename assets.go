@@ -0,0 +1,133 @@
+package templates
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"html/template"
+	"io/fs"
+	"path"
+	"strings"
+	"sync"
+
+	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
+)
+
+// assetHashLen is the number of hex characters of the content hash added to asset file names.
+const assetHashLen = 8
+
+// Assets fingerprints static files by adding a hash of their content to the file name, so they can be
+// cached indefinitely by browsers and are refetched when they change.
+//
+//	assets := templates.NewAssets(static.Content, "/static")
+//	assets.Register(e)
+//
+//	render := templates.New(templates.WithAssets(assets))
+//
+// In templates the asset function returns the fingerprinted path:
+//
+//	<link rel="stylesheet" href="{{asset "css/app.css"}}"> <!-- /static/css/app.3fa9d2c1.css -->
+//
+// Hashes are calculated once for each file, as assets are expected to be embedded in the binary.
+type Assets struct {
+	fsys   fs.FS
+	prefix string
+
+	mu     sync.RWMutex
+	hashes map[string]string // file name to content hash
+}
+
+// NewAssets creates Assets serving the files in fsys under the URL path prefix.
+func NewAssets(fsys fs.FS, prefix string) *Assets {
+	return &Assets{
+		fsys:   fsys,
+		prefix: "/" + strings.Trim(prefix, "/"),
+		hashes: make(map[string]string),
+	}
+}
+
+// WithAssets adds the asset template function which returns the fingerprinted path of a file.
+func WithAssets(a *Assets) Option {
+	return WithFuncs(template.FuncMap{
+		"asset": a.Path,
+	})
+}
+
+// Path returns the URL path of the named file with the hash of its content added before the extension.
+func (a *Assets) Path(name string) (string, error) {
+	name = strings.TrimPrefix(name, "/")
+
+	hash, err := a.hash(name)
+	if err != nil {
+		return "", err
+	}
+
+	ext := path.Ext(name)
+
+	return path.Join(a.prefix, strings.TrimSuffix(name, ext)+"."+hash+ext), nil
+}
+
+// Register adds a route serving the assets under the prefix to e.
+func (a *Assets) Register(e *echo.Echo) {
+	e.GET(a.prefix+"/*", a.Handler())
+}
+
+// Handler returns a handler serving the file named by the "*" path parameter. Fingerprinted paths with
+// the current hash are served with immutable cache headers, other files are served without them.
+func (a *Assets) Handler() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		name := strings.TrimPrefix(c.Param("*"), "/")
+
+		if orig, hash, ok := splitAssetHash(name); ok {
+			current, err := a.hash(orig)
+			if err == nil && current == hash {
+				c.Response().Header().Set(echo.HeaderCacheControl, "public, max-age=31536000, immutable")
+				return echo.StaticFileHandler(orig, a.fsys)(c)
+			}
+		}
+
+		return echo.StaticFileHandler(name, a.fsys)(c)
+	}
+}
+
+func (a *Assets) hash(name string) (string, error) {
+	a.mu.RLock()
+	hash, ok := a.hashes[name]
+	a.mu.RUnlock()
+
+	if ok {
+		return hash, nil
+	}
+
+	data, err := fs.ReadFile(a.fsys, name)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to read asset %s", name)
+	}
+
+	sum := sha256.Sum256(data)
+	hash = hex.EncodeToString(sum[:])[:assetHashLen]
+
+	a.mu.Lock()
+	a.hashes[name] = hash
+	a.mu.Unlock()
+
+	return hash, nil
+}
+
+// splitAssetHash splits a fingerprinted file name into the original name and the hash.
+func splitAssetHash(name string) (string, string, bool) {
+	ext := path.Ext(name)
+	base := strings.TrimSuffix(name, ext)
+
+	hashExt := path.Ext(base)
+	if len(hashExt) != assetHashLen+1 {
+		return "", "", false
+	}
+
+	hash := hashExt[1:]
+	if _, err := hex.DecodeString(hash); err != nil {
+		return "", "", false
+	}
+
+	return strings.TrimSuffix(base, hashExt) + ext, hash, true
+}
@@ -0,0 +1,62 @@
+package templates_test
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"testing/fstest"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/require"
+	templates "github.com/wolfeidau/echo-go-templates"
+)
+
+func Test_Assets(t *testing.T) {
+	assert := require.New(t)
+
+	static := fstest.MapFS{
+		"css/app.css": {Data: []byte("body { color: red }")},
+	}
+
+	sum := sha256.Sum256([]byte("body { color: red }"))
+	hashed := "/static/css/app." + hex.EncodeToString(sum[:])[:8] + ".css"
+
+	assets := templates.NewAssets(static, "static/")
+
+	fsys := fstest.MapFS{
+		"pages/index.html": {Data: []byte(`<link rel="stylesheet" href="{{asset "css/app.css"}}">`)},
+	}
+
+	render := templates.New(templates.WithAssets(assets))
+
+	err := render.Add(fsys, "pages/*.html")
+	assert.NoError(err)
+
+	s, err := render.RenderString("index.html", nil)
+	assert.NoError(err)
+	assert.Equal(`<link rel="stylesheet" href="`+hashed+`">`, s)
+
+	e := echo.New()
+	assets.Register(e)
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, hashed, http.NoBody))
+
+	assert.Equal(http.StatusOK, rec.Code)
+	assert.Equal("public, max-age=31536000, immutable", rec.Header().Get(echo.HeaderCacheControl))
+	assert.Contains(rec.Header().Get(echo.HeaderContentType), "text/css")
+	assert.Equal("body { color: red }", rec.Body.String())
+
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/static/css/app.css", http.NoBody))
+
+	assert.Equal(http.StatusOK, rec.Code)
+	assert.Empty(rec.Header().Get(echo.HeaderCacheControl))
+
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/static/css/app.00000000.css", http.NoBody))
+
+	assert.Equal(http.StatusNotFound, rec.Code)
+}
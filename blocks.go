@@ -0,0 +1,42 @@
+package templates
+
+import (
+	"fmt"
+	"text/template/parse"
+)
+
+// emptyDefines returns the names of the templates defined in the source with an empty body, other than
+// the source itself.
+//
+// text/template keeps the existing definition when a template is redefined as empty, so a page couldn't
+// otherwise clear a block given default content by its layout, such as {{define "sidebar"}}{{end}}.
+func emptyDefines(name string, source []byte, d delims) []string {
+	tree := parse.New(name)
+	tree.Mode = parse.SkipFuncCheck
+
+	trees := make(map[string]*parse.Tree)
+
+	_, err := tree.Parse(string(source), d.leftOrDefault(), d.rightOrDefault(), trees)
+	if err != nil {
+		// errors are reported when the source is parsed by the template engine
+		return nil
+	}
+
+	var names []string
+
+	for defined, t := range trees {
+		if defined != name && parse.IsEmptyTree(t.Root) {
+			names = append(names, defined)
+		}
+	}
+
+	return names
+}
+
+// clearDefine returns a definition of the template which renders nothing, but isn't empty so it replaces
+// an existing definition.
+func clearDefine(name string, d delims) string {
+	l, r := d.leftOrDefault(), d.rightOrDefault()
+
+	return fmt.Sprintf(`%sdefine %q%s%sif false%s%send%s%send%s`, l, name, r, l, r, l, r, l, r)
+}
@@ -0,0 +1,50 @@
+package templates_test
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/require"
+	templates "github.com/wolfeidau/echo-go-templates"
+)
+
+func Test_BlockDefaults(t *testing.T) {
+	fsys := fstest.MapFS{
+		"layout.html":             {Data: []byte(`<main>{{block "content" .}}{{end}}</main><aside>{{block "sidebar" .}}default sidebar{{end}}</aside>{{block "footer" .}}{{template "copyright" .}}{{end}}`)},
+		"includes/footer.html":    {Data: []byte(`{{define "copyright"}}(c) {{.}}{{end}}`)},
+		"pages/fallback.html":     {Data: []byte(`{{define "content"}}fallback{{end}}`)},
+		"pages/override.html":     {Data: []byte(`{{define "content"}}override{{end}}{{define "sidebar"}}custom sidebar{{end}}`)},
+		"pages/empty.html":        {Data: []byte(`{{define "content"}}empty{{end}}{{define "sidebar"}}{{end}}{{define "footer"}} {{end}}`)},
+		"pages/include.html":      {Data: []byte(`{{define "content"}}include{{end}}{{define "copyright"}}custom {{.}}{{end}}`)},
+		"pages/nolayout.html":     {Data: []byte(`{{define "sidebar"}}{{end}}standalone`)},
+		"pages/emptyinclude.html": {Data: []byte(`{{define "content"}}emptyinclude{{end}}{{define "copyright"}}{{end}}`)},
+	}
+
+	render := templates.New()
+
+	err := render.AddWithLayoutAndIncludes(fsys, "layout.html", "includes/*.html", "pages/*.html")
+	require.NoError(t, err)
+
+	tests := map[string]string{
+		"fallback.html":     "<main>fallback</main><aside>default sidebar</aside>(c) 2023",
+		"override.html":     "<main>override</main><aside>custom sidebar</aside>(c) 2023",
+		"empty.html":        "<main>empty</main><aside></aside>",
+		"include.html":      "<main>include</main><aside>default sidebar</aside>custom 2023",
+		"emptyinclude.html": "<main>emptyinclude</main><aside>default sidebar</aside>",
+	}
+
+	for name, expected := range tests {
+		t.Run(name, func(t *testing.T) {
+			s, err := render.RenderString(name, 2023)
+			require.NoError(t, err)
+			require.Equal(t, expected, s)
+		})
+	}
+
+	err = render.Add(fsys, "pages/nolayout.html")
+	require.NoError(t, err)
+
+	s, err := render.RenderString("nolayout.html", nil)
+	require.NoError(t, err)
+	require.Equal(t, "standalone", s)
+}
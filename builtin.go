@@ -0,0 +1,39 @@
+package templates
+
+import (
+	"embed"
+	"io"
+	"sync"
+	"text/template"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// builtinFS contains the templates used to render feeds, sitemaps and pagination.
+//
+//go:embed builtin
+var builtinFS embed.FS
+
+var (
+	builtinOnce      sync.Once
+	builtinTemplates *template.Template
+	builtinErr       error
+)
+
+// executeBuiltin executes the named builtin template, they are parsed on first use.
+func executeBuiltin(w io.Writer, name string, data any) error {
+	builtinOnce.Do(func() {
+		builtinTemplates, builtinErr = template.New("builtin").Funcs(template.FuncMap{
+			"xml":     xmlEscape,
+			"rfc1123": func(tm time.Time) string { return tm.UTC().Format(time.RFC1123Z) },
+			"rfc3339": func(tm time.Time) string { return tm.UTC().Format(time.RFC3339) },
+		}).ParseFS(builtinFS, "builtin/*.xml")
+	})
+
+	if builtinErr != nil {
+		return errors.Wrap(builtinErr, "failed to parse builtin templates")
+	}
+
+	return builtinTemplates.ExecuteTemplate(w, name, data)
+}
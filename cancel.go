@@ -0,0 +1,110 @@
+package templates
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+// RenderTimeoutError is returned when rendering a template takes longer than the timeout set using
+// WithRenderTimeout, it is wrapped in a RenderError so use errors.As to check for it.
+type RenderTimeoutError struct {
+	Name    string
+	Timeout time.Duration
+}
+
+func (e *RenderTimeoutError) Error() string {
+	return fmt.Sprintf("render template %s exceeded the timeout of %s", e.Name, e.Timeout)
+}
+
+// WithRenderTimeout aborts rendering a template which takes longer than d, returning a RenderTimeoutError,
+// so a runaway range over a huge dataset can't hold the connection forever. The deadline is checked each
+// time the template writes output, so a template function which blocks isn't interrupted.
+func WithRenderTimeout(d time.Duration) Option {
+	return func(t *TemplateRenderer) {
+		t.renderTimeout = d
+	}
+}
+
+// OutputLimitError is returned when a template writes more than the limit set using WithMaxOutput, it is
+// wrapped in a RenderError so use errors.As to check for it.
+type OutputLimitError struct {
+	Name  string
+	Limit int64
+}
+
+func (e *OutputLimitError) Error() string {
+	return fmt.Sprintf("render template %s exceeded the output limit of %d bytes", e.Name, e.Limit)
+}
+
+// WithMaxOutput aborts rendering a template which writes more than limit bytes, returning an
+// OutputLimitError, so a template bug can't produce a response large enough to exhaust memory.
+func WithMaxOutput(limit int64) Option {
+	return func(t *TemplateRenderer) {
+		t.maxOutput = limit
+	}
+}
+
+// contextWriter stops writing once the context is done, the deadline has passed or the limit is reached,
+// as a write error aborts the template execution this stops rendering pages for clients which have
+// disconnected.
+type contextWriter struct {
+	w        io.Writer
+	ctx      context.Context
+	deadline time.Time
+	name     string
+	timeout  time.Duration
+	limit    int64
+	n        int64
+}
+
+func (cw *contextWriter) Write(p []byte) (int, error) {
+	if err := cw.err(); err != nil {
+		return 0, err
+	}
+
+	if cw.limit > 0 && cw.n+int64(len(p)) > cw.limit {
+		return 0, &OutputLimitError{Name: cw.name, Limit: cw.limit}
+	}
+
+	n, err := cw.w.Write(p)
+	cw.n += int64(n)
+
+	return n, err
+}
+
+func (cw *contextWriter) err() error {
+	if cw.ctx != nil && cw.ctx.Err() != nil {
+		return cw.ctx.Err()
+	}
+
+	if !cw.deadline.IsZero() && time.Now().After(cw.deadline) {
+		return &RenderTimeoutError{Name: cw.name, Timeout: cw.timeout}
+	}
+
+	return nil
+}
+
+// cancellable wraps w so rendering the template is aborted once the request context is done, the render
+// timeout has passed or the output limit is reached, returning an error if the request context is
+// already done.
+func (t *TemplateRenderer) cancellable(w io.Writer, tmpl *Template, c echo.Context) (io.Writer, error) {
+	if c == nil && t.renderTimeout <= 0 && t.maxOutput <= 0 {
+		return w, nil
+	}
+
+	cw := &contextWriter{w: w, name: tmpl.name, timeout: t.renderTimeout, limit: t.maxOutput}
+
+	if c != nil {
+		cw.ctx = c.Request().Context()
+	}
+
+	if t.renderTimeout > 0 {
+		cw.deadline = time.Now().Add(t.renderTimeout)
+	}
+
+	return cw, cw.err()
+}
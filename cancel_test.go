@@ -0,0 +1,110 @@
+package templates_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"html/template"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/require"
+	templates "github.com/wolfeidau/echo-go-templates"
+)
+
+func Test_Render_Cancelled(t *testing.T) {
+	assert := require.New(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	rendered := 0
+
+	render := templates.New(templates.WithFuncs(template.FuncMap{
+		"item": func(i int) int {
+			rendered++
+
+			// the client disconnects part way through the page
+			if i == 2 {
+				cancel()
+			}
+
+			return i
+		},
+	}))
+
+	err := render.AddFromString("index.html", "", `{{range .}}<li>{{item .}}</li>{{end}}`)
+	assert.NoError(err)
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/", http.NoBody).WithContext(ctx)
+	c := e.NewContext(req, httptest.NewRecorder())
+
+	buf := new(bytes.Buffer)
+
+	err = render.Render(buf, "index.html", []int{1, 2, 3, 4, 5}, c)
+	assert.True(errors.Is(err, context.Canceled), err)
+	assert.Equal(2, rendered)
+	assert.Equal("<li>1</li><li>", buf.String())
+
+	// already cancelled requests aren't rendered
+	buf.Reset()
+
+	err = render.Render(buf, "index.html", []int{1}, c)
+	assert.True(errors.Is(err, context.Canceled), err)
+	assert.Equal(2, rendered)
+	assert.Empty(buf.String())
+}
+
+func Test_WithRenderTimeout(t *testing.T) {
+	assert := require.New(t)
+
+	render := templates.New(
+		templates.WithRenderTimeout(20*time.Millisecond),
+		templates.WithFuncs(template.FuncMap{
+			"slow": func(i int) int {
+				time.Sleep(10 * time.Millisecond)
+				return i
+			},
+		}),
+	)
+
+	err := render.AddFromString("index.html", "", `{{range .}}<li>{{slow .}}</li>{{end}}`)
+	assert.NoError(err)
+
+	_, err = render.RenderString("index.html", make([]int, 100))
+
+	var timeout *templates.RenderTimeoutError
+	assert.True(errors.As(err, &timeout), err)
+	assert.Equal("index.html", timeout.Name)
+	assert.Equal(20*time.Millisecond, timeout.Timeout)
+
+	out, err := render.RenderString("index.html", []int{1})
+	assert.NoError(err)
+	assert.Equal("<li>1</li>", out)
+}
+
+func Test_WithMaxOutput(t *testing.T) {
+	assert := require.New(t)
+
+	render := templates.New(templates.WithMaxOutput(32))
+
+	err := render.AddFromString("index.html", "", `{{range .}}<li>{{.}}</li>{{end}}`)
+	assert.NoError(err)
+
+	buf := new(bytes.Buffer)
+
+	err = render.Render(buf, "index.html", []int{1, 2, 3, 4, 5, 6, 7, 8}, nil)
+
+	var limit *templates.OutputLimitError
+	assert.True(errors.As(err, &limit), err)
+	assert.Equal("index.html", limit.Name)
+	assert.Equal(int64(32), limit.Limit)
+	assert.LessOrEqual(buf.Len(), 32)
+
+	out, err := render.RenderString("index.html", []int{1, 2})
+	assert.NoError(err)
+	assert.Equal("<li>1</li><li>2</li>", out)
+}
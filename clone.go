@@ -0,0 +1,76 @@
+package templates
+
+// Clone returns a copy of t with its own template registry and options, so templates and options can
+// be added to the copy without changing t, for example to extend a renderer configured by a library.
+// The templates registered with t at the time of the call are parsed again for the copy, using the
+// options they were registered with, and reloading the copy re-parses them without reading the
+// templates later added to t from strings. The page cache store, if enabled, is shared with t.
+func (t *TemplateRenderer) Clone() *TemplateRenderer {
+	c := t.Group()
+	c.registry = newRegistry()
+
+	t.writeMu.Lock()
+	defer t.writeMu.Unlock()
+
+	t.memory.mu.RLock()
+	for name, data := range t.memory.files {
+		c.memory.files[name] = data
+	}
+	t.memory.mu.RUnlock()
+
+	t.expected.Range(func(k, v any) bool {
+		c.expected.Store(k, v)
+		return true
+	})
+
+	// the ops are re-recorded against the copy, and those of the groups of t against a copy of the
+	// group using the registry of the copy
+	owners := map[*TemplateRenderer]*TemplateRenderer{t: c}
+
+	for _, o := range t.ops {
+		owner, ok := owners[o.owner]
+		if !ok {
+			g := *o.owner
+			g.registry = c.registry
+			owner = &g
+			owners[o.owner] = owner
+		}
+
+		o.owner = owner
+		c.ops = append(c.ops, o)
+	}
+
+	err := c.replay()
+	if err != nil {
+		// the templates of t can no longer be parsed, for example a file was removed, so the copy
+		// uses the parsed templates until it is reloaded
+		t.defaultLog().Warn().Err(err).Msg("failed to parse templates for clone")
+
+		t.mu.RLock()
+		for name, tmpl := range t.templates {
+			c.templates[name] = tmpl
+		}
+		t.mu.RUnlock()
+	}
+
+	return c
+}
+
+// Merge registers the templates of other with t, as if they had been added to t, so template sets
+// provided by plugins can be combined at startup. In strict mode a template with the same name as one
+// already registered is an error. Reloading t re-reads the templates registered with other.
+func (t *TemplateRenderer) Merge(other *TemplateRenderer) error {
+	return t.update(t, func(r *TemplateRenderer, templates map[string]*Template) error {
+		other.mu.RLock()
+		defer other.mu.RUnlock()
+
+		for _, name := range sortedNames(other.templates) {
+			err := r.register(templates, other.templates[name])
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
@@ -0,0 +1,84 @@
+package templates_test
+
+import (
+	"html/template"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	templates "github.com/wolfeidau/echo-go-templates"
+)
+
+func Test_Clone(t *testing.T) {
+	assert := require.New(t)
+
+	base := templates.New(templates.WithFuncs(template.FuncMap{"upper": strings.ToUpper}))
+
+	err := base.AddFromMap(map[string]string{
+		"layout.html": `<html>{{block "content" .}}{{end}}</html>`,
+		"index.html":  `{{upper .}}`,
+	})
+	assert.NoError(err)
+
+	clone := base.Clone()
+	templates.WithFuncs(template.FuncMap{"lower": strings.ToLower})(clone)
+
+	err = clone.AddFromString("about.html", "layout.html", `{{define "content"}}{{lower .}}{{end}}`)
+	assert.NoError(err)
+
+	assert.Equal([]string{"index.html", "layout.html"}, base.Names())
+	assert.Equal([]string{"about.html", "index.html", "layout.html"}, clone.Names())
+
+	s, err := clone.RenderString("about.html", "ABOUT")
+	assert.NoError(err)
+	assert.Equal("<html>about</html>", s)
+
+	s, err = clone.RenderString("index.html", "index")
+	assert.NoError(err)
+	assert.Equal("INDEX", s)
+
+	err = base.AddFromString("other.html", "", `{{lower .}}`)
+	assert.ErrorContains(err, `function "lower" not defined`)
+
+	err = clone.Reload()
+	assert.NoError(err)
+	assert.Equal([]string{"about.html", "index.html", "layout.html"}, clone.Names())
+
+	err = base.AddFromString("index.html", "", `base {{.}}`)
+	assert.NoError(err)
+
+	err = clone.Reload()
+	assert.NoError(err)
+
+	s, err = clone.RenderString("index.html", "index")
+	assert.NoError(err)
+	assert.Equal("INDEX", s)
+
+	s, err = base.RenderString("index.html", "index")
+	assert.NoError(err)
+	assert.Equal("base index", s)
+}
+
+func Test_Merge(t *testing.T) {
+	assert := require.New(t)
+
+	app := templates.New(templates.WithStrict())
+	assert.NoError(app.AddFromString("index.html", "", `app index`))
+
+	plugin := templates.New()
+	assert.NoError(plugin.AddFromMap(map[string]string{"plugin.html": `plugin {{.}}`}))
+
+	err := app.Merge(plugin)
+	assert.NoError(err)
+	assert.Equal([]string{"index.html", "plugin.html"}, app.Names())
+
+	s, err := app.RenderString("plugin.html", "page")
+	assert.NoError(err)
+	assert.Equal("plugin page", s)
+
+	duplicate := templates.New()
+	assert.NoError(duplicate.AddFromString("index.html", "", `other index`))
+
+	err = app.Merge(duplicate)
+	assert.EqualError(err, "template: duplicate template name: `index.html`")
+}
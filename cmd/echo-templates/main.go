@@ -0,0 +1,168 @@
+// Command echo-templates loads templates using the same rules as the library, parses them and runs
+// Validate, so template errors are caught in CI without starting the application:
+//
+//	echo-templates -dir views -layout layout.html -includes "includes/*.html" "pages/*.html"
+//	echo-templates -dir views -load
+//
+// It can also be run using go:generate:
+//
+//	//go:generate go run github.com/wolfeidau/echo-go-templates/cmd/echo-templates -layout layout.html "pages/*.html"
+//
+// Functions the templates call which aren't defined by the library, such as those added using WithFuncs,
+// are replaced with stubs as only parsing is checked. The exit status is 1 if any problems are found,
+// templates defined using {{define}} which are never referenced are reported but don't fail the check.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"html/template"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/rs/zerolog"
+	templates "github.com/wolfeidau/echo-go-templates"
+)
+
+func main() {
+	// the library logs each template registered at debug level
+	zerolog.SetGlobalLevel(zerolog.WarnLevel)
+
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+type config struct {
+	dir      string
+	layout   string
+	includes string
+	relative bool
+	load     bool
+	loadCfg  templates.LoadConfig
+	patterns []string
+}
+
+func run(args []string, stdout, stderr io.Writer) int {
+	cfg, err := parseFlags(args, stderr)
+	if err != nil {
+		return 2
+	}
+
+	render, err := load(cfg)
+	if err != nil {
+		fmt.Fprintf(stderr, "error: %v\n", err)
+		return 1
+	}
+
+	names := render.Names()
+
+	err = render.Validate()
+	if err != nil {
+		fmt.Fprintf(stderr, "%v\n", err)
+		return 1
+	}
+
+	fmt.Fprintf(stdout, "ok: %d templates\n", len(names))
+
+	// nothing has been rendered, so only report the defined templates which aren't referenced
+	registered := make(map[string]bool, len(names))
+	for _, name := range names {
+		registered[name] = true
+	}
+
+	for _, name := range render.Unused() {
+		if !registered[name] {
+			fmt.Fprintf(stdout, "unused: %s\n", name)
+		}
+	}
+
+	return 0
+}
+
+func parseFlags(args []string, stderr io.Writer) (config, error) {
+	var cfg config
+
+	fs := flag.NewFlagSet("echo-templates", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.StringVar(&cfg.dir, "dir", ".", "root directory of the templates")
+	fs.StringVar(&cfg.layout, "layout", "", "layout used by the templates, or a comma separated chain of layouts")
+	fs.StringVar(&cfg.includes, "includes", "", "comma separated patterns of the includes used by the templates")
+	fs.BoolVar(&cfg.relative, "relative", false, "name templates by their relative path")
+	fs.BoolVar(&cfg.load, "load", false, "load templates using the Load directory conventions")
+	fs.StringVar(&cfg.loadCfg.LayoutsDir, "layouts-dir", "", "layouts directory used with -load")
+	fs.StringVar(&cfg.loadCfg.PagesDir, "pages-dir", "", "pages directory used with -load")
+	fs.StringVar(&cfg.loadCfg.PartialsDir, "partials-dir", "", "partials directory used with -load")
+	fs.StringVar(&cfg.loadCfg.Ext, "ext", "", "template file extension used with -load")
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "usage: echo-templates [flags] patterns...")
+		fs.PrintDefaults()
+	}
+
+	err := fs.Parse(args)
+	if err != nil {
+		return cfg, err
+	}
+
+	cfg.patterns = fs.Args()
+
+	if !cfg.load && len(cfg.patterns) == 0 {
+		fs.Usage()
+		return cfg, fmt.Errorf("no patterns")
+	}
+
+	return cfg, nil
+}
+
+var undefinedFunc = regexp.MustCompile(`function "([^"]+)" not defined`)
+
+// load registers the templates, adding a stub for each undefined function until they parse.
+func load(cfg config) (*templates.TemplateRenderer, error) {
+	stubs := template.FuncMap{}
+
+	for {
+		render, err := register(cfg, stubs)
+		if err == nil {
+			return render, nil
+		}
+
+		m := undefinedFunc.FindStringSubmatch(err.Error())
+		if m == nil || stubs[m[1]] != nil {
+			return nil, err
+		}
+
+		stubs[m[1]] = func(...any) string { return "" }
+	}
+}
+
+func register(cfg config, stubs template.FuncMap) (*templates.TemplateRenderer, error) {
+	opts := []templates.Option{
+		templates.WithFuncs(templates.CommonFuncs()),
+		templates.WithFuncs(stubs),
+	}
+
+	if cfg.relative {
+		opts = append(opts, templates.WithRelativeNames())
+	}
+
+	render := templates.New(opts...)
+	fsys := os.DirFS(cfg.dir)
+
+	if cfg.load {
+		return render, render.Load(fsys, cfg.loadCfg)
+	}
+
+	if cfg.includes != "" {
+		err := render.SetIncludes(fsys, strings.Split(cfg.includes, ",")...)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var layouts []string
+	if cfg.layout != "" {
+		layouts = strings.Split(cfg.layout, ",")
+	}
+
+	return render, render.AddWithLayouts(fsys, layouts, cfg.patterns...)
+}
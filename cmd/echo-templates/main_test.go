@@ -0,0 +1,35 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_run(t *testing.T) {
+	assert := require.New(t)
+
+	stdout, stderr := new(bytes.Buffer), new(bytes.Buffer)
+
+	code := run([]string{"-dir", "testdata/valid", "-layout", "layout.html", "-includes", "includes/*.html", "pages/*.html"}, stdout, stderr)
+	assert.Equal(0, code, stderr.String())
+	assert.Equal("ok: 1 templates\nunused: header.html#footer\n", stdout.String())
+
+	stdout.Reset()
+	stderr.Reset()
+
+	code = run([]string{"-dir", "testdata/invalid", "-layout", "layout.html", "pages/index.html"}, stdout, stderr)
+	assert.Equal(1, code)
+	assert.Contains(stderr.String(), `template index.html: layout.html references undefined template "header"`)
+	assert.Contains(stderr.String(), `template index.html: content references undefined template "missing"`)
+
+	stderr.Reset()
+
+	code = run([]string{"-dir", "testdata/invalid", "-layout", "layout.html", "pages/*.html"}, stdout, stderr)
+	assert.Equal(1, code)
+	assert.Contains(stderr.String(), "failed to parse template pages/broken.html")
+
+	code = run(nil, stdout, stderr)
+	assert.Equal(2, code)
+}
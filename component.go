@@ -0,0 +1,93 @@
+package templates
+
+import (
+	"fmt"
+	"html"
+	"html/template"
+	"io/fs"
+)
+
+// componentPrefix is prepended to the names of component templates, so they don't collide with pages.
+const componentPrefix = "components/"
+
+// ComponentData is the data passed to component templates, the props and the content of the slots:
+//
+//	<div class="card">
+//	  <h2>{{.Props.title}}</h2>
+//	  {{.Slot ""}}
+//	  {{if .HasSlot "footer"}}{{.Slot "footer"}}{{else}}<a href="#">Close</a>{{end}}
+//	</div>
+type ComponentData struct {
+	Props map[string]any
+	Slots map[string]template.HTML
+}
+
+// Slot returns the content of the named slot, the default slot is named "".
+func (d ComponentData) Slot(name string) template.HTML {
+	return d.Slots[name]
+}
+
+// HasSlot returns true if content was passed for the named slot, so a component can render default content.
+func (d ComponentData) HasSlot(name string) bool {
+	_, ok := d.Slots[name]
+	return ok
+}
+
+// componentSlot is the content of a named slot created by the slot template function.
+type componentSlot struct {
+	name    string
+	content template.HTML
+}
+
+// RegisterComponents registers reusable component templates, named by their file name without the
+// extension, and adds the component and slot template functions which render them:
+//
+//	err := render.RegisterComponents(views.Content, "components/*.html")
+//
+//	{{component "card" (dict "title" .Title) "default slot" (slot "footer" .FooterHTML)}}
+//
+// Strings are escaped, template.HTML values are passed through, so components can be nested by passing
+// the output of a component as a slot. Components are rendered without the request, values they need
+// must be passed as props. It must be called before templates using component are added.
+func (t *TemplateRenderer) RegisterComponents(fsys fs.FS, patterns ...string) error {
+	t.addFuncs(template.FuncMap{
+		"component": t.renderComponent,
+		"slot": func(name string, content any) componentSlot {
+			return componentSlot{name: name, content: slotContent(content)}
+		},
+	})
+
+	return t.Group(WithNameFunc(Prefix(componentPrefix, TrimExt(BaseName)))).Add(fsys, patterns...)
+}
+
+func (t *TemplateRenderer) renderComponent(name string, props map[string]any, slots ...any) (template.HTML, error) {
+	data := ComponentData{Props: props, Slots: make(map[string]template.HTML)}
+
+	for _, s := range slots {
+		if cs, ok := s.(componentSlot); ok {
+			data.Slots[cs.name] += cs.content
+			continue
+		}
+
+		data.Slots[""] += slotContent(s)
+	}
+
+	out, err := t.executeNested(componentPrefix+name, data)
+	if err != nil {
+		return "", fmt.Errorf("component %s: %w", name, err)
+	}
+
+	return template.HTML(out), nil //nolint:gosec // output of a template
+}
+
+// slotContent returns the content, escaping it unless it is template.HTML.
+func slotContent(content any) template.HTML {
+	switch v := content.(type) {
+	case template.HTML:
+		return v
+	case nil:
+		return ""
+	default:
+		return template.HTML(html.EscapeString(fmt.Sprint(v))) //nolint:gosec // escaped
+	}
+}
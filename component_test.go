@@ -0,0 +1,89 @@
+package templates_test
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/require"
+	templates "github.com/wolfeidau/echo-go-templates"
+)
+
+func Test_RegisterComponents(t *testing.T) {
+	assert := require.New(t)
+
+	fsys := fstest.MapFS{
+		"components/card.html":  {Data: []byte(`<div><h2>{{.Props.title}}</h2>{{.Slot ""}}<footer>{{if .HasSlot "footer"}}{{.Slot "footer"}}{{else}}close{{end}}</footer></div>`)},
+		"components/badge.html": {Data: []byte(`<span>{{.Props.count}}</span>`)},
+		"index.html": {Data: []byte(`{{component "card" (dict "title" .Title) "<b>body</b>"}}` +
+			`{{component "card" (dict "title" "nested") (component "badge" (dict "count" 3)) (slot "footer" "done")}}`)},
+		"missing.html": {Data: []byte(`{{component "nope" nil}}`)},
+	}
+
+	render := templates.New(templates.WithFuncs(templates.CommonFuncs()))
+
+	err := render.RegisterComponents(fsys, "components/*.html")
+	assert.NoError(err)
+
+	err = render.Add(fsys, "index.html", "missing.html")
+	assert.NoError(err)
+
+	s, err := render.RenderString("index.html", map[string]any{"Title": "Tom & Jerry"})
+	assert.NoError(err)
+	assert.Equal(`<div><h2>Tom &amp; Jerry</h2>&lt;b&gt;body&lt;/b&gt;<footer>close</footer></div>`+
+		`<div><h2>nested</h2><span>3</span><footer>done</footer></div>`, s)
+
+	_, err = render.RenderString("missing.html", nil)
+	assert.ErrorContains(err, "component nope: template: no template named")
+}
+
+func Test_RegisterComponents_ViewModel(t *testing.T) {
+	assert := require.New(t)
+
+	fsys := fstest.MapFS{
+		"components/badge.html": {Data: []byte(`<span>{{.Props.count}}</span>`)},
+		"index.html":            {Data: []byte(`{{.Data.Name}} {{component "badge" (dict "count" .Data.Count)}}`)},
+	}
+
+	renders := 0
+
+	render := templates.New(
+		templates.WithFuncs(templates.CommonFuncs()),
+		templates.WithViewModel(),
+		templates.WithRenderEvents(func(templates.RenderEvent) { renders++ }),
+	)
+
+	err := render.RegisterComponents(fsys, "components/*.html")
+	assert.NoError(err)
+
+	err = render.Add(fsys, "index.html")
+	assert.NoError(err)
+
+	s, err := render.RenderString("index.html", map[string]any{"Name": "inbox", "Count": 3})
+	assert.NoError(err)
+	assert.Equal(`inbox <span>3</span>`, s)
+	assert.Equal(1, renders)
+}
+
+func Test_RegisterComponents_LazyParsing(t *testing.T) {
+	assert := require.New(t)
+
+	fsys := fstest.MapFS{
+		"components/badge.html": {Data: []byte(`<span>{{.Props.count}}</span>`)},
+		"index.html":            {Data: []byte(`hello {{.}}`)},
+	}
+
+	render := templates.New(templates.WithLazyParsing())
+
+	err := render.Add(fsys, "index.html")
+	assert.NoError(err)
+
+	done := make(chan error)
+	go func() {
+		_, err := render.RenderString("index.html", "world")
+		done <- err
+	}()
+
+	err = render.RegisterComponents(fsys, "components/*.html")
+	assert.NoError(err)
+	assert.NoError(<-done)
+}
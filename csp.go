@@ -0,0 +1,105 @@
+package templates
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"html/template"
+	"regexp"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
+)
+
+// CSPNonceContextKey is the context key the CSPNonce middleware stores the nonce under.
+const CSPNonceContextKey = "csp_nonce"
+
+// CSPNonce returns middleware which generates a random nonce for each request, which is available in
+// templates using the cspNonce function added by WithCSPNonce. If policy isn't empty it is sent as the
+// Content-Security-Policy header with each {nonce} replaced by the nonce:
+//
+//	e.Use(templates.CSPNonce("script-src 'nonce-{nonce}'; style-src 'nonce-{nonce}'"))
+func CSPNonce(policy string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			b := make([]byte, 16)
+
+			_, err := rand.Read(b)
+			if err != nil {
+				return errors.Wrap(err, "failed to generate csp nonce")
+			}
+
+			nonce := base64.RawURLEncoding.EncodeToString(b)
+
+			c.Set(CSPNonceContextKey, nonce)
+
+			if policy != "" {
+				c.Response().Header().Set(echo.HeaderContentSecurityPolicy, strings.ReplaceAll(policy, "{nonce}", nonce))
+			}
+
+			return next(c)
+		}
+	}
+}
+
+// CSPNonceFromContext returns the nonce generated by the CSPNonce middleware for the request.
+func CSPNonceFromContext(c echo.Context) string {
+	if c == nil {
+		return ""
+	}
+
+	nonce, _ := c.Get(CSPNonceContextKey).(string)
+
+	return nonce
+}
+
+// WithCSPNonce adds the cspNonce template function which returns the nonce generated by the CSPNonce
+// middleware for the request:
+//
+//	<script nonce="{{cspNonce}}">...</script>
+func WithCSPNonce() Option {
+	return WithContextFuncs(func(c echo.Context) template.FuncMap {
+		return template.FuncMap{
+			"cspNonce": func() string { return CSPNonceFromContext(c) },
+		}
+	})
+}
+
+var scriptOrStyleTag = regexp.MustCompile(`(?i)<(script|style)(\s[^>]*)?>`)
+
+var nonceAttr = regexp.MustCompile(`(?i)\snonce\s*=`)
+
+// InjectCSPNonce returns a Transformer which adds the nonce generated by the CSPNonce middleware to
+// every <script> and <style> tag which doesn't already have one, rather than using cspNonce in each.
+// Output rendered without a request, or a nonce, is unchanged.
+//
+// The transformer can't tell the tags written in templates from those in values rendered as
+// template.HTML, such as the output of safeHTML, markdown or a component slot, so a tag injected through
+// that content is given the nonce and allowed to run by the policy. Only use it when every template.HTML
+// value is trusted, otherwise add nonce="{{cspNonce}}" to the tags in the templates using WithCSPNonce.
+func InjectCSPNonce() Transformer {
+	return nonceInjector{}
+}
+
+type nonceInjector struct{}
+
+func (nonceInjector) Transform(name string, in []byte) ([]byte, error) {
+	return in, nil
+}
+
+func (nonceInjector) TransformContext(c echo.Context, name string, in []byte) ([]byte, error) {
+	nonce := CSPNonceFromContext(c)
+	if nonce == "" {
+		return in, nil
+	}
+
+	return scriptOrStyleTag.ReplaceAllFunc(in, func(tag []byte) []byte {
+		if nonceAttr.Match(tag) {
+			return tag
+		}
+
+		m := scriptOrStyleTag.FindSubmatchIndex(tag)
+
+		return []byte(string(tag[:m[3]]) + ` nonce="` + nonce + `"` + string(tag[m[3]:]))
+	}), nil
+}
@@ -0,0 +1,47 @@
+package templates_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"testing/fstest"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/require"
+	templates "github.com/wolfeidau/echo-go-templates"
+)
+
+func Test_CSPNonce(t *testing.T) {
+	assert := require.New(t)
+
+	fsys := fstest.MapFS{
+		"pages/index.html": {Data: []byte(`<script nonce="{{cspNonce}}">a()</script><script src="/b.js"></script><style>p{}</style>`)},
+	}
+
+	render := templates.New(templates.WithCSPNonce(), templates.WithTransformer(templates.InjectCSPNonce()))
+
+	err := render.Add(fsys, "pages/*.html")
+	assert.NoError(err)
+
+	e := echo.New()
+	e.Renderer = render
+	e.Use(templates.CSPNonce("script-src 'nonce-{nonce}'"))
+	e.GET("/", func(c echo.Context) error {
+		return c.Render(http.StatusOK, "index.html", nil)
+	})
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", http.NoBody))
+
+	assert.Equal(http.StatusOK, rec.Code)
+
+	policy := rec.Header().Get(echo.HeaderContentSecurityPolicy)
+	assert.Regexp(`^script-src 'nonce-[A-Za-z0-9_-]{22}'$`, policy)
+
+	nonce := policy[len("script-src 'nonce-") : len(policy)-1]
+	assert.Equal(`<script nonce="`+nonce+`">a()</script><script nonce="`+nonce+`" src="/b.js"></script><style nonce="`+nonce+`">p{}</style>`, rec.Body.String())
+
+	s, err := render.RenderString("index.html", nil)
+	assert.NoError(err)
+	assert.Equal(`<script nonce="">a()</script><script src="/b.js"></script><style>p{}</style>`, s)
+}
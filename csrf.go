@@ -0,0 +1,57 @@
+package templates
+
+import (
+	"fmt"
+	"html"
+	"html/template"
+
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	// CSRFContextKey is the default context key the Echo CSRF middleware stores the token under.
+	CSRFContextKey = "csrf"
+	// CSRFFormField is the default name of the form field rendered by csrfField.
+	CSRFFormField = "_csrf"
+	// CSRFTokenLookup configures the Echo CSRF middleware to read the token from the form field
+	// rendered by csrfField, or the X-CSRF-Token header used by JavaScript clients.
+	CSRFTokenLookup = "form:" + CSRFFormField + ",header:" + echo.HeaderXCSRFToken
+)
+
+// WithCSRF adds the csrf and csrfField template functions which read the token set by Echo's CSRF
+// middleware from the request context, empty arguments use CSRFContextKey and CSRFFormField.
+//
+//	e.Use(middleware.CSRFWithConfig(middleware.CSRFConfig{TokenLookup: templates.CSRFTokenLookup}))
+//
+// In templates csrf returns the token, and csrfField returns a hidden input containing it:
+//
+//	<form method="post">{{csrfField}}...</form>
+func WithCSRF(contextKey, formField string) Option {
+	if contextKey == "" {
+		contextKey = CSRFContextKey
+	}
+
+	if formField == "" {
+		formField = CSRFFormField
+	}
+
+	return WithContextFuncs(func(c echo.Context) template.FuncMap {
+		token := func() string {
+			if c == nil {
+				return ""
+			}
+
+			token, _ := c.Get(contextKey).(string)
+
+			return token
+		}
+
+		return template.FuncMap{
+			"csrf": token,
+			"csrfField": func() template.HTML {
+				return template.HTML(fmt.Sprintf(`<input type="hidden" name="%s" value="%s">`, //nolint:gosec // values are escaped
+					html.EscapeString(formField), html.EscapeString(token())))
+			},
+		}
+	})
+}
@@ -0,0 +1,54 @@
+package templates_test
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"testing/fstest"
+
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+	"github.com/stretchr/testify/require"
+	templates "github.com/wolfeidau/echo-go-templates"
+)
+
+func Test_WithCSRF(t *testing.T) {
+	assert := require.New(t)
+
+	fsys := fstest.MapFS{
+		"form.html": {Data: []byte(`<form>{{csrfField}}</form><meta name="csrf" content="{{csrf}}">`)},
+	}
+
+	render := templates.New(templates.WithCSRF("", ""))
+
+	err := render.Add(fsys, "*.html")
+	assert.NoError(err)
+
+	e := echo.New()
+	e.Renderer = render
+	e.Use(middleware.CSRFWithConfig(middleware.CSRFConfig{TokenLookup: templates.CSRFTokenLookup}))
+	e.GET("/", func(c echo.Context) error {
+		return c.Render(http.StatusOK, "form.html", nil)
+	})
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", http.NoBody))
+
+	assert.Equal(http.StatusOK, rec.Code)
+
+	var token string
+	for _, cookie := range rec.Result().Cookies() {
+		if cookie.Name == "_csrf" {
+			token = cookie.Value
+		}
+	}
+
+	assert.NotEmpty(token)
+	assert.Equal(`<form><input type="hidden" name="_csrf" value="`+token+`"></form><meta name="csrf" content="`+token+`">`, rec.Body.String())
+
+	output := bytes.NewBufferString("")
+	err = render.Render(output, "form.html", nil, e.NewContext(httptest.NewRequest(http.MethodGet, "/", http.NoBody), httptest.NewRecorder()))
+	assert.NoError(err)
+	assert.Equal(`<form><input type="hidden" name="_csrf" value=""></form><meta name="csrf" content="">`, output.String())
+}
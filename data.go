@@ -0,0 +1,61 @@
+package templates
+
+import (
+	"html/template"
+
+	"github.com/labstack/echo/v4"
+)
+
+// DataDecorator modifies or replaces the data passed to a template before each render, for example to
+// add values used on every page such as the current user.
+type DataDecorator func(c echo.Context, data any) any
+
+// WithDataDecorator adds a DataDecorator, decorators are applied in the order they are added.
+func WithDataDecorator(fn DataDecorator) Option {
+	return func(t *TemplateRenderer) {
+		t.decorators = append(t.decorators, fn)
+	}
+}
+
+// WithGlobalData merges site wide values, such as the application name and version, into the data
+// passed to every template. Globals are added to nil and map[string]any data without replacing existing
+// keys, as other types can't be merged the globals are also available using the globals function:
+//
+//	{{(globals).Version}}
+func WithGlobalData(globals map[string]any) Option {
+	return func(t *TemplateRenderer) {
+		t.globals = globals
+
+		WithFuncs(template.FuncMap{
+			"globals": func() map[string]any { return globals },
+		})(t)
+
+		WithDataDecorator(func(c echo.Context, data any) any {
+			return mergeGlobals(globals, data)
+		})(t)
+	}
+}
+
+func mergeGlobals(globals map[string]any, data any) any {
+	switch d := data.(type) {
+	case nil:
+		merged := make(map[string]any, len(globals))
+		for k, v := range globals {
+			merged[k] = v
+		}
+
+		return merged
+	case map[string]any:
+		merged := make(map[string]any, len(globals)+len(d))
+		for k, v := range globals {
+			merged[k] = v
+		}
+		for k, v := range d {
+			merged[k] = v
+		}
+
+		return merged
+	default:
+		return data
+	}
+}
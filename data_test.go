@@ -0,0 +1,69 @@
+package templates_test
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"testing/fstest"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/require"
+	templates "github.com/wolfeidau/echo-go-templates"
+)
+
+func Test_WithGlobalData(t *testing.T) {
+	assert := require.New(t)
+
+	fsys := fstest.MapFS{
+		"map.html":    {Data: []byte(`{{.AppName}} {{.Version}} {{.Title}}`)},
+		"struct.html": {Data: []byte(`{{(globals).AppName}} {{.Title}}`)},
+	}
+
+	render := templates.New(templates.WithGlobalData(map[string]any{"AppName": "app", "Version": "1.0", "Title": "default"}))
+
+	err := render.Add(fsys, "*.html")
+	assert.NoError(err)
+
+	e := echo.New()
+	c := e.NewContext(httptest.NewRequest(http.MethodGet, "/", http.NoBody), httptest.NewRecorder())
+
+	output := bytes.NewBufferString("")
+	err = render.Render(output, "map.html", map[string]any{"Title": "home"}, c)
+	assert.NoError(err)
+	assert.Equal("app 1.0 home", output.String())
+
+	output = bytes.NewBufferString("")
+	err = render.Render(output, "map.html", nil, c)
+	assert.NoError(err)
+	assert.Equal("app 1.0 default", output.String())
+
+	output = bytes.NewBufferString("")
+	err = render.Render(output, "struct.html", struct{ Title string }{Title: "page"}, c)
+	assert.NoError(err)
+	assert.Equal("app page", output.String())
+}
+
+func Test_WithDataDecorator(t *testing.T) {
+	assert := require.New(t)
+
+	fsys := fstest.MapFS{
+		"user.html": {Data: []byte(`{{.User}}: {{.Data}}`)},
+	}
+
+	render := templates.New(templates.WithDataDecorator(func(c echo.Context, data any) any {
+		return map[string]any{"User": c.Get("user"), "Data": data}
+	}))
+
+	err := render.Add(fsys, "*.html")
+	assert.NoError(err)
+
+	e := echo.New()
+	c := e.NewContext(httptest.NewRequest(http.MethodGet, "/", http.NoBody), httptest.NewRecorder())
+	c.Set("user", "mark")
+
+	output := bytes.NewBufferString("")
+	err = render.Render(output, "user.html", "hello", c)
+	assert.NoError(err)
+	assert.Equal("mark: hello", output.String())
+}
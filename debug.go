@@ -0,0 +1,88 @@
+package templates
+
+import (
+	"bytes"
+	"errors"
+	"html/template"
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+// WithDebug renders template execution errors as an HTML page showing the error and the template source
+// with the failing line highlighted, rather than a truncated page. Output is buffered so nothing is written
+// if the template fails, this is intended for development, typically along with WithReload.
+func WithDebug(debug bool) Option {
+	return func(t *TemplateRenderer) {
+		t.debug = debug
+	}
+}
+
+var debugPage = template.Must(template.New("debug").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Template error: {{.Name}}</title>
+<style>
+body { font-family: sans-serif; margin: 2rem; color: #222; }
+h1 { color: #b00020; font-size: 1.4rem; }
+.message { background: #fdecea; padding: 1rem; white-space: pre-wrap; font-family: monospace; }
+.source { background: #f6f8fa; padding: 1rem 0; font-family: monospace; }
+.source div { padding: 0 1rem; white-space: pre; }
+.source .failing { background: #ffd7d5; font-weight: bold; }
+</style>
+</head>
+<body>
+<h1>Template error rendering {{.Name}}</h1>
+{{with .Path}}<p>{{.}}{{with $.Line}}:{{.}}{{end}}</p>{{end}}
+<div class="message">{{.Message}}</div>
+{{with .Lines}}<div class="source">{{range .}}<div{{if .Failing}} class="failing"{{end}}>{{.Text}}</div>{{end}}</div>{{end}}
+</body>
+</html>
+`))
+
+type debugLine struct {
+	Text    string
+	Failing bool
+}
+
+// writeDebugPage writes the error page to the response, it is written directly as echo discards the
+// output of a renderer which returns an error.
+func (t *TemplateRenderer) writeDebugPage(c echo.Context, tmpl *Template, err error) {
+	if c == nil || c.Response().Committed {
+		return
+	}
+
+	page := struct {
+		Name    string
+		Path    string
+		Line    int
+		Message string
+		Lines   []debugLine
+	}{Name: tmpl.name, Message: err.Error()}
+
+	var rerr *RenderError
+	if errors.As(err, &rerr) {
+		page.Path = rerr.Path
+		page.Line = rerr.Line
+		page.Message = rerr.Err.Error()
+
+		for _, line := range strings.Split(strings.TrimSuffix(rerr.Snippet, "\n"), "\n") {
+			if line == "" {
+				continue
+			}
+
+			page.Lines = append(page.Lines, debugLine{Text: line[2:], Failing: line[0] == '>'})
+		}
+	}
+
+	buf := new(bytes.Buffer)
+
+	if perr := debugPage.Execute(buf, page); perr != nil {
+		t.ctxLog(c).Error().Err(perr).Msg("render debug page failed")
+		return
+	}
+
+	_ = c.HTMLBlob(http.StatusInternalServerError, buf.Bytes())
+}
@@ -0,0 +1,52 @@
+package templates_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"testing/fstest"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/require"
+	templates "github.com/wolfeidau/echo-go-templates"
+)
+
+func Test_WithDebug(t *testing.T) {
+	assert := require.New(t)
+
+	fsys := fstest.MapFS{
+		"layout.html":      {Data: []byte("<html>{{block \"content\" .}}{{end}}</html>")},
+		"pages/index.html": {Data: []byte("{{define \"content\"}}\n<h1>{{.Title}}</h1>\n<p>{{index .Items 3}}</p>\n{{end}}")},
+	}
+
+	render := templates.New(templates.WithDebug(true))
+
+	err := render.AddWithLayout(fsys, "layout.html", "pages/*.html")
+	assert.NoError(err)
+
+	e := echo.New()
+	e.Renderer = render
+	e.GET("/", func(c echo.Context) error {
+		return c.Render(http.StatusOK, "index.html", map[string]any{"Title": "<home>", "Items": []string{}})
+	})
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", http.NoBody))
+
+	assert.Equal(http.StatusInternalServerError, rec.Code)
+	assert.Contains(rec.Header().Get(echo.HeaderContentType), echo.MIMETextHTML)
+	assert.Contains(rec.Body.String(), "<h1>Template error rendering index.html</h1>")
+	assert.Contains(rec.Body.String(), "<p>pages/index.html:3</p>")
+	assert.Contains(rec.Body.String(), `<div class="failing">3 | &lt;p&gt;{{index .Items 3}}&lt;/p&gt;</div>`)
+	assert.NotContains(rec.Body.String(), "&lt;home&gt;")
+
+	e.GET("/ok", func(c echo.Context) error {
+		return c.Render(http.StatusOK, "index.html", map[string]any{"Title": "home", "Items": []string{"0", "1", "2", "3"}})
+	})
+
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/ok", http.NoBody))
+
+	assert.Equal(http.StatusOK, rec.Code)
+	assert.Equal("<html>\n<h1>home</h1>\n<p>3</p>\n</html>", rec.Body.String())
+}
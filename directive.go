@@ -0,0 +1,41 @@
+package templates
+
+import (
+	"regexp"
+	"strings"
+)
+
+// layoutDirective returns the layouts named in a comment at the start of a template, such as:
+//
+//	{{/* layout: admin.html */}}
+//
+// The layouts are paths in the same file system as the template, nested layouts are separated by commas
+// with the outermost first, and "none" registers the template without a layout. This overrides the
+// layouts passed when adding the template, nil is returned if there is no directive.
+//
+// When custom delimiters are configured the comment uses them in place of the braces.
+func layoutDirective(source []byte, d delims) []string {
+	re := regexp.MustCompile(`^\s*` + regexp.QuoteMeta(d.leftOrDefault()) + `-?\s*/\*\s*layout:\s*(.*?)\s*\*/\s*-?` + regexp.QuoteMeta(d.rightOrDefault()))
+
+	m := re.FindSubmatch(source)
+	if m == nil {
+		return nil
+	}
+
+	return splitLayouts(string(m[1]))
+}
+
+// splitLayouts splits a comma separated list of layouts, "none" returns an empty list.
+func splitLayouts(value string) []string {
+	layouts := []string{}
+
+	if value == "none" {
+		return layouts
+	}
+
+	for _, layout := range strings.Split(value, ",") {
+		layouts = append(layouts, strings.TrimSpace(layout))
+	}
+
+	return layouts
+}
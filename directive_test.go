@@ -0,0 +1,49 @@
+package templates_test
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"testing/fstest"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/require"
+	templates "github.com/wolfeidau/echo-go-templates"
+)
+
+func Test_LayoutDirective(t *testing.T) {
+	assert := require.New(t)
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/", http.NoBody)
+	rec := httptest.NewRecorder()
+
+	fsys := fstest.MapFS{
+		"layouts/base.html":  {Data: []byte(`base {{block "content" .}}{{end}}`)},
+		"layouts/admin.html": {Data: []byte(`{{define "content"}}admin {{block "page" .}}{{end}}{{end}}`)},
+		"pages/index.html":   {Data: []byte(`{{define "content"}}index{{end}}`)},
+		"pages/users.html":   {Data: []byte("{{/* layout: layouts/base.html, layouts/admin.html */}}\n{{define \"page\"}}users{{end}}")},
+		"pages/bare.html":    {Data: []byte(`{{- /* layout: none */ -}}bare`)},
+	}
+
+	render := templates.New()
+
+	err := render.AddWithLayout(fsys, "layouts/base.html", "pages/*.html")
+	assert.NoError(err)
+
+	c := e.NewContext(req, rec)
+
+	tests := map[string]string{
+		"index.html": "base index",
+		"users.html": "base admin users",
+		"bare.html":  "bare",
+	}
+
+	for name, expected := range tests {
+		output := bytes.NewBufferString("")
+		err = render.Render(output, name, nil, c)
+		assert.NoError(err)
+		assert.Equal(expected, output.String())
+	}
+}
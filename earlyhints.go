@@ -0,0 +1,182 @@
+package templates
+
+import (
+	"net/http"
+	"path"
+	"strings"
+	"text/template/parse"
+
+	"github.com/labstack/echo/v4"
+	"golang.org/x/net/html"
+)
+
+const earlyHintsSentKey = "templates.earlyHintsSent"
+
+// WithPreloadHeaders scans the layouts for stylesheet links and script elements when they are parsed,
+// adding a Link preload header for each to the responses rendering a page, so browsers start fetching
+// them sooner without any template changes. Only URLs written in the layouts are found, those produced
+// by actions such as {{asset "app.css"}} aren't known until the template is executed.
+func WithPreloadHeaders() Option {
+	return func(t *TemplateRenderer) {
+		t.preload = true
+	}
+}
+
+// WithEarlyHints adds the Link preload headers of WithPreloadHeaders, and also sends them in a 103 Early
+// Hints response before rendering the page, so browsers can fetch them while the page renders.
+func WithEarlyHints() Option {
+	return func(t *TemplateRenderer) {
+		t.preload = true
+		t.earlyHints = true
+	}
+}
+
+// sendPreloads adds the preload Link headers of the template to the response, sending them in a 103
+// Early Hints response first if enabled.
+func (t *TemplateRenderer) sendPreloads(c echo.Context, tmpl *Template) {
+	if tmpl.ensureParsed() != nil || len(tmpl.preloads) == 0 {
+		return
+	}
+
+	res := c.Response()
+	if res.Committed {
+		return
+	}
+
+	// a response composed from several renders using the same layouts preloads each asset once
+	sent := make(map[string]bool)
+	for _, link := range res.Header().Values("Link") {
+		sent[link] = true
+	}
+
+	for _, link := range tmpl.preloads {
+		if !sent[link] {
+			sent[link] = true
+			res.Header().Add("Link", link)
+		}
+	}
+
+	req := c.Request()
+	if !tmpl.earlyHints || !req.ProtoAtLeast(1, 1) || c.Get(earlyHintsSentKey) != nil {
+		return
+	}
+
+	c.Set(earlyHintsSentKey, true)
+
+	// written to the underlying writer as echo treats any status as committing the response
+	res.Writer.WriteHeader(http.StatusEarlyHints)
+}
+
+// preloadLinks returns the Link header values preloading the stylesheets and scripts referenced in the
+// text of the layouts, including the blocks they define, in the order they appear in the page.
+func preloadLinks(e engine, layouts []string) []string {
+	if len(layouts) == 0 {
+		return nil
+	}
+
+	files := make(map[string]bool, len(layouts))
+	for _, layout := range layouts {
+		files[path.Base(layout)] = true
+	}
+
+	var (
+		trees   = e.Trees()
+		links   []string
+		seen    = make(map[string]bool)
+		visited = make(map[string]bool)
+		visit   func(name string)
+	)
+
+	visit = func(name string) {
+		tree := trees[name]
+		if visited[name] || tree == nil || !files[tree.ParseName] {
+			return
+		}
+
+		visited[name] = true
+
+		walkNodes(tree.Root, func(node parse.Node) {
+			switch n := node.(type) {
+			case *parse.TextNode:
+				for _, link := range scanPreloads(string(n.Text)) {
+					if !seen[link] {
+						seen[link] = true
+						links = append(links, link)
+					}
+				}
+			case *parse.TemplateNode:
+				visit(n.Name)
+			}
+		})
+	}
+
+	visit(layoutName(layouts))
+
+	return links
+}
+
+// scanPreloads returns the Link header values preloading the stylesheets and scripts in the HTML, tags
+// which are incomplete because they contain an action are skipped.
+func scanPreloads(s string) []string {
+	var links []string
+
+	z := html.NewTokenizer(strings.NewReader(s))
+
+	for {
+		tt := z.Next()
+		if tt == html.ErrorToken {
+			return links
+		}
+
+		if tt != html.StartTagToken && tt != html.SelfClosingTagToken {
+			continue
+		}
+
+		tok := z.Token()
+
+		switch tok.Data {
+		case "link":
+			href := tokenAttr(tok, "href")
+			if validPreloadURL(href) && hasToken(tokenAttr(tok, "rel"), "stylesheet") {
+				links = append(links, "<"+href+">; rel=preload; as=style")
+			}
+		case "script":
+			src := tokenAttr(tok, "src")
+			if !validPreloadURL(src) {
+				continue
+			}
+
+			if strings.EqualFold(tokenAttr(tok, "type"), "module") {
+				links = append(links, "<"+src+">; rel=modulepreload")
+			} else {
+				links = append(links, "<"+src+">; rel=preload; as=script")
+			}
+		}
+	}
+}
+
+func tokenAttr(tok html.Token, key string) string {
+	for _, attr := range tok.Attr {
+		if attr.Key == key {
+			return strings.TrimSpace(attr.Val)
+		}
+	}
+
+	return ""
+}
+
+// hasToken reports whether the space separated list contains the token, ignoring case.
+func hasToken(list, token string) bool {
+	for _, s := range strings.Fields(list) {
+		if strings.EqualFold(s, token) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// validPreloadURL reports whether the URL can be written in a Link header.
+func validPreloadURL(u string) bool {
+	return u != "" && !strings.ContainsAny(u, "<>\"\r\n\t ")
+}
@@ -0,0 +1,138 @@
+package templates_test
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"testing/fstest"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/require"
+	templates "github.com/wolfeidau/echo-go-templates"
+)
+
+// hintsRecorder records the Link headers sent with each informational response.
+type hintsRecorder struct {
+	*httptest.ResponseRecorder
+	hints [][]string
+}
+
+func (r *hintsRecorder) WriteHeader(code int) {
+	if code == http.StatusEarlyHints {
+		r.hints = append(r.hints, append([]string(nil), r.Header().Values("Link")...))
+		return
+	}
+
+	r.ResponseRecorder.WriteHeader(code)
+}
+
+func earlyHintsFS() fstest.MapFS {
+	return fstest.MapFS{
+		"layout.html": {Data: []byte(`<html><head>
+<link rel="stylesheet" href="/css/app.css">
+<link rel="icon" href="/favicon.ico">
+<link rel="stylesheet" href="{{.CSS}}">
+<script type="module" src="/js/app.js"></script>
+{{block "head" .}}<script src="/js/default.js"></script>{{end}}
+</head><body>{{block "content" .}}{{end}}<script src="/js/app.js" type="module"></script></body></html>`)},
+		"pages/index.html": {Data: []byte(`{{define "content"}}<script src="/js/page.js"></script>index{{end}}`)},
+		"pages/about.html": {Data: []byte(`{{define "head"}}<script src="/js/about.js"></script>{{end}}{{define "content"}}about{{end}}`)},
+	}
+}
+
+func Test_WithPreloadHeaders(t *testing.T) {
+	assert := require.New(t)
+
+	render := templates.New(templates.WithPreloadHeaders())
+
+	err := render.AddWithLayout(earlyHintsFS(), "layout.html", "pages/*.html")
+	assert.NoError(err)
+
+	e := echo.New()
+	e.Renderer = render
+
+	rec := httptest.NewRecorder()
+	c := e.NewContext(httptest.NewRequest(http.MethodGet, "/", http.NoBody), rec)
+
+	err = c.Render(http.StatusOK, "index.html", map[string]string{"CSS": "/css/theme.css"})
+	assert.NoError(err)
+	assert.Equal([]string{
+		"</css/app.css>; rel=preload; as=style",
+		"</js/app.js>; rel=modulepreload",
+		"</js/default.js>; rel=preload; as=script",
+	}, rec.Header().Values("Link"))
+
+	// blocks overridden by the page replace those of the layout
+	rec = httptest.NewRecorder()
+	c = e.NewContext(httptest.NewRequest(http.MethodGet, "/", http.NoBody), rec)
+
+	err = c.Render(http.StatusOK, "about.html", nil)
+	assert.NoError(err)
+	assert.Equal([]string{
+		"</css/app.css>; rel=preload; as=style",
+		"</js/app.js>; rel=modulepreload",
+	}, rec.Header().Values("Link"))
+
+	// blocks rendered for partial updates don't add the headers
+	rec = httptest.NewRecorder()
+	c = e.NewContext(httptest.NewRequest(http.MethodGet, "/", http.NoBody), rec)
+
+	err = render.RenderBlock(rec, "index.html", "content", nil, c)
+	assert.NoError(err)
+	assert.Empty(rec.Header().Values("Link"))
+}
+
+func Test_WithEarlyHints(t *testing.T) {
+	assert := require.New(t)
+
+	render := templates.New(templates.WithEarlyHints(), templates.WithLazyParsing())
+
+	err := render.AddWithLayout(earlyHintsFS(), "layout.html", "pages/*.html")
+	assert.NoError(err)
+
+	e := echo.New()
+	e.Renderer = render
+
+	rec := &hintsRecorder{ResponseRecorder: httptest.NewRecorder()}
+	c := e.NewContext(httptest.NewRequest(http.MethodGet, "/", http.NoBody), rec)
+
+	err = c.Render(http.StatusOK, "index.html", nil)
+	assert.NoError(err)
+
+	err = c.Render(http.StatusOK, "index.html", nil)
+	assert.NoError(err)
+
+	// the hints are only sent once for each request
+	assert.Len(rec.hints, 1)
+	assert.Equal([]string{
+		"</css/app.css>; rel=preload; as=style",
+		"</js/app.js>; rel=modulepreload",
+		"</js/default.js>; rel=preload; as=script",
+	}, rec.hints[0])
+	assert.Equal(http.StatusOK, rec.Code)
+	assert.Contains(rec.Body.String(), "index")
+}
+
+func Test_WithPreloadHeaders_Composed(t *testing.T) {
+	assert := require.New(t)
+
+	render := templates.New(templates.WithPreloadHeaders())
+
+	err := render.AddWithLayout(earlyHintsFS(), "layout.html", "pages/*.html")
+	assert.NoError(err)
+
+	rec := httptest.NewRecorder()
+	c := echo.New().NewContext(httptest.NewRequest(http.MethodGet, "/", http.NoBody), rec)
+
+	for _, name := range []string{"index.html", "about.html", "index.html"} {
+		err = render.Render(new(bytes.Buffer), name, nil, c)
+		assert.NoError(err)
+	}
+
+	assert.Equal([]string{
+		"</css/app.css>; rel=preload; as=style",
+		"</js/app.js>; rel=modulepreload",
+		"</js/default.js>; rel=preload; as=script",
+	}, rec.Header().Values("Link"))
+}
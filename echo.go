@@ -0,0 +1,30 @@
+package templates
+
+import (
+	"fmt"
+	"html/template"
+
+	"github.com/labstack/echo/v4"
+)
+
+// AttachEcho sets the renderer as the echo.Echo renderer, and adds a url template function which
+// builds the path of a named route using echo.Echo.Reverse, so paths aren't hardcoded in templates:
+//
+//	e.GET("/users/:id", showUser).Name = "user"
+//
+//	<a href="{{url "user" .ID}}">profile</a>
+//
+// It must be called before templates using url are added.
+func (t *TemplateRenderer) AttachEcho(e *echo.Echo) {
+	t.addFuncs(template.FuncMap{"url": func(name string, params ...any) (string, error) {
+		for _, r := range e.Routes() {
+			if r.Name == name {
+				return e.Reverse(name, params...), nil
+			}
+		}
+
+		return "", fmt.Errorf("url: route not found: %#q", name)
+	}})
+
+	e.Renderer = t
+}
@@ -0,0 +1,70 @@
+package templates_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"testing/fstest"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/require"
+	templates "github.com/wolfeidau/echo-go-templates"
+)
+
+func Test_AttachEcho(t *testing.T) {
+	assert := require.New(t)
+
+	fsys := fstest.MapFS{
+		"links.html":  {Data: []byte(`<a href="{{url "user" .}}">user</a> <a href="{{url "home"}}">home</a>`)},
+		"broken.html": {Data: []byte(`{{url "missing"}}`)},
+	}
+
+	e := echo.New()
+
+	render := templates.New()
+	render.AttachEcho(e)
+
+	err := render.Add(fsys, "*.html")
+	assert.NoError(err)
+
+	e.GET("/", func(c echo.Context) error {
+		return c.Render(http.StatusOK, "links.html", 42)
+	}).Name = "home"
+	e.GET("/users/:id", func(c echo.Context) error {
+		return c.Render(http.StatusOK, "broken.html", nil)
+	}).Name = "user"
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", http.NoBody))
+
+	assert.Equal(http.StatusOK, rec.Code)
+	assert.Equal(`<a href="/users/42">user</a> <a href="/">home</a>`, rec.Body.String())
+
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/users/1", http.NoBody))
+
+	assert.Equal(http.StatusInternalServerError, rec.Code)
+}
+
+func Test_AttachEcho_LazyParsing(t *testing.T) {
+	assert := require.New(t)
+
+	fsys := fstest.MapFS{
+		"index.html": {Data: []byte(`hello {{.}}`)},
+	}
+
+	render := templates.New(templates.WithLazyParsing())
+
+	err := render.Add(fsys, "*.html")
+	assert.NoError(err)
+
+	done := make(chan error)
+	go func() {
+		_, err := render.RenderString("index.html", "world")
+		done <- err
+	}()
+
+	render.AttachEcho(echo.New())
+
+	assert.NoError(<-done)
+}
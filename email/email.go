@@ -0,0 +1,144 @@
+// Package email renders transactional emails using templates registered with a templates.TemplateRenderer.
+//
+// An email is an HTML template and an optional sibling text template with the same base name and a .txt
+// extension, the text template is registered using text/template so it isn't HTML escaped:
+//
+//	render := templates.New()
+//	err := render.AddWithLayout(views.Content, "emails/layout.html", "emails/*.html")
+//	...
+//	err = render.Group(templates.WithTextTemplates()).Add(views.Content, "emails/*.txt")
+//	...
+//	msg, err := email.New(render).Render("welcome.html", data)
+//
+// The subject is read from the "subject" key of the front matter of the HTML template, or by rendering
+// a block named "subject" defined in the text or HTML template.
+package email
+
+import (
+	"path"
+	"strings"
+
+	"github.com/pkg/errors"
+	templates "github.com/wolfeidau/echo-go-templates"
+)
+
+// SubjectBlock is the name of the block rendered to build the subject of an email.
+const SubjectBlock = "subject"
+
+// Message is a rendered email.
+type Message struct {
+	Subject string
+	HTML    string
+	Text    string // empty if there is no text template
+}
+
+// Transformer post-processes the rendered HTML body of an email, such as InlineCSS.
+type Transformer = templates.Transformer
+
+// Option configures a Renderer.
+type Option func(*Renderer)
+
+// WithTransformer adds a Transformer applied to the HTML body of each email, transformers are applied in
+// the order they are added.
+func WithTransformer(t Transformer) Option {
+	return func(r *Renderer) {
+		r.transformers = append(r.transformers, t)
+	}
+}
+
+// Renderer renders emails using the templates registered with a TemplateRenderer.
+type Renderer struct {
+	templates    *templates.TemplateRenderer
+	transformers []Transformer
+}
+
+// New creates a new Renderer using the templates registered with render.
+func New(render *templates.TemplateRenderer, opts ...Option) *Renderer {
+	r := &Renderer{templates: render}
+
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	return r
+}
+
+// Render renders the named HTML template and its sibling text template, if there is one, with the data.
+func (r *Renderer) Render(name string, data any) (*Message, error) {
+	htmlTmpl, ok := r.templates.Lookup(name)
+	if !ok {
+		return nil, errors.Errorf("email: no template named %#q", name)
+	}
+
+	msg := new(Message)
+
+	body, err := r.templates.RenderBytes(name, data)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to render html body of email %s", name)
+	}
+
+	for _, t := range r.transformers {
+		body, err = t.Transform(name, body)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to transform html body of email %s", name)
+		}
+	}
+
+	msg.HTML = string(body)
+
+	textName := TextName(name)
+
+	textTmpl, hasText := r.templates.Lookup(textName)
+	if hasText {
+		msg.Text, err = r.templates.RenderString(textName, data)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to render text body of email %s", name)
+		}
+	}
+
+	msg.Subject, err = r.subject(htmlTmpl, textTmpl, data)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to render subject of email %s", name)
+	}
+
+	return msg, nil
+}
+
+// subject returns the subject from the front matter, or renders the subject block preferring the text
+// template as it isn't HTML escaped.
+func (r *Renderer) subject(htmlTmpl, textTmpl *templates.Template, data any) (string, error) {
+	meta, _ := r.templates.Meta(htmlTmpl.Name())
+	if subject, ok := meta[SubjectBlock].(string); ok {
+		return subject, nil
+	}
+
+	for _, tmpl := range []*templates.Template{textTmpl, htmlTmpl} {
+		if tmpl == nil || !hasBlock(tmpl, SubjectBlock) {
+			continue
+		}
+
+		subject, err := r.templates.RenderString(tmpl.Name()+"#"+SubjectBlock, data)
+		if err != nil {
+			return "", err
+		}
+
+		return strings.TrimSpace(subject), nil
+	}
+
+	return "", nil
+}
+
+// TextName returns the name of the text template for the named HTML template.
+func TextName(name string) string {
+	return strings.TrimSuffix(name, path.Ext(name)) + ".txt"
+}
+
+func hasBlock(tmpl *templates.Template, block string) bool {
+	for _, name := range tmpl.Blocks() {
+		if name == block {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,69 @@
+package email_test
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/require"
+	templates "github.com/wolfeidau/echo-go-templates"
+	"github.com/wolfeidau/echo-go-templates/email"
+)
+
+func Test_Render(t *testing.T) {
+	assert := require.New(t)
+
+	fsys := fstest.MapFS{
+		"emails/layout.html":  {Data: []byte(`<html>{{block "content" .}}{{end}}</html>`)},
+		"emails/welcome.html": {Data: []byte(`{{define "content"}}<p>Welcome {{.Name}}</p>{{end}}`)},
+		"emails/welcome.txt":  {Data: []byte(`{{define "subject"}} Welcome {{.Name}} {{end}}Welcome {{.Name}}`)},
+		"emails/reset.html":   {Data: []byte("---\nsubject: Reset your password\n---\n{{define \"content\"}}<a href=\"{{.Link}}\">reset</a>{{end}}")},
+		"emails/notice.html":  {Data: []byte(`{{define "subject"}}Notice for {{.Name}}{{end}}{{define "content"}}notice{{end}}`)},
+	}
+
+	render := templates.New()
+
+	err := render.AddWithLayout(fsys, "emails/layout.html", "emails/*.html")
+	assert.NoError(err)
+
+	err = render.Group(templates.WithTextTemplates()).Add(fsys, "emails/*.txt")
+	assert.NoError(err)
+
+	mailer := email.New(render)
+
+	msg, err := mailer.Render("welcome.html", map[string]string{"Name": "O'Brien"})
+	assert.NoError(err)
+	assert.Equal(&email.Message{
+		Subject: "Welcome O'Brien",
+		HTML:    "<html><p>Welcome O&#39;Brien</p></html>",
+		Text:    "Welcome O'Brien",
+	}, msg)
+
+	msg, err = mailer.Render("reset.html", map[string]string{"Link": "https://example.com/reset"})
+	assert.NoError(err)
+	assert.Equal("Reset your password", msg.Subject)
+	assert.Empty(msg.Text)
+
+	msg, err = mailer.Render("notice.html", map[string]string{"Name": "mark"})
+	assert.NoError(err)
+	assert.Equal("Notice for mark", msg.Subject)
+
+	_, err = mailer.Render("missing.html", nil)
+	assert.EqualError(err, "email: no template named `missing.html`")
+}
+
+func Test_Render_WithTransformer(t *testing.T) {
+	assert := require.New(t)
+
+	fsys := fstest.MapFS{
+		"emails/welcome.html": {Data: []byte(`<html><head><style>p { color: red }</style></head><body><p>Welcome {{.Name}}</p></body></html>`)},
+	}
+
+	render := templates.New()
+
+	err := render.Add(fsys, "emails/*.html")
+	assert.NoError(err)
+
+	msg, err := email.New(render, email.WithTransformer(email.InlineCSS())).Render("welcome.html", map[string]string{"Name": "mark"})
+	assert.NoError(err)
+	assert.Equal(`<html><head></head><body><p style="color: red">Welcome mark</p></body></html>`, msg.HTML)
+}
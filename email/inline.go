@@ -0,0 +1,337 @@
+package email
+
+import (
+	"bytes"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"golang.org/x/net/html"
+)
+
+// InlineCSS returns a Transformer which copies the rules in <style> elements into the style attribute of
+// each matching element, as many email clients ignore style sheets.
+//
+// Selectors made up of type, class, id and universal selectors, optionally combined using the descendant
+// combinator, are inlined in order of specificity with existing style attributes taking precedence. Other
+// rules, such as @media queries and those using pseudo classes, are left in the <style> element.
+func InlineCSS() Transformer {
+	return cssInliner{}
+}
+
+type cssInliner struct{}
+
+type cssRule struct {
+	selector    []compoundSelector // descendant selectors, outermost first
+	specificity [3]int
+	order       int
+	decls       string
+}
+
+type compoundSelector struct {
+	tag     string
+	id      string
+	classes []string
+}
+
+func (cssInliner) Transform(name string, in []byte) ([]byte, error) {
+	doc, err := html.Parse(bytes.NewReader(in))
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to parse html of %s", name)
+	}
+
+	var rules []cssRule
+
+	for _, style := range findElements(doc, "style") {
+		if style.FirstChild == nil || style.FirstChild.Type != html.TextNode {
+			continue
+		}
+
+		inlined, retained := parseStyleSheet(style.FirstChild.Data, len(rules))
+		rules = append(rules, inlined...)
+
+		if strings.TrimSpace(retained) == "" {
+			style.Parent.RemoveChild(style)
+			continue
+		}
+
+		style.FirstChild.Data = retained
+	}
+
+	sort.SliceStable(rules, func(i, j int) bool {
+		a, b := rules[i].specificity, rules[j].specificity
+		if a != b {
+			return a[0] < b[0] || a[0] == b[0] && (a[1] < b[1] || a[1] == b[1] && a[2] < b[2])
+		}
+
+		return rules[i].order < rules[j].order
+	})
+
+	walk(doc, func(n *html.Node) {
+		if n.Type != html.ElementNode {
+			return
+		}
+
+		var decls []string
+
+		for _, rule := range rules {
+			if rule.matches(n) {
+				decls = append(decls, rule.decls)
+			}
+		}
+
+		if len(decls) == 0 {
+			return
+		}
+
+		for i, attr := range n.Attr {
+			if attr.Key == "style" {
+				n.Attr[i].Val = joinDecls(append(decls, attr.Val))
+				return
+			}
+		}
+
+		n.Attr = append(n.Attr, html.Attribute{Key: "style", Val: joinDecls(decls)})
+	})
+
+	buf := new(bytes.Buffer)
+
+	err = html.Render(buf, doc)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to render html of %s", name)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// parseStyleSheet returns the rules which can be inlined, and the text of the remaining rules.
+func parseStyleSheet(css string, order int) ([]cssRule, string) {
+	css = stripComments(css)
+
+	var (
+		rules    []cssRule
+		retained strings.Builder
+	)
+
+	for len(css) > 0 {
+		open := strings.IndexByte(css, '{')
+		if open < 0 {
+			break
+		}
+
+		end := matchingBrace(css, open)
+
+		next := end + 1
+		if next > len(css) {
+			next = len(css)
+		}
+
+		prelude := strings.TrimSpace(css[:open])
+		body := css[open+1 : end]
+		block := css[:next]
+		css = css[next:]
+
+		if strings.HasPrefix(prelude, "@") {
+			retained.WriteString(strings.TrimSpace(block) + "\n")
+			continue
+		}
+
+		var unsupported []string
+
+		for _, sel := range strings.Split(prelude, ",") {
+			sel = strings.TrimSpace(sel)
+
+			rule, ok := parseSelector(sel)
+			if !ok {
+				unsupported = append(unsupported, sel)
+				continue
+			}
+
+			rule.order = order
+			rule.decls = strings.TrimSpace(body)
+			rules = append(rules, rule)
+			order++
+		}
+
+		if len(unsupported) > 0 {
+			retained.WriteString(strings.Join(unsupported, ", ") + " {" + body + "}\n")
+		}
+	}
+
+	return rules, retained.String()
+}
+
+func parseSelector(sel string) (cssRule, bool) {
+	var rule cssRule
+
+	if sel == "" || strings.ContainsAny(sel, ":>+~[") {
+		return rule, false
+	}
+
+	for _, part := range strings.Fields(sel) {
+		var cs compoundSelector
+
+		for len(part) > 0 {
+			var kind byte
+
+			if part[0] == '.' || part[0] == '#' {
+				kind, part = part[0], part[1:]
+			}
+
+			end := strings.IndexAny(part, ".#")
+			if end < 0 {
+				end = len(part)
+			}
+
+			token := part[:end]
+			part = part[end:]
+
+			if token == "" {
+				return rule, false
+			}
+
+			switch kind {
+			case '.':
+				cs.classes = append(cs.classes, token)
+				rule.specificity[1]++
+			case '#':
+				cs.id = token
+				rule.specificity[0]++
+			default:
+				if token != "*" {
+					cs.tag = strings.ToLower(token)
+					rule.specificity[2]++
+				}
+			}
+		}
+
+		rule.selector = append(rule.selector, cs)
+	}
+
+	return rule, true
+}
+
+// matches reports whether the element matches the last compound selector, and its ancestors match
+// the preceding selectors in order.
+func (r cssRule) matches(n *html.Node) bool {
+	last := len(r.selector) - 1
+	if !r.selector[last].matches(n) {
+		return false
+	}
+
+	i := last - 1
+	for p := n.Parent; p != nil && i >= 0; p = p.Parent {
+		if p.Type == html.ElementNode && r.selector[i].matches(p) {
+			i--
+		}
+	}
+
+	return i < 0
+}
+
+func (cs compoundSelector) matches(n *html.Node) bool {
+	if cs.tag != "" && n.Data != cs.tag {
+		return false
+	}
+
+	if cs.id != "" && attr(n, "id") != cs.id {
+		return false
+	}
+
+	classes := strings.Fields(attr(n, "class"))
+
+	for _, want := range cs.classes {
+		found := false
+
+		for _, class := range classes {
+			if class == want {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			return false
+		}
+	}
+
+	return true
+}
+
+func attr(n *html.Node, key string) string {
+	for _, a := range n.Attr {
+		if a.Key == key {
+			return a.Val
+		}
+	}
+
+	return ""
+}
+
+func joinDecls(decls []string) string {
+	var parts []string
+
+	for _, d := range decls {
+		d = strings.TrimSuffix(strings.TrimSpace(d), ";")
+		if d != "" {
+			parts = append(parts, d)
+		}
+	}
+
+	return strings.Join(parts, "; ")
+}
+
+// matchingBrace returns the index of the brace closing the one at open, or the length of s if it isn't closed.
+func matchingBrace(s string, open int) int {
+	depth := 0
+
+	for i := open; i < len(s); i++ {
+		switch s[i] {
+		case '{':
+			depth++
+		case '}':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+
+	return len(s)
+}
+
+func stripComments(css string) string {
+	for {
+		start := strings.Index(css, "/*")
+		if start < 0 {
+			return css
+		}
+
+		end := strings.Index(css[start+2:], "*/")
+		if end < 0 {
+			return css[:start]
+		}
+
+		css = css[:start] + css[start+2+end+2:]
+	}
+}
+
+func findElements(n *html.Node, tag string) []*html.Node {
+	var found []*html.Node
+
+	walk(n, func(n *html.Node) {
+		if n.Type == html.ElementNode && n.Data == tag {
+			found = append(found, n)
+		}
+	})
+
+	return found
+}
+
+func walk(n *html.Node, fn func(*html.Node)) {
+	fn(n)
+
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		walk(c, fn)
+	}
+}
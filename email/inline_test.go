@@ -0,0 +1,47 @@
+package email_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/wolfeidau/echo-go-templates/email"
+)
+
+func Test_InlineCSS(t *testing.T) {
+	assert := require.New(t)
+
+	in := `<html><head><style>
+/* base styles */
+p { color: #333; margin: 0 }
+.note, #intro { font-size: 12px; }
+table td.cell { padding: 4px }
+p.note { color: red; }
+a:hover { color: blue }
+@media (max-width: 600px) { p { margin: 4px } }
+</style></head><body>
+<p id="intro">Hi</p>
+<p class="note" style="margin: 2px">Note</p>
+<table><tr><td class="cell">1</td><td>2</td></tr></table>
+<a href="/">link</a>
+</body></html>`
+
+	out, err := email.InlineCSS().Transform("welcome.html", []byte(in))
+	assert.NoError(err)
+
+	assert.Equal(`<html><head><style>a:hover { color: blue }
+@media (max-width: 600px) { p { margin: 4px } }
+</style></head><body>
+<p id="intro" style="color: #333; margin: 0; font-size: 12px">Hi</p>
+<p class="note" style="color: #333; margin: 0; font-size: 12px; color: red; margin: 2px">Note</p>
+<table><tbody><tr><td class="cell" style="padding: 4px">1</td><td>2</td></tr></tbody></table>
+<a href="/">link</a>
+</body></html>`, string(out))
+}
+
+func Test_InlineCSS_RemovesEmptyStyle(t *testing.T) {
+	assert := require.New(t)
+
+	out, err := email.InlineCSS().Transform("welcome.html", []byte(`<html><head><style>p{color:red}</style></head><body><p>Hi</p></body></html>`))
+	assert.NoError(err)
+	assert.Equal(`<html><head></head><body><p style="color:red">Hi</p></body></html>`, string(out))
+}
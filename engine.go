@@ -0,0 +1,120 @@
+package templates
+
+import (
+	htmltemplate "html/template"
+	"io"
+	"io/fs"
+	texttemplate "text/template"
+	"text/template/parse"
+)
+
+// engine is the subset of html/template and text/template used to parse and execute templates, this
+// allows templates producing plain text to share the registration and rendering of HTML templates.
+type engine interface {
+	ParseFS(fsys fs.FS, patterns ...string) error
+	Parse(text string) error
+	Funcs(funcs map[string]any)
+	Clone() (engine, error)
+	// New returns a new template associated with this one, sharing its definitions and functions.
+	New(name string) engine
+	ExecuteTemplate(w io.Writer, name string, data any) error
+	// Trees returns the parse tree of each template, keyed by name, the tree is nil for templates
+	// which are referenced but not defined.
+	Trees() map[string]*parse.Tree
+}
+
+func newEngine(text bool, name string, d delims, options []string) engine {
+	if text {
+		return &textEngine{texttemplate.New(name).Delims(d.left, d.right).Option(options...)}
+	}
+
+	return &htmlEngine{htmltemplate.New(name).Delims(d.left, d.right).Option(options...)}
+}
+
+type htmlEngine struct {
+	t *htmltemplate.Template
+}
+
+func (e *htmlEngine) ParseFS(fsys fs.FS, patterns ...string) error {
+	_, err := e.t.ParseFS(fsys, patterns...)
+	return err
+}
+
+func (e *htmlEngine) Parse(text string) error {
+	_, err := e.t.Parse(text)
+	return err
+}
+
+func (e *htmlEngine) Funcs(funcs map[string]any) {
+	e.t.Funcs(funcs)
+}
+
+func (e *htmlEngine) Clone() (engine, error) {
+	clone, err := e.t.Clone()
+	if err != nil {
+		return nil, err
+	}
+
+	return &htmlEngine{clone}, nil
+}
+
+func (e *htmlEngine) New(name string) engine {
+	return &htmlEngine{e.t.New(name)}
+}
+
+func (e *htmlEngine) ExecuteTemplate(w io.Writer, name string, data any) error {
+	return e.t.ExecuteTemplate(w, name, data)
+}
+
+func (e *htmlEngine) Trees() map[string]*parse.Tree {
+	trees := make(map[string]*parse.Tree)
+	for _, tt := range e.t.Templates() {
+		trees[tt.Name()] = tt.Tree
+	}
+
+	return trees
+}
+
+type textEngine struct {
+	t *texttemplate.Template
+}
+
+func (e *textEngine) ParseFS(fsys fs.FS, patterns ...string) error {
+	_, err := e.t.ParseFS(fsys, patterns...)
+	return err
+}
+
+func (e *textEngine) Parse(text string) error {
+	_, err := e.t.Parse(text)
+	return err
+}
+
+func (e *textEngine) Funcs(funcs map[string]any) {
+	e.t.Funcs(funcs)
+}
+
+func (e *textEngine) Clone() (engine, error) {
+	clone, err := e.t.Clone()
+	if err != nil {
+		return nil, err
+	}
+
+	return &textEngine{clone}, nil
+}
+
+func (e *textEngine) New(name string) engine {
+	return &textEngine{e.t.New(name)}
+}
+
+func (e *textEngine) ExecuteTemplate(w io.Writer, name string, data any) error {
+	return e.t.ExecuteTemplate(w, name, data)
+}
+
+func (e *textEngine) Trees() map[string]*parse.Tree {
+	trees := make(map[string]*parse.Tree)
+	for _, tt := range e.t.Templates() {
+		trees[tt.Name()] = tt.Tree
+	}
+
+	return trees
+}
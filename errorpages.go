@@ -0,0 +1,96 @@
+package templates
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+)
+
+// errorPagePrefix is prepended to the names of error page templates, so they don't collide with pages.
+const errorPagePrefix = "errors/"
+
+// ErrorPage is the data passed to error page templates by HTTPErrorHandler.
+type ErrorPage struct {
+	Code    int
+	Status  string // status text of the code, such as "Not Found"
+	Message string // message of an echo.HTTPError, or the status text
+	Path    string
+	Err     error // the error returned by the handler, take care not to display internal details
+}
+
+// RegisterErrorPages registers the error page templates rendered by HTTPErrorHandler, which are named by
+// status code such as 404.html and 500.html, with error.html rendering any status without a page:
+//
+//	err := render.RegisterErrorPages(views.Content, "layout.html", "errors/*.html")
+//	e.HTTPErrorHandler = render.HTTPErrorHandler
+//
+// An empty layout registers the pages without a layout.
+func (t *TemplateRenderer) RegisterErrorPages(fsys fs.FS, layout string, patterns ...string) error {
+	g := t.Group(WithNameFunc(Prefix(errorPagePrefix, BaseName)))
+
+	if layout == "" {
+		return g.Add(fsys, patterns...)
+	}
+
+	return g.AddWithLayout(fsys, layout, patterns...)
+}
+
+// HTTPErrorHandler is an echo.HTTPErrorHandler rendering the error page registered using RegisterErrorPages
+// for the status code, falling back to the error.html page and then Echo's default error handler.
+func (t *TemplateRenderer) HTTPErrorHandler(err error, c echo.Context) {
+	if c.Response().Committed {
+		return
+	}
+
+	page := ErrorPage{Code: http.StatusInternalServerError, Path: c.Request().URL.Path, Err: err}
+
+	var he *echo.HTTPError
+	if errors.As(err, &he) {
+		page.Code = he.Code
+		page.Message = fmt.Sprint(he.Message)
+
+		if he.Internal != nil {
+			page.Err = he.Internal
+		}
+	}
+
+	page.Status = http.StatusText(page.Code)
+	if page.Message == "" {
+		page.Message = page.Status
+	}
+
+	for _, name := range []string{strconv.Itoa(page.Code) + ".html", "error.html"} {
+		if _, ok := t.lookup(errorPagePrefix + name); !ok {
+			continue
+		}
+
+		if c.Request().Method == http.MethodHead {
+			t.logError(c, c.NoContent(page.Code))
+			return
+		}
+
+		buf := new(bytes.Buffer)
+
+		rerr := t.render(buf, errorPagePrefix+name, page, c)
+		if rerr != nil {
+			break
+		}
+
+		t.logError(c, c.HTMLBlob(page.Code, buf.Bytes()))
+
+		return
+	}
+
+	c.Echo().DefaultHTTPErrorHandler(err, c)
+}
+
+func (t *TemplateRenderer) logError(c echo.Context, err error) {
+	if err != nil {
+		t.ctxLog(c).Error().Err(err).Msg("write error page failed")
+	}
+}
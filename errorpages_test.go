@@ -0,0 +1,71 @@
+package templates_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"testing/fstest"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/require"
+	templates "github.com/wolfeidau/echo-go-templates"
+)
+
+func Test_HTTPErrorHandler(t *testing.T) {
+	assert := require.New(t)
+
+	fsys := fstest.MapFS{
+		"layout.html":       {Data: []byte(`<main>{{block "content" .}}{{end}}</main>`)},
+		"errors/404.html":   {Data: []byte(`{{define "content"}}missing {{.Path}}{{end}}`)},
+		"errors/error.html": {Data: []byte(`{{define "content"}}{{.Code}} {{.Status}}: {{.Message}}{{end}}`)},
+		"404.html":          {Data: []byte(`page named 404`)},
+	}
+
+	render := templates.New()
+
+	err := render.Add(fsys, "404.html")
+	assert.NoError(err)
+
+	err = render.RegisterErrorPages(fsys, "layout.html", "errors/*.html")
+	assert.NoError(err)
+
+	e := echo.New()
+	e.HTTPErrorHandler = render.HTTPErrorHandler
+	e.GET("/fail", func(c echo.Context) error { return errors.New("database is down") })
+	e.GET("/forbidden", func(c echo.Context) error { return echo.NewHTTPError(http.StatusForbidden, "no access") })
+
+	tests := []struct {
+		target string
+		code   int
+		body   string
+	}{
+		{"/nope", http.StatusNotFound, "<main>missing /nope</main>"},
+		{"/fail", http.StatusInternalServerError, "<main>500 Internal Server Error: Internal Server Error</main>"},
+		{"/forbidden", http.StatusForbidden, "<main>403 Forbidden: no access</main>"},
+	}
+
+	for _, tt := range tests {
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tt.target, nil))
+		assert.Equal(tt.code, rec.Code, tt.target)
+		assert.Equal(tt.body, rec.Body.String(), tt.target)
+		assert.Equal(echo.MIMETextHTMLCharsetUTF8, rec.Header().Get(echo.HeaderContentType))
+	}
+
+	s, err := render.RenderString("404.html", nil)
+	assert.NoError(err)
+	assert.Equal("page named 404", s)
+}
+
+func Test_HTTPErrorHandlerFallback(t *testing.T) {
+	assert := require.New(t)
+
+	e := echo.New()
+	e.HTTPErrorHandler = templates.New().HTTPErrorHandler
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/nope", nil))
+	assert.Equal(http.StatusNotFound, rec.Code)
+	assert.JSONEq(`{"message":"Not Found"}`, rec.Body.String())
+}
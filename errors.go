@@ -0,0 +1,140 @@
+package templates
+
+import (
+	"fmt"
+	"io/fs"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// errLocation matches the location html/template includes in execution errors, this is the base name of
+// the file followed by the line and column.
+var errLocation = regexp.MustCompile(`template: ([^:\s]+):(\d+):(?:\d+:)?`)
+
+// RenderError is returned when executing a template fails, it records the path of the file containing
+// the failing action, the line number and a snippet of the surrounding source.
+type RenderError struct {
+	Name    string // name of the template being rendered
+	Path    string // path of the file containing the error, empty if it couldn't be found
+	Line    int
+	Snippet string
+	Err     error
+}
+
+func (e *RenderError) Error() string {
+	if e.Path == "" {
+		return fmt.Sprintf("render template %s failed: %v", e.Name, e.Err)
+	}
+
+	return fmt.Sprintf("render template %s failed at %s:%d: %v", e.Name, e.Path, e.Line, e.Err)
+}
+
+func (e *RenderError) Unwrap() error {
+	return e.Err
+}
+
+// recoverPanic converts a panic while rendering the template into a RenderError, it must be deferred.
+// Panics in template functions are already converted to errors naming the function by html/template,
+// this covers the decorators, hooks and request scoped functions called outside of the template.
+func (tmpl *Template) recoverPanic(err *error) {
+	if r := recover(); r != nil {
+		*err = &RenderError{Name: tmpl.name, Err: fmt.Errorf("panic: %v", r)}
+	}
+}
+
+// renderError wraps an error returned executing the template with the location of the error.
+func (tmpl *Template) renderError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	rerr := &RenderError{Name: tmpl.name, Err: err}
+
+	m := errLocation.FindStringSubmatch(err.Error())
+	if m == nil {
+		return rerr
+	}
+
+	rerr.Line, _ = strconv.Atoi(m[2])
+
+	p, source, ok := tmpl.findSource(m[1])
+	if ok {
+		rerr.Path = p
+		rerr.Snippet = snippet(source, rerr.Line, 2)
+	}
+
+	return rerr
+}
+
+// findSource returns the path and source of the file parsed with the base name, files are searched in
+// the reverse of the order they are parsed as later definitions override earlier ones.
+func (tmpl *Template) findSource(base string) (string, []byte, bool) {
+	if base == tmpl.filename {
+		return tmpl.path, tmpl.source, true
+	}
+
+	sets := []fileSet{{fsys: tmpl.fsys, patterns: tmpl.includes}, {fsys: tmpl.fsys, patterns: tmpl.layoutFiles}}
+	for i := len(tmpl.shared) - 1; i >= 0; i-- {
+		sets = append(sets, tmpl.shared[i])
+	}
+
+	for _, set := range sets {
+		if len(set.patterns) == 0 {
+			continue
+		}
+
+		files, err := readFileNames(set.fsys, set.patterns...)
+		if err != nil {
+			continue
+		}
+
+		for i := len(files) - 1; i >= 0; i-- {
+			if path.Base(files[i]) != base {
+				continue
+			}
+
+			source, err := fs.ReadFile(set.fsys, files[i])
+			if err != nil {
+				return "", nil, false
+			}
+
+			return files[i], source, true
+		}
+	}
+
+	return "", nil, false
+}
+
+// snippet returns the lines of source around line, numbered with the line marked.
+func snippet(source []byte, line, context int) string {
+	lines := strings.Split(string(source), "\n")
+	if line < 1 || line > len(lines) {
+		return ""
+	}
+
+	first, last := line-context, line+context
+	if first < 1 {
+		first = 1
+	}
+
+	if last > len(lines) {
+		last = len(lines)
+	}
+
+	var sb strings.Builder
+
+	width := len(strconv.Itoa(last))
+
+	for n := first; n <= last; n++ {
+		marker := " "
+		if n == line {
+			marker = ">"
+		}
+
+		fmt.Fprintf(&sb, "%s %*d | %s\n", marker, width, n, lines[n-1])
+	}
+
+	return sb.String()
+}
@@ -0,0 +1,129 @@
+package templates_test
+
+import (
+	"bytes"
+	"errors"
+	"html/template"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"testing/fstest"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/require"
+	templates "github.com/wolfeidau/echo-go-templates"
+)
+
+func Test_RenderError(t *testing.T) {
+	assert := require.New(t)
+
+	fsys := fstest.MapFS{
+		"layouts/base.html":    {Data: []byte("<html>\n<body>\n{{block \"content\" .}}{{end}}\n{{template \"footer\" .}}\n</body>\n</html>")},
+		"includes/footer.html": {Data: []byte("{{define \"footer\"}}\n<footer>\n{{.Footer.Text}}\n</footer>\n{{end}}")},
+		"pages/index.html":     {Data: []byte("---\ntitle: home\n---\n{{define \"content\"}}\n{{.Missing.Title}}\n{{end}}")},
+	}
+
+	render := templates.New()
+
+	err := render.AddWithLayoutAndIncludes(fsys, "layouts/base.html", "includes/*.html", "pages/*.html")
+	assert.NoError(err)
+
+	e := echo.New()
+	c := e.NewContext(httptest.NewRequest(http.MethodGet, "/", http.NoBody), httptest.NewRecorder())
+
+	err = render.Render(new(bytes.Buffer), "index.html", map[string]any{"Missing": map[string]any{}, "Footer": 1}, c)
+	assert.Error(err)
+
+	var rerr *templates.RenderError
+	assert.True(errors.As(err, &rerr))
+	assert.Equal("index.html", rerr.Name)
+	assert.Equal("includes/footer.html", rerr.Path)
+	assert.Equal(3, rerr.Line)
+	assert.Equal("  1 | {{define \"footer\"}}\n  2 | <footer>\n> 3 | {{.Footer.Text}}\n  4 | </footer>\n  5 | {{end}}\n", rerr.Snippet)
+	assert.Contains(err.Error(), "render template index.html failed at includes/footer.html:3: ")
+
+	err = render.Render(new(bytes.Buffer), "index.html", map[string]any{"Missing": 1}, c)
+	assert.True(errors.As(err, &rerr))
+	assert.Equal("pages/index.html", rerr.Path)
+	assert.Equal(5, rerr.Line)
+	assert.Contains(rerr.Snippet, "> 5 | {{.Missing.Title}}")
+}
+
+func Test_RenderPanic(t *testing.T) {
+	assert := require.New(t)
+
+	fsys := fstest.MapFS{
+		"pages/func.html":  {Data: []byte(`<p>{{boom}}</p>`)},
+		"pages/index.html": {Data: []byte(`<p>{{.}}</p>`)},
+	}
+
+	render := templates.New(templates.WithFuncs(template.FuncMap{
+		"boom": func() string { panic("kaboom") },
+	}))
+
+	err := render.Add(fsys, "pages/*.html")
+	assert.NoError(err)
+
+	e := echo.New()
+	c := e.NewContext(httptest.NewRequest(http.MethodGet, "/", http.NoBody), httptest.NewRecorder())
+
+	var rerr *templates.RenderError
+
+	err = render.Render(new(bytes.Buffer), "func.html", nil, c)
+	assert.True(errors.As(err, &rerr))
+	assert.Equal("func.html", rerr.Name)
+	assert.Contains(err.Error(), "error calling boom: kaboom")
+
+	decorated := render.Group(templates.WithDataDecorator(func(c echo.Context, data any) any {
+		panic("bad decorator")
+	}))
+
+	err = decorated.Render(new(bytes.Buffer), "index.html", nil, c)
+	assert.True(errors.As(err, &rerr))
+	assert.Equal("index.html", rerr.Name)
+	assert.EqualError(err, "render template index.html failed: panic: bad decorator")
+}
+
+func Test_TemplateNotFound(t *testing.T) {
+	assert := require.New(t)
+
+	fsys := fstest.MapFS{
+		"index.html": {Data: []byte(`<p>{{.}}</p>`)},
+	}
+
+	render := templates.New()
+
+	err := render.Add(fsys, "*.html")
+	assert.NoError(err)
+
+	e := echo.New()
+	e.GET("/turbo", func(c echo.Context) error {
+		return render.TurboStreams().Append("list", "index.html", 1).Append("list", "missing.html", 2).Render(c, http.StatusOK)
+	})
+	e.GET("/oob", func(c echo.Context) error {
+		return render.HTMXResponse().Main("index.html", 1).OOB("missing.html", 2).Render(c, http.StatusOK)
+	})
+	e.GET("/negotiate", func(c echo.Context) error {
+		return render.Negotiate(c, http.StatusOK, "missing.html", nil)
+	})
+	e.GET("/handler", render.HandlerWith("missing.html", func(c echo.Context) (any, error) {
+		return nil, nil
+	}))
+	e.GET("/typed", func(c echo.Context) error {
+		return templates.Typed[string](render, "missing.html").RenderCode(c, http.StatusCreated, "")
+	})
+	e.GET("/serve", func(c echo.Context) error {
+		return render.Serve(c, http.StatusOK, "missing.html", nil)
+	})
+
+	// the helpers composing a response leave the error response to the error handler
+	for _, target := range []string{"/turbo", "/oob", "/negotiate", "/handler", "/typed", "/serve"} {
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, target, nil))
+		assert.Equal(http.StatusInternalServerError, rec.Code, target)
+		assert.Equal(`{"message":"Internal Server Error"}`+"\n", rec.Body.String(), target)
+	}
+
+	err = render.Render(new(bytes.Buffer), "missing.html", nil, nil)
+	assert.EqualError(err, "template: no template named `missing.html`")
+}
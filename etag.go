@@ -0,0 +1,105 @@
+package templates
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+// WithETag buffers the output of each render and sets a strong ETag response header computed from it.
+// When the If-None-Match header of a GET or HEAD request matches, the status is changed to 304 Not
+// Modified and the body discarded, so unchanged pages are cheap to revalidate. Responses composed from
+// several renders, such as HTMXResponse and TurboStreams, use an ETag computed from the complete body.
+func WithETag() Option {
+	return func(t *TemplateRenderer) {
+		t.etag = true
+	}
+}
+
+// withETag calls render with a buffer when ETags are enabled, setting the ETag header from the complete
+// output before writing it. If it matches the If-None-Match header of the request a 200 response is
+// changed to 304 Not Modified and the body discarded, responses with other statuses are unchanged.
+func (t *TemplateRenderer) withETag(w io.Writer, c echo.Context, render func(w io.Writer) error) error {
+	if !t.etag || c == nil {
+		return render(w)
+	}
+
+	buf := new(bytes.Buffer)
+
+	err := render(buf)
+	if err != nil {
+		return err
+	}
+
+	if notModified(c, buf.Bytes()) {
+		// echo writes the status passed to Render once the renderer returns, so it is replaced before
+		// the header is written rather than writing the response here
+		res := c.Response()
+		res.Before(func() {
+			if res.Status != http.StatusOK {
+				return
+			}
+
+			res.Status = http.StatusNotModified
+			res.Header().Del(echo.HeaderContentType)
+			res.Writer = discardBody{res.Writer}
+		})
+	}
+
+	_, err = buf.WriteTo(w)
+
+	return err
+}
+
+// discardBody discards the body of a 304 Not Modified response.
+type discardBody struct {
+	http.ResponseWriter
+}
+
+func (discardBody) Write(p []byte) (int, error) {
+	return len(p), nil
+}
+
+// blob writes a response composed from several renders, setting the ETag header from the complete body
+// when enabled and sending 304 Not Modified in place of a 200 response if it matches the request.
+func (t *TemplateRenderer) blob(c echo.Context, code int, contentType string, b []byte) error {
+	if t.etag && code == http.StatusOK && notModified(c, b) {
+		c.Response().Header().Del(echo.HeaderContentType)
+		return c.NoContent(http.StatusNotModified)
+	}
+
+	return c.Blob(code, contentType, b)
+}
+
+// notModified sets the ETag header for the output of a GET or HEAD request, returning true if it matches
+// the If-None-Match header of the request.
+func notModified(c echo.Context, out []byte) bool {
+	req := c.Request()
+	if req.Method != http.MethodGet && req.Method != http.MethodHead {
+		return false
+	}
+
+	sum := sha256.Sum256(out)
+	etag := `"` + hex.EncodeToString(sum[:16]) + `"`
+
+	c.Response().Header().Set("ETag", etag)
+
+	return etagMatch(req.Header.Get("If-None-Match"), etag)
+}
+
+// etagMatch reports whether the If-None-Match header matches the etag, using the weak comparison.
+func etagMatch(header, etag string) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,162 @@
+package templates_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"testing/fstest"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/require"
+	templates "github.com/wolfeidau/echo-go-templates"
+)
+
+func Test_WithETag(t *testing.T) {
+	assert := require.New(t)
+
+	fsys := fstest.MapFS{
+		"index.html": {Data: []byte(`<p>{{.}}</p>`)},
+	}
+
+	render := templates.New(templates.WithETag())
+
+	err := render.Add(fsys, "*.html")
+	assert.NoError(err)
+
+	e := echo.New()
+	e.Renderer = render
+	e.GET("/", func(c echo.Context) error {
+		return c.Render(http.StatusOK, "index.html", c.QueryParam("name"))
+	})
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/?name=a", nil))
+	assert.Equal(http.StatusOK, rec.Code)
+	assert.Equal("<p>a</p>", rec.Body.String())
+
+	etag := rec.Header().Get("ETag")
+	assert.Regexp(`^"[0-9a-f]{32}"$`, etag)
+
+	req := httptest.NewRequest(http.MethodGet, "/?name=a", nil)
+	req.Header.Set("If-None-Match", `"other", W/`+etag)
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	assert.Equal(http.StatusNotModified, rec.Code)
+	assert.Empty(rec.Body.String())
+	assert.Equal(etag, rec.Header().Get("ETag"))
+
+	req = httptest.NewRequest(http.MethodGet, "/?name=b", nil)
+	req.Header.Set("If-None-Match", etag)
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	assert.Equal(http.StatusOK, rec.Code)
+	assert.Equal("<p>b</p>", rec.Body.String())
+	assert.NotEqual(etag, rec.Header().Get("ETag"))
+}
+
+func Test_WithETag_Composite(t *testing.T) {
+	assert := require.New(t)
+
+	fsys := fstest.MapFS{
+		"index.html": {Data: []byte(`<p>{{.}}</p>`)},
+	}
+
+	render := templates.New(templates.WithETag())
+
+	err := render.Add(fsys, "*.html")
+	assert.NoError(err)
+
+	e := echo.New()
+	e.Renderer = render
+	e.GET("/", func(c echo.Context) error {
+		return c.Render(http.StatusOK, "index.html", 1)
+	})
+	e.GET("/oob", func(c echo.Context) error {
+		return render.HTMXResponse().Main("index.html", 1).OOB("index.html", 2).Render(c, http.StatusOK)
+	})
+	e.GET("/turbo", func(c echo.Context) error {
+		return render.TurboStreams().Append("list", "index.html", 1).Render(c, http.StatusOK)
+	})
+	e.GET("/negotiate", func(c echo.Context) error {
+		return render.Negotiate(c, http.StatusOK, "index.html", 1)
+	})
+	e.GET("/handler", render.Handler("index.html", 1))
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	fragmentETag := rec.Header().Get("ETag")
+
+	for _, target := range []string{"/oob", "/turbo", "/negotiate", "/handler"} {
+		rec = httptest.NewRecorder()
+		e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, target, nil))
+		assert.Equal(http.StatusOK, rec.Code, target)
+		assert.Contains(rec.Body.String(), "<p>1</p>", target)
+
+		etag := rec.Header().Get("ETag")
+		assert.Regexp(`^"[0-9a-f]{32}"$`, etag, target)
+
+		req := httptest.NewRequest(http.MethodGet, target, nil)
+		req.Header.Set("If-None-Match", etag)
+		rec = httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		assert.Equal(http.StatusNotModified, rec.Code, target)
+		assert.Empty(rec.Body.String(), target)
+		assert.Empty(rec.Header().Get(echo.HeaderContentType), target)
+	}
+
+	// the ETag of a fragment doesn't match the composed response
+	for _, target := range []string{"/oob", "/turbo"} {
+		req := httptest.NewRequest(http.MethodGet, target, nil)
+		req.Header.Set("If-None-Match", fragmentETag)
+		rec = httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		assert.Equal(http.StatusOK, rec.Code, target)
+		assert.Contains(rec.Body.String(), "<p>1</p>", target)
+		assert.NotEqual(fragmentETag, rec.Header().Get("ETag"), target)
+	}
+}
+
+func Test_WithETag_NotOK(t *testing.T) {
+	assert := require.New(t)
+
+	fsys := fstest.MapFS{
+		"index.html": {Data: []byte(`<p>{{.}}</p>`)},
+	}
+
+	render := templates.New(templates.WithETag())
+
+	err := render.Add(fsys, "*.html")
+	assert.NoError(err)
+
+	e := echo.New()
+	e.Renderer = render
+	e.GET("/", func(c echo.Context) error {
+		return c.Render(http.StatusOK, "index.html", "a")
+	})
+	e.GET("/missing", func(c echo.Context) error {
+		return c.Render(http.StatusNotFound, "index.html", "a")
+	})
+	e.GET("/invalid", func(c echo.Context) error {
+		return render.RenderWithOptions(c.Response(), "index.html", "a", c, templates.RenderStatus(http.StatusUnprocessableEntity))
+	})
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	etag := rec.Header().Get("ETag")
+
+	// a matching ETag doesn't replace the status of a response which isn't 200
+	for _, tt := range []struct {
+		target string
+		code   int
+	}{
+		{"/missing", http.StatusNotFound},
+		{"/invalid", http.StatusUnprocessableEntity},
+	} {
+		req := httptest.NewRequest(http.MethodGet, tt.target, nil)
+		req.Header.Set("If-None-Match", etag)
+		rec = httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		assert.Equal(tt.code, rec.Code, tt.target)
+		assert.Equal("<p>a</p>", rec.Body.String(), tt.target)
+	}
+}
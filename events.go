@@ -0,0 +1,92 @@
+package templates
+
+import (
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+// RenderEvent is a structured record of a completed render, see WithRenderEvents.
+type RenderEvent struct {
+	Time      time.Time     `json:"time"`
+	Name      string        `json:"name"`
+	Layout    string        `json:"layout,omitempty"`
+	Block     string        `json:"block,omitempty"`
+	Bytes     int64         `json:"bytes"`
+	Duration  time.Duration `json:"duration_ns"`
+	Error     string        `json:"error,omitempty"`
+	RequestID string        `json:"request_id,omitempty"`
+	Slow      bool          `json:"slow,omitempty"` // exceeded the WithSlowRenderThreshold duration
+}
+
+// WithRenderEvents calls fn with a RenderEvent once each render is complete, for example to ship them to
+// an analytics pipeline. The request ID is read from the X-Request-ID header set by the echo RequestID
+// middleware. fn is called on the request goroutine so it should return quickly.
+func WithRenderEvents(fn func(RenderEvent)) Option {
+	return func(t *TemplateRenderer) {
+		t.events = append(t.events, fn)
+	}
+}
+
+// WithRenderEventChannel sends a RenderEvent to ch once each render is complete, events are dropped
+// rather than blocking the render if ch is full:
+//
+//	events := make(chan templates.RenderEvent, 1000)
+//	render := templates.New(templates.WithRenderEventChannel(events))
+//
+//	go func() {
+//		for ev := range events {
+//			pipeline.Send(ev)
+//		}
+//	}()
+func WithRenderEventChannel(ch chan<- RenderEvent) Option {
+	return WithRenderEvents(func(ev RenderEvent) {
+		select {
+		case ch <- ev:
+		default:
+		}
+	})
+}
+
+// emitRenderEvent calls the render event callbacks with the completed render.
+func (t *TemplateRenderer) emitRenderEvent(c echo.Context, info RenderInfo) {
+	if len(t.events) == 0 {
+		return
+	}
+
+	ev := RenderEvent{
+		Time:     time.Now(),
+		Name:     info.Name,
+		Layout:   info.Layout,
+		Block:    info.Block,
+		Bytes:    info.Bytes,
+		Duration: info.Duration,
+		Slow:     t.isSlowRender(info.Duration),
+	}
+
+	if info.Err != nil {
+		ev.Error = info.Err.Error()
+	}
+
+	if c != nil {
+		ev.RequestID = requestID(c)
+	}
+
+	for _, fn := range t.events {
+		fn(ev)
+	}
+}
+
+// isSlowRender reports whether the render duration exceeds the threshold set using WithSlowRenderThreshold.
+func (t *TemplateRenderer) isSlowRender(d time.Duration) bool {
+	return t.slowRender > 0 && d > t.slowRender
+}
+
+// requestID returns the request ID set by the RequestID middleware, or sent by the client.
+func requestID(c echo.Context) string {
+	if id := c.Response().Header().Get(echo.HeaderXRequestID); id != "" {
+		return id
+	}
+
+	return c.Request().Header.Get(echo.HeaderXRequestID)
+}
@@ -0,0 +1,124 @@
+package templates_test
+
+import (
+	"bytes"
+	"html/template"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+	templates "github.com/wolfeidau/echo-go-templates"
+)
+
+func Test_WithRenderEvents(t *testing.T) {
+	assert := require.New(t)
+
+	fsys := fstest.MapFS{
+		"layout.html":       {Data: []byte(`<html>{{block "content" .}}{{end}}</html>`)},
+		"pages/index.html":  {Data: []byte(`{{define "content"}}index{{end}}`)},
+		"pages/broken.html": {Data: []byte(`{{define "content"}}{{.Missing.Field}}{{end}}`)},
+	}
+
+	var events []templates.RenderEvent
+
+	render := templates.New(templates.WithRenderEvents(func(ev templates.RenderEvent) {
+		events = append(events, ev)
+	}))
+
+	err := render.AddWithLayout(fsys, "layout.html", "pages/*.html")
+	assert.NoError(err)
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/", http.NoBody)
+	req.Header.Set(echo.HeaderXRequestID, "abc123")
+	c := e.NewContext(req, httptest.NewRecorder())
+
+	assert.NoError(render.Render(new(bytes.Buffer), "index.html", nil, c))
+	assert.Error(render.Render(new(bytes.Buffer), "broken.html", map[string]any{"Missing": 1}, c))
+
+	assert.Len(events, 2)
+
+	assert.Equal("index.html", events[0].Name)
+	assert.Equal("layout.html", events[0].Layout)
+	assert.Equal(int64(len("<html>index</html>")), events[0].Bytes)
+	assert.Equal("abc123", events[0].RequestID)
+	assert.Empty(events[0].Error)
+	assert.False(events[0].Time.IsZero())
+
+	assert.Equal("broken.html", events[1].Name)
+	assert.Contains(events[1].Error, "can't evaluate field Field")
+}
+
+func Test_WithRenderEventChannel(t *testing.T) {
+	assert := require.New(t)
+
+	events := make(chan templates.RenderEvent, 1)
+
+	render := templates.New(templates.WithRenderEventChannel(events))
+
+	err := render.AddFromString("index.html", "", "index")
+	assert.NoError(err)
+
+	e := echo.New()
+	rec := httptest.NewRecorder()
+	c := e.NewContext(httptest.NewRequest(http.MethodGet, "/", http.NoBody), rec)
+	c.Response().Header().Set(echo.HeaderXRequestID, "from-middleware")
+
+	// the second event is dropped as the channel is full
+	assert.NoError(render.Render(new(bytes.Buffer), "index.html", nil, c))
+	assert.NoError(render.Render(new(bytes.Buffer), "index.html", nil, c))
+
+	ev := <-events
+	assert.Equal("index.html", ev.Name)
+	assert.Equal("from-middleware", ev.RequestID)
+	assert.Empty(events)
+}
+
+func Test_WithSlowRenderThreshold(t *testing.T) {
+	assert := require.New(t)
+
+	type page struct{ Title string }
+
+	logs := new(bytes.Buffer)
+
+	var events []templates.RenderEvent
+
+	render := templates.New(
+		templates.WithLogger(zerolog.New(logs).Level(zerolog.WarnLevel)),
+		templates.WithSlowRenderThreshold(5*time.Millisecond),
+		templates.WithRenderEvents(func(ev templates.RenderEvent) {
+			events = append(events, ev)
+		}),
+		templates.WithFuncs(template.FuncMap{
+			"sleep": func(ms int) string {
+				time.Sleep(time.Duration(ms) * time.Millisecond)
+				return ""
+			},
+		}),
+	)
+
+	err := render.AddFromMap(map[string]string{
+		"fast.html": `{{.Title}}`,
+		"slow.html": `{{sleep 10}}{{.Title}}`,
+	})
+	assert.NoError(err)
+
+	_, err = render.RenderString("fast.html", page{Title: "fast"})
+	assert.NoError(err)
+	assert.NotContains(logs.String(), "slow render")
+
+	_, err = render.RenderString("slow.html", &page{Title: "slow"})
+	assert.NoError(err)
+	assert.Contains(logs.String(), `"level":"warn","name":"slow.html"`)
+	assert.Contains(logs.String(), `"data_type":"*templates_test.page"`)
+	assert.Contains(logs.String(), `"threshold":"5ms","message":"slow render"`)
+
+	assert.Len(events, 2)
+	assert.False(events[0].Slow)
+	assert.True(events[1].Slow)
+}
@@ -0,0 +1,44 @@
+package templates
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Expect registers the type of data the named template is rendered with, rendering it with data of
+// another type returns an error rather than producing output with missing values:
+//
+//	render.Expect("index.html", IndexData{})
+//
+// Pointers to the type are also accepted, as are values when a pointer type is expected. Blocks rendered
+// using RenderBlock, or the "#" suffix, aren't checked as they are typically passed part of the data.
+func (t *TemplateRenderer) Expect(name string, sample any) {
+	t.expected.Store(name, reflect.TypeOf(sample))
+}
+
+// checkExpected returns an error if the data isn't of the type registered for the template using Expect.
+func (t *TemplateRenderer) checkExpected(name string, data any) error {
+	v, ok := t.expected.Load(name)
+	if !ok {
+		return nil
+	}
+
+	expected := v.(reflect.Type)
+	actual := reflect.TypeOf(data)
+
+	if expected == nil || actual == expected {
+		return nil
+	}
+
+	if actual != nil {
+		if actual.Kind() == reflect.Pointer && actual.Elem() == expected {
+			return nil
+		}
+
+		if expected.Kind() == reflect.Pointer && expected.Elem() == actual {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("template: %s expects data of type %s, got %T", name, expected, data)
+}
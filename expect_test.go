@@ -0,0 +1,50 @@
+package templates_test
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/require"
+	templates "github.com/wolfeidau/echo-go-templates"
+)
+
+type indexData struct {
+	Title string
+}
+
+func Test_Expect(t *testing.T) {
+	assert := require.New(t)
+
+	fsys := fstest.MapFS{
+		"index.html": {Data: []byte(`{{.Title}}{{define "row"}}{{.}}{{end}}`)},
+	}
+
+	render := templates.New()
+
+	err := render.Add(fsys, "*.html")
+	assert.NoError(err)
+
+	render.Expect("index.html", indexData{})
+
+	s, err := render.RenderString("index.html", indexData{Title: "value"})
+	assert.NoError(err)
+	assert.Equal("value", s)
+
+	s, err = render.RenderString("index.html", &indexData{Title: "pointer"})
+	assert.NoError(err)
+	assert.Equal("pointer", s)
+
+	_, err = render.RenderString("index.html", map[string]any{"Title": "map"})
+	assert.EqualError(err, "template: index.html expects data of type templates_test.indexData, got map[string]interface {}")
+
+	_, err = render.RenderString("index.html", nil)
+	assert.EqualError(err, "template: index.html expects data of type templates_test.indexData, got <nil>")
+
+	s, err = render.RenderString("index.html#row", "row")
+	assert.NoError(err)
+	assert.Equal("row", s)
+
+	clone := render.Clone()
+	_, err = clone.RenderString("index.html", 1)
+	assert.Error(err)
+}
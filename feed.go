@@ -0,0 +1,110 @@
+package templates
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+// FeedFormat is the format of a feed rendered by RenderFeed.
+type FeedFormat int
+
+const (
+	// FeedRSS renders an RSS 2.0 feed.
+	FeedRSS FeedFormat = iota
+	// FeedAtom renders an Atom 1.0 feed.
+	FeedAtom
+)
+
+// Feed is a feed of items, such as blog posts, rendered as RSS or Atom by RenderFeed.
+type Feed struct {
+	Format      FeedFormat
+	Title       string
+	Link        string // URL of the site
+	FeedURL     string // URL of the feed itself
+	ID          string // defaults to the FeedURL or Link
+	Description string
+	Author      string
+	Updated     time.Time // defaults to the most recent item
+	Items       []FeedItem
+}
+
+// FeedItem is an item in a Feed.
+type FeedItem struct {
+	Title       string
+	Link        string
+	ID          string // defaults to the Link
+	Description string
+	Content     string // HTML content of the item, only rendered in Atom feeds
+	Author      string
+	Published   time.Time
+	Updated     time.Time // defaults to Published
+}
+
+// RenderFeed writes the feed to the response as RSS 2.0 or Atom, with values escaped and dates formatted
+// as required by the format:
+//
+//	return render.RenderFeed(c, templates.Feed{Title: "Blog", Link: "https://example.com/", Items: items})
+func (t *TemplateRenderer) RenderFeed(c echo.Context, feed Feed) error {
+	buf := new(bytes.Buffer)
+
+	err := WriteFeed(buf, feed)
+	if err != nil {
+		t.ctxLog(c).Error().Err(err).Str("title", feed.Title).Msg("render feed failed")
+		return err
+	}
+
+	contentType := "application/rss+xml; charset=UTF-8"
+	if feed.Format == FeedAtom {
+		contentType = "application/atom+xml; charset=UTF-8"
+	}
+
+	return c.Blob(http.StatusOK, contentType, buf.Bytes())
+}
+
+// WriteFeed writes the feed to w as RSS 2.0 or Atom, for example to generate a static file.
+func WriteFeed(w io.Writer, feed Feed) error {
+	name := "rss.xml"
+	if feed.Format == FeedAtom {
+		name = "atom.xml"
+	}
+
+	return executeBuiltin(w, name, withFeedDefaults(feed))
+}
+
+// withFeedDefaults returns a copy of the feed with the defaults of the optional fields applied.
+func withFeedDefaults(feed Feed) Feed {
+	if feed.ID == "" {
+		feed.ID = feed.FeedURL
+	}
+
+	if feed.ID == "" {
+		feed.ID = feed.Link
+	}
+
+	defaultUpdated := feed.Updated.IsZero()
+	items := make([]FeedItem, len(feed.Items))
+
+	for i, item := range feed.Items {
+		if item.ID == "" {
+			item.ID = item.Link
+		}
+
+		if item.Updated.IsZero() {
+			item.Updated = item.Published
+		}
+
+		if defaultUpdated && item.Updated.After(feed.Updated) {
+			feed.Updated = item.Updated
+		}
+
+		items[i] = item
+	}
+
+	feed.Items = items
+
+	return feed
+}
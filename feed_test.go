@@ -0,0 +1,100 @@
+package templates_test
+
+import (
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/require"
+	templates "github.com/wolfeidau/echo-go-templates"
+)
+
+func testFeed(format templates.FeedFormat) templates.Feed {
+	return templates.Feed{
+		Format:      format,
+		Title:       "Tom & Jerry's <blog>",
+		Link:        "https://example.com/",
+		FeedURL:     "https://example.com/feed.xml",
+		Description: "Posts",
+		Items: []templates.FeedItem{
+			{
+				Title:     "First & best",
+				Link:      "https://example.com/posts/1?a=1&b=2",
+				Content:   "<p>hello</p>",
+				Published: time.Date(2023, 5, 1, 10, 0, 0, 0, time.UTC),
+			},
+			{
+				Title:     "Second",
+				Link:      "https://example.com/posts/2",
+				ID:        "post-2",
+				Published: time.Date(2023, 6, 1, 10, 0, 0, 0, time.UTC),
+			},
+		},
+	}
+}
+
+func Test_RenderFeedRSS(t *testing.T) {
+	assert := require.New(t)
+
+	e := echo.New()
+	rec := httptest.NewRecorder()
+	c := e.NewContext(httptest.NewRequest(http.MethodGet, "/feed.xml", nil), rec)
+
+	err := templates.New().RenderFeed(c, testFeed(templates.FeedRSS))
+	assert.NoError(err)
+	assert.Equal("application/rss+xml; charset=UTF-8", rec.Header().Get(echo.HeaderContentType))
+
+	var rss struct {
+		Channel struct {
+			Title         string `xml:"title"`
+			LastBuildDate string `xml:"lastBuildDate"`
+			Items         []struct {
+				Title   string `xml:"title"`
+				Link    string `xml:"link"`
+				GUID    string `xml:"guid"`
+				PubDate string `xml:"pubDate"`
+			} `xml:"item"`
+		} `xml:"channel"`
+	}
+
+	err = xml.Unmarshal(rec.Body.Bytes(), &rss)
+	assert.NoError(err)
+	assert.Equal("Tom & Jerry's <blog>", rss.Channel.Title)
+	assert.Equal("Thu, 01 Jun 2023 10:00:00 +0000", rss.Channel.LastBuildDate)
+	assert.Len(rss.Channel.Items, 2)
+	assert.Equal("First & best", rss.Channel.Items[0].Title)
+	assert.Equal("https://example.com/posts/1?a=1&b=2", rss.Channel.Items[0].GUID)
+	assert.Equal("post-2", rss.Channel.Items[1].GUID)
+	assert.Contains(rec.Body.String(), `<guid isPermaLink="false">post-2</guid>`)
+}
+
+func Test_WriteFeedAtom(t *testing.T) {
+	assert := require.New(t)
+
+	buf := new(strings.Builder)
+
+	err := templates.WriteFeed(buf, testFeed(templates.FeedAtom))
+	assert.NoError(err)
+
+	var atom struct {
+		ID      string `xml:"id"`
+		Updated string `xml:"updated"`
+		Entries []struct {
+			ID      string `xml:"id"`
+			Updated string `xml:"updated"`
+			Content string `xml:"content"`
+		} `xml:"entry"`
+	}
+
+	err = xml.Unmarshal([]byte(buf.String()), &atom)
+	assert.NoError(err)
+	assert.Equal("https://example.com/feed.xml", atom.ID)
+	assert.Equal("2023-06-01T10:00:00Z", atom.Updated)
+	assert.Len(atom.Entries, 2)
+	assert.Equal("2023-05-01T10:00:00Z", atom.Entries[0].Updated)
+	assert.Equal("<p>hello</p>", atom.Entries[0].Content)
+}
@@ -0,0 +1,87 @@
+package templates
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"html/template"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
+)
+
+const (
+	flashCookieName = "_flash"
+	flashContextKey = "_flashes"
+	flashPendingKey = "_flashes_pending"
+)
+
+// FlashMessage is a one-time notice displayed on the next page rendered, typically after a redirect.
+type FlashMessage struct {
+	Level   string `json:"level"`
+	Message string `json:"message"`
+}
+
+// Flash adds a message which is shown on the next request, the messages are stored in a cookie so this
+// must be called before the response is written, for example before redirecting.
+func Flash(c echo.Context, level, message string) error {
+	pending, _ := c.Get(flashPendingKey).([]FlashMessage)
+	pending = append(pending, FlashMessage{Level: level, Message: message})
+
+	data, err := json.Marshal(pending)
+	if err != nil {
+		return errors.Wrap(err, "failed to encode flash messages")
+	}
+
+	c.Set(flashPendingKey, pending)
+	c.SetCookie(&http.Cookie{
+		Name:     flashCookieName,
+		Value:    base64.RawURLEncoding.EncodeToString(data),
+		Path:     "/",
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+	})
+
+	return nil
+}
+
+// Flashes returns the flash messages sent with the request and clears them, so they are only shown once.
+// Repeated calls during the same request return the same messages.
+func Flashes(c echo.Context) []FlashMessage {
+	if flashes, ok := c.Get(flashContextKey).([]FlashMessage); ok {
+		return flashes
+	}
+
+	var flashes []FlashMessage
+
+	cookie, err := c.Cookie(flashCookieName)
+	if err == nil {
+		c.SetCookie(&http.Cookie{Name: flashCookieName, Path: "/", MaxAge: -1, HttpOnly: true, SameSite: http.SameSiteLaxMode})
+
+		data, err := base64.RawURLEncoding.DecodeString(cookie.Value)
+		if err == nil {
+			_ = json.Unmarshal(data, &flashes)
+		}
+	}
+
+	c.Set(flashContextKey, flashes)
+
+	return flashes
+}
+
+// WithFlash adds a flashes template function which returns the flash messages for the request:
+//
+//	{{range flashes}}<div class="alert-{{.Level}}">{{.Message}}</div>{{end}}
+func WithFlash() Option {
+	return WithContextFuncs(func(c echo.Context) template.FuncMap {
+		return template.FuncMap{
+			"flashes": func() []FlashMessage {
+				if c == nil {
+					return nil
+				}
+
+				return Flashes(c)
+			},
+		}
+	})
+}
@@ -0,0 +1,56 @@
+package templates_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"testing/fstest"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/require"
+	templates "github.com/wolfeidau/echo-go-templates"
+)
+
+func Test_WithFlash(t *testing.T) {
+	assert := require.New(t)
+
+	fsys := fstest.MapFS{
+		"index.html": {Data: []byte(`{{range flashes}}<p class="{{.Level}}">{{.Message}}</p>{{end}}`)},
+	}
+
+	render := templates.New(templates.WithFlash())
+
+	err := render.Add(fsys, "*.html")
+	assert.NoError(err)
+
+	e := echo.New()
+	e.Renderer = render
+	e.POST("/save", func(c echo.Context) error {
+		assert.NoError(templates.Flash(c, "info", "saved"))
+		assert.NoError(templates.Flash(c, "warn", "<check> this"))
+		return c.Redirect(http.StatusSeeOther, "/")
+	})
+	e.GET("/", func(c echo.Context) error {
+		return c.Render(http.StatusOK, "index.html", nil)
+	})
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/save", http.NoBody))
+	assert.Equal(http.StatusSeeOther, rec.Code)
+
+	cookies := rec.Result().Cookies()
+	assert.Len(cookies, 2)
+
+	req := httptest.NewRequest(http.MethodGet, "/", http.NoBody)
+	req.AddCookie(cookies[1])
+
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	assert.Equal(`<p class="info">saved</p><p class="warn">&lt;check&gt; this</p>`, rec.Body.String())
+	assert.Equal(-1, rec.Result().Cookies()[0].MaxAge)
+
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", http.NoBody))
+	assert.Empty(rec.Body.String())
+}
@@ -0,0 +1,235 @@
+package templates
+
+import (
+	"fmt"
+	"html"
+	"html/template"
+	"net/url"
+	"reflect"
+	"strings"
+)
+
+// FormErrorClass is the class added to form controls which have errors.
+const FormErrorClass = "is-invalid"
+
+// Form holds the submitted values of a form and the validation errors of each field, it is passed to the
+// form template functions added by WithFormFuncs to repopulate values and render errors.
+type Form struct {
+	Values any                 // a struct, map[string]any or url.Values
+	Errors map[string][]string // validation errors keyed by field name
+}
+
+// NewForm returns a form with the values and the validation errors in err, see FormErrors.
+func NewForm(values any, err error) *Form {
+	return &Form{Values: values, Errors: FormErrors(err)}
+}
+
+// FormErrors returns the validation errors in err keyed by field name, it supports errors which are a
+// slice of field errors with Field and Error methods, such as validator.ValidationErrors from
+// go-playground/validator. Other errors are returned under the "" key.
+func FormErrors(err error) map[string][]string {
+	errs := make(map[string][]string)
+
+	if err == nil {
+		return errs
+	}
+
+	type fieldError interface {
+		Field() string
+		Error() string
+	}
+
+	v := reflect.ValueOf(err)
+	if v.Kind() == reflect.Slice {
+		for i := 0; i < v.Len(); i++ {
+			fe, ok := v.Index(i).Interface().(fieldError)
+			if !ok {
+				errs = make(map[string][]string)
+				break
+			}
+
+			errs[fe.Field()] = append(errs[fe.Field()], fe.Error())
+		}
+
+		if len(errs) > 0 {
+			return errs
+		}
+	}
+
+	errs[""] = []string{err.Error()}
+
+	return errs
+}
+
+// Value returns the submitted value of the field, struct fields are matched by their form tag or name. A
+// nil form has no values, so empty forms can be rendered using the same templates.
+func (f *Form) Value(field string) any {
+	if f == nil {
+		return nil
+	}
+
+	switch values := f.Values.(type) {
+	case nil:
+		return nil
+	case url.Values:
+		return values.Get(field)
+	case map[string]any:
+		return values[field]
+	case map[string]string:
+		return values[field]
+	}
+
+	v := reflect.Indirect(reflect.ValueOf(f.Values))
+	if v.Kind() != reflect.Struct {
+		return nil
+	}
+
+	for i := 0; i < v.NumField(); i++ {
+		sf := v.Type().Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+
+		name, _, _ := strings.Cut(sf.Tag.Get("form"), ",")
+		if name == field || (name == "" && sf.Name == field) {
+			return v.Field(i).Interface()
+		}
+	}
+
+	return nil
+}
+
+// HasErrors returns true if the field has validation errors, or any field if field is empty.
+func (f *Form) HasErrors(field string) bool {
+	if f == nil {
+		return false
+	}
+
+	if field == "" {
+		return len(f.Errors) > 0
+	}
+
+	return len(f.Errors[field]) > 0
+}
+
+// SelectOption is an option of a select rendered by the select template function, a string option
+// uses the same value and label.
+type SelectOption struct {
+	Value string
+	Label string
+}
+
+// WithFormFuncs adds template functions rendering form controls for a Form, each has the id and name of
+// the field, its submitted value, and the FormErrorClass and aria-invalid attributes if it has errors:
+//
+//	{{input .Form "email" "email"}}
+//	{{fieldErrors .Form "email"}}
+//	{{select .Form "country" .Countries}}
+//	{{checkbox .Form "subscribe"}}
+//
+// Errors are rendered as a list with the field-errors class.
+func WithFormFuncs() Option {
+	return WithFuncs(template.FuncMap{
+		"input":       formInput,
+		"select":      formSelect,
+		"checkbox":    formCheckbox,
+		"fieldErrors": formFieldErrors,
+	})
+}
+
+func formInput(f *Form, field, typ string) template.HTML {
+	value := ""
+	if v := f.Value(field); v != nil && typ != "password" {
+		value = fmt.Sprint(v)
+	}
+
+	return template.HTML(fmt.Sprintf(`<input type="%s" %s value="%s">`, //nolint:gosec // values are escaped
+		html.EscapeString(typ), formAttrs(f, field), html.EscapeString(value)))
+}
+
+func formSelect(f *Form, field string, options any) (template.HTML, error) {
+	opts, err := selectOptions(options)
+	if err != nil {
+		return "", err
+	}
+
+	selected := fmt.Sprint(f.Value(field))
+
+	buf := new(strings.Builder)
+	fmt.Fprintf(buf, `<select %s>`, formAttrs(f, field))
+
+	for _, o := range opts {
+		attr := ""
+		if o.Value == selected {
+			attr = " selected"
+		}
+
+		fmt.Fprintf(buf, `<option value="%s"%s>%s</option>`, html.EscapeString(o.Value), attr, html.EscapeString(o.Label))
+	}
+
+	buf.WriteString("</select>")
+
+	return template.HTML(buf.String()), nil //nolint:gosec // values are escaped
+}
+
+func formCheckbox(f *Form, field string) template.HTML {
+	attr := ""
+
+	switch v := f.Value(field).(type) {
+	case bool:
+		if v {
+			attr = " checked"
+		}
+	case string:
+		if v != "" && v != "false" && v != "0" {
+			attr = " checked"
+		}
+	}
+
+	return template.HTML(fmt.Sprintf(`<input type="checkbox" %s value="true"%s>`, formAttrs(f, field), attr)) //nolint:gosec // values are escaped
+}
+
+func formFieldErrors(f *Form, field string) template.HTML {
+	if !f.HasErrors(field) {
+		return ""
+	}
+
+	buf := new(strings.Builder)
+	fmt.Fprintf(buf, `<ul class="field-errors" id="%s-errors">`, html.EscapeString(field))
+
+	for _, msg := range f.Errors[field] {
+		fmt.Fprintf(buf, "<li>%s</li>", html.EscapeString(msg))
+	}
+
+	buf.WriteString("</ul>")
+
+	return template.HTML(buf.String()) //nolint:gosec // values are escaped
+}
+
+// formAttrs returns the id, name and error attributes of a form control.
+func formAttrs(f *Form, field string) string {
+	name := html.EscapeString(field)
+	attrs := fmt.Sprintf(`id="%s" name="%s"`, name, name)
+
+	if f.HasErrors(field) {
+		attrs += fmt.Sprintf(` class="%s" aria-invalid="true" aria-describedby="%s-errors"`, FormErrorClass, name)
+	}
+
+	return attrs
+}
+
+func selectOptions(options any) ([]SelectOption, error) {
+	switch opts := options.(type) {
+	case []SelectOption:
+		return opts, nil
+	case []string:
+		result := make([]SelectOption, len(opts))
+		for i, o := range opts {
+			result[i] = SelectOption{Value: o, Label: o}
+		}
+
+		return result, nil
+	default:
+		return nil, fmt.Errorf("select: unsupported options type %T", options)
+	}
+}
@@ -0,0 +1,69 @@
+package templates_test
+
+import (
+	"errors"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/require"
+	templates "github.com/wolfeidau/echo-go-templates"
+)
+
+type testFieldError struct {
+	field, msg string
+}
+
+func (e testFieldError) Field() string { return e.field }
+func (e testFieldError) Error() string { return e.msg }
+
+type testValidationErrors []testFieldError
+
+func (e testValidationErrors) Error() string { return "validation failed" }
+
+func Test_FormErrors(t *testing.T) {
+	assert := require.New(t)
+
+	errs := templates.FormErrors(testValidationErrors{{"email", "is required"}, {"email", "is invalid"}, {"age", "too young"}})
+	assert.Equal(map[string][]string{"email": {"is required", "is invalid"}, "age": {"too young"}}, errs)
+
+	assert.Equal(map[string][]string{"": {"failed"}}, templates.FormErrors(errors.New("failed")))
+	assert.Empty(templates.FormErrors(nil))
+}
+
+func Test_WithFormFuncs(t *testing.T) {
+	assert := require.New(t)
+
+	fsys := fstest.MapFS{
+		"signup.html": {Data: []byte(`{{input . "email" "email"}}{{fieldErrors . "email"}}` +
+			`{{input . "Password" "password"}}{{select . "country" .Values.Countries}}{{checkbox . "subscribe"}}{{fieldErrors . "country"}}`)},
+	}
+
+	render := templates.New(templates.WithFormFuncs())
+
+	err := render.Add(fsys, "*.html")
+	assert.NoError(err)
+
+	type signup struct {
+		Email     string `form:"email"`
+		Password  string
+		Country   string `form:"country"`
+		Subscribe bool   `form:"subscribe"`
+		Countries []string
+	}
+
+	form := templates.NewForm(signup{
+		Email:     `tom"@example`,
+		Password:  "secret",
+		Country:   "NZ",
+		Subscribe: true,
+		Countries: []string{"AU", "NZ"},
+	}, testValidationErrors{{"email", "is <invalid>"}})
+
+	s, err := render.RenderString("signup.html", form)
+	assert.NoError(err)
+	assert.Equal(`<input type="email" id="email" name="email" class="is-invalid" aria-invalid="true" aria-describedby="email-errors" value="tom&#34;@example">`+
+		`<ul class="field-errors" id="email-errors"><li>is &lt;invalid&gt;</li></ul>`+
+		`<input type="password" id="Password" name="Password" value="">`+
+		`<select id="country" name="country"><option value="AU">AU</option><option value="NZ" selected>NZ</option></select>`+
+		`<input type="checkbox" id="subscribe" name="subscribe" value="true" checked>`, s)
+}
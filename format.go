@@ -0,0 +1,121 @@
+package templates
+
+import (
+	"html/template"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
+	"golang.org/x/text/currency"
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+	"golang.org/x/text/number"
+)
+
+// defaultFormatLocale is used to format values when the locale of the request isn't known.
+const defaultFormatLocale = "en"
+
+// WithFormatFuncs adds the formatDate, formatNumber and formatCurrency template functions, which format
+// values for the locale of the request resolved using WithI18n, or English if it isn't configured:
+//
+//	{{formatDate .Created "long"}}     <!-- 2. März 2024 -->
+//	{{formatNumber .Total}}            <!-- 1.234.567,891 -->
+//	{{formatCurrency .Price "EUR"}}    <!-- € 1.234,50 -->
+//
+// Dates are formatted using the "short" or "long" style. Numbers and currencies are formatted using
+// golang.org/x/text, which places currency symbols before the amount in every locale.
+func WithFormatFuncs() Option {
+	return func(t *TemplateRenderer) {
+		WithContextFuncs(func(c echo.Context) template.FuncMap {
+			locale := func() string {
+				if l := t.Locale(c); l != "" {
+					return l
+				}
+
+				return defaultFormatLocale
+			}
+
+			return template.FuncMap{
+				"formatDate": func(tm time.Time, style string) (string, error) {
+					return formatDate(locale(), tm, style)
+				},
+				"formatNumber": func(v any) string {
+					return message.NewPrinter(language.Make(locale())).Sprint(number.Decimal(v))
+				},
+				"formatCurrency": func(amount any, code string) (string, error) {
+					unit, err := currency.ParseISO(code)
+					if err != nil {
+						return "", errors.Wrapf(err, "invalid currency %s", code)
+					}
+
+					return message.NewPrinter(language.Make(locale())).Sprint(currency.Symbol(unit.Amount(amount))), nil
+				},
+			}
+		})(t)
+	}
+}
+
+// dateLayouts are the layouts of each date style by locale, the English month names in the long layouts
+// are replaced by those in monthNames.
+var dateLayouts = map[string]map[string]string{
+	"short": {
+		"en":    "1/2/06",
+		"en-GB": "02/01/2006",
+		"de":    "02.01.06",
+		"es":    "2/1/06",
+		"fr":    "02/01/2006",
+		"it":    "02/01/06",
+		"ja":    "2006/01/02",
+		"nl":    "02-01-2006",
+		"pt":    "02/01/2006",
+	},
+	"long": {
+		"en":    "January 2, 2006",
+		"en-GB": "2 January 2006",
+		"de":    "2. January 2006",
+		"es":    "2 de January de 2006",
+		"fr":    "2 January 2006",
+		"it":    "2 January 2006",
+		"ja":    "2006年1月2日",
+		"nl":    "2 January 2006",
+		"pt":    "2 de January de 2006",
+	},
+}
+
+var monthNames = map[string][12]string{
+	"de": {"Januar", "Februar", "März", "April", "Mai", "Juni", "Juli", "August", "September", "Oktober", "November", "Dezember"},
+	"es": {"enero", "febrero", "marzo", "abril", "mayo", "junio", "julio", "agosto", "septiembre", "octubre", "noviembre", "diciembre"},
+	"fr": {"janvier", "février", "mars", "avril", "mai", "juin", "juillet", "août", "septembre", "octobre", "novembre", "décembre"},
+	"it": {"gennaio", "febbraio", "marzo", "aprile", "maggio", "giugno", "luglio", "agosto", "settembre", "ottobre", "novembre", "dicembre"},
+	"nl": {"januari", "februari", "maart", "april", "mei", "juni", "juli", "augustus", "september", "oktober", "november", "december"},
+	"pt": {"janeiro", "fevereiro", "março", "abril", "maio", "junho", "julho", "agosto", "setembro", "outubro", "novembro", "dezembro"},
+}
+
+// formatDate formats the time using the style for the locale, or the base language of the locale,
+// falling back to ISO 8601 for unsupported locales.
+func formatDate(locale string, tm time.Time, style string) (string, error) {
+	layouts, ok := dateLayouts[style]
+	if !ok {
+		return "", errors.Errorf("unknown date style %s", style)
+	}
+
+	lang, _, _ := strings.Cut(locale, "-")
+
+	layout, ok := layouts[locale]
+	if !ok {
+		layout, ok = layouts[lang]
+	}
+
+	if !ok {
+		return tm.Format("2006-01-02"), nil
+	}
+
+	s := tm.Format(layout)
+
+	if names, ok := monthNames[lang]; ok && strings.Contains(layout, "January") {
+		s = strings.Replace(s, tm.Month().String(), names[tm.Month()-1], 1)
+	}
+
+	return s, nil
+}
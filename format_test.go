@@ -0,0 +1,63 @@
+package templates_test
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/require"
+	templates "github.com/wolfeidau/echo-go-templates"
+)
+
+func Test_WithFormatFuncs(t *testing.T) {
+	assert := require.New(t)
+
+	fsys := fstest.MapFS{
+		"pages/index.html": {Data: []byte(`{{formatDate .Date "short"}}|{{formatDate .Date "long"}}|{{formatNumber .Number}}|{{formatCurrency .Price "EUR"}}`)},
+		"pages/bad.html":   {Data: []byte(`{{formatCurrency .Price "XX"}}`)},
+	}
+
+	cat, err := templates.LoadCatalog(fstest.MapFS{}, "en")
+	assert.NoError(err)
+
+	render := templates.New(
+		templates.WithI18n(templates.I18nConfig{Translator: cat, Locales: []string{"en", "en-GB", "de", "fr", "ja", "ko"}}),
+		templates.WithFormatFuncs(),
+	)
+
+	err = render.Add(fsys, "pages/*.html")
+	assert.NoError(err)
+
+	data := map[string]any{
+		"Date":   time.Date(2024, time.March, 2, 10, 0, 0, 0, time.UTC),
+		"Number": 1234567.891,
+		"Price":  1234.5,
+	}
+
+	e := echo.New()
+
+	for acceptLanguage, want := range map[string]string{
+		"en":    "3/2/24|March 2, 2024|1,234,567.891|€ 1,234.50",
+		"en-GB": "02/03/2024|2 March 2024|1,234,567.891|€ 1,234.50",
+		"de":    "02.03.24|2. März 2024|1.234.567,891|€ 1.234,50",
+		"fr":    "02/03/2024|2 mars 2024|1\u00a0234\u00a0567,891|€ 1\u00a0234,50",
+		"ja":    "2024/03/02|2024年3月2日|1,234,567.891|€ 1,234.50",
+		"ko":    "2024-03-02|2024-03-02|1,234,567.891|€ 1,234.50",
+	} {
+		req := httptest.NewRequest(http.MethodGet, "/", http.NoBody)
+		req.Header.Set("Accept-Language", acceptLanguage)
+
+		buf := new(bytes.Buffer)
+
+		err = render.Render(buf, "index.html", data, e.NewContext(req, httptest.NewRecorder()))
+		assert.NoError(err)
+		assert.Equal(want, buf.String(), acceptLanguage)
+	}
+
+	_, err = render.RenderString("bad.html", data)
+	assert.ErrorContains(err, "invalid currency XX")
+}
@@ -0,0 +1,80 @@
+package templates
+
+import (
+	"fmt"
+	"html/template"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// FragmentCache is an in-memory LRU cache of rendered template fragments, see WithFragmentCache.
+type FragmentCache struct {
+	entries *lru[template.HTML]
+}
+
+// NewFragmentCache returns a fragment cache holding up to size fragments, a size of zero is unbounded.
+func NewFragmentCache(size int) *FragmentCache {
+	return &FragmentCache{entries: newLRU[template.HTML](size)}
+}
+
+// Purge removes all the cached fragments, for example after the content they display has changed.
+func (fc *FragmentCache) Purge() {
+	fc.entries.purge()
+}
+
+// Len returns the number of cached fragments.
+func (fc *FragmentCache) Len() int {
+	return fc.entries.len()
+}
+
+// WithFragmentCache adds the cache template function which renders a registered template, storing the
+// output in fc keyed by the template name and key until the ttl expires:
+//
+//	{{cache "sidebar.html" "sidebar" "5m" .}}
+//
+// The ttl is a time.Duration or a string parsed by time.ParseDuration. Fragments are rendered without
+// the request context, as the output is shared by all requests, so the key must include anything in
+// the data which changes the output.
+func WithFragmentCache(fc *FragmentCache) Option {
+	return func(t *TemplateRenderer) {
+		t.templateFuncs["cache"] = func(name, key string, ttl any, data any) (template.HTML, error) {
+			d, err := toDuration(ttl)
+			if err != nil {
+				return "", err
+			}
+
+			cacheKey := name + "\x00" + key
+
+			if out, ok := fc.entries.get(cacheKey); ok {
+				return out, nil
+			}
+
+			out, err := t.executeNested(name, data)
+			if err != nil {
+				return "", err
+			}
+
+			fc.entries.set(cacheKey, template.HTML(out), d) //nolint:gosec // output of a template
+
+			return template.HTML(out), nil //nolint:gosec // output of a template
+		}
+	}
+}
+
+// toDuration converts a time.Duration, or a string parsed by time.ParseDuration, to a duration.
+func toDuration(v any) (time.Duration, error) {
+	switch d := v.(type) {
+	case time.Duration:
+		return d, nil
+	case string:
+		parsed, err := time.ParseDuration(d)
+		if err != nil {
+			return 0, errors.Wrapf(err, "invalid duration %q", d)
+		}
+
+		return parsed, nil
+	default:
+		return 0, fmt.Errorf("invalid duration type %T", v)
+	}
+}
@@ -0,0 +1,110 @@
+package templates_test
+
+import (
+	"html/template"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	templates "github.com/wolfeidau/echo-go-templates"
+)
+
+func Test_WithFragmentCache(t *testing.T) {
+	assert := require.New(t)
+
+	calls := 0
+
+	fsys := fstest.MapFS{
+		"sidebar.html": {Data: []byte(`<nav>{{count}} {{.}}</nav>`)},
+		"index.html":   {Data: []byte(`{{cache "sidebar.html" "main" "5m" "a"}}{{cache "sidebar.html" "main" "5m" "b"}}`)},
+		"expired.html": {Data: []byte(`{{cache "sidebar.html" "expired" "1ns" "c"}}`)},
+	}
+
+	fc := templates.NewFragmentCache(10)
+
+	render := templates.New(
+		templates.WithFuncs(template.FuncMap{"count": func() int { calls++; return calls }}),
+		templates.WithFragmentCache(fc),
+	)
+
+	err := render.Add(fsys, "*.html")
+	assert.NoError(err)
+
+	s, err := render.RenderString("index.html", nil)
+	assert.NoError(err)
+	assert.Equal("<nav>1 a</nav><nav>1 a</nav>", s)
+	assert.Equal(1, fc.Len())
+
+	s, err = render.RenderString("expired.html", nil)
+	assert.NoError(err)
+	assert.Equal("<nav>2 c</nav>", s)
+
+	time.Sleep(time.Millisecond)
+
+	s, err = render.RenderString("expired.html", nil)
+	assert.NoError(err)
+	assert.Equal("<nav>3 c</nav>", s)
+
+	fc.Purge()
+	assert.Equal(0, fc.Len())
+
+	s, err = render.RenderString("index.html", nil)
+	assert.NoError(err)
+	assert.Equal("<nav>4 a</nav><nav>4 a</nav>", s)
+}
+
+func Test_FragmentCacheEviction(t *testing.T) {
+	assert := require.New(t)
+
+	fsys := fstest.MapFS{
+		"item.html":  {Data: []byte(`{{.}}`)},
+		"index.html": {Data: []byte(`{{range .}}{{cache "item.html" . "1h" .}}{{end}}`)},
+		"bad.html":   {Data: []byte(`{{cache "item.html" "key" "soon" .}}`)},
+	}
+
+	fc := templates.NewFragmentCache(2)
+	render := templates.New(templates.WithFragmentCache(fc))
+
+	err := render.Add(fsys, "*.html")
+	assert.NoError(err)
+
+	_, err = render.RenderString("index.html", []string{"a", "b", "c"})
+	assert.NoError(err)
+	assert.Equal(2, fc.Len())
+
+	_, err = render.RenderString("bad.html", nil)
+	assert.ErrorContains(err, `invalid duration "soon"`)
+}
+
+func Test_WithFragmentCache_Nested(t *testing.T) {
+	assert := require.New(t)
+
+	fsys := fstest.MapFS{
+		"sidebar.html": {Data: []byte(`<nav>{{.}}</nav>`)},
+		"index.html":   {Data: []byte(`{{cache "sidebar.html" "main" "5m" "a"}}`)},
+	}
+
+	var rendered []string
+
+	render := templates.New(
+		templates.WithFragmentCache(templates.NewFragmentCache(10)),
+		templates.WithRenderEvents(func(ev templates.RenderEvent) {
+			rendered = append(rendered, ev.Name)
+		}),
+	)
+
+	err := render.Add(fsys, "*.html")
+	assert.NoError(err)
+
+	s, err := render.RenderString("index.html", nil)
+	assert.NoError(err)
+	assert.Equal("<nav>a</nav>", s)
+	assert.Equal([]string{"index.html"}, rendered)
+
+	for _, stats := range render.Stats() {
+		if stats.Name == "sidebar.html" {
+			assert.Zero(stats.Renders)
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package templates
+
+import (
+	"bytes"
+
+	"gopkg.in/yaml.v3"
+)
+
+var frontMatterDelim = []byte("---")
+
+// splitFrontMatter splits an optional YAML front matter block from the start of a template:
+//
+//	---
+//	title: About Us
+//	layout: layouts/base.html
+//	---
+//	{{define "content"}}...{{end}}
+//
+// The front matter is available using the meta template function, the Meta method, and as .Meta when
+// the data is wrapped using WithViewModel, and a layout key selects the layouts in the same way as a
+// layout directive. The returned body has the front matter
+// replaced with a template comment so line numbers in errors still match the file.
+func splitFrontMatter(source []byte, d delims) (map[string]any, []byte, error) {
+	lines, end := frontMatterLines(source)
+	if end < 0 {
+		return nil, source, nil
+	}
+
+	meta := make(map[string]any)
+
+	err := yaml.Unmarshal(bytes.Join(lines[1:end], nil), &meta)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	body := make([]byte, 0, len(source)+8)
+	body = append(body, d.leftOrDefault()+"/*\n"...)
+	for range lines[1:end] {
+		body = append(body, '\n')
+	}
+	body = append(body, "*/ -"+d.rightOrDefault()...)
+	body = append(body, bytes.TrimLeft(lines[end], "-")...)
+	body = append(body, bytes.Join(lines[end+1:], nil)...)
+
+	return meta, body, nil
+}
+
+// stripFrontMatter returns source with the front matter removed, for content which isn't a template.
+func stripFrontMatter(source []byte) []byte {
+	lines, end := frontMatterLines(source)
+	if end < 0 {
+		return source
+	}
+
+	return bytes.Join(lines[end+1:], nil)
+}
+
+// frontMatterLines splits source into lines, returning the index of the line closing the front matter,
+// or -1 if there is no front matter.
+func frontMatterLines(source []byte) ([][]byte, int) {
+	if !bytes.HasPrefix(source, frontMatterDelim) {
+		return nil, -1
+	}
+
+	lines := bytes.SplitAfter(source, []byte("\n"))
+	if len(lines) < 2 || !bytes.Equal(bytes.TrimSpace(lines[0]), frontMatterDelim) {
+		return nil, -1
+	}
+
+	for i := 1; i < len(lines); i++ {
+		if bytes.Equal(bytes.TrimSpace(lines[i]), frontMatterDelim) {
+			return lines, i
+		}
+	}
+
+	return nil, -1
+}
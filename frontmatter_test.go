@@ -0,0 +1,76 @@
+package templates_test
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"testing/fstest"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/require"
+	templates "github.com/wolfeidau/echo-go-templates"
+)
+
+func Test_FrontMatter(t *testing.T) {
+	assert := require.New(t)
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/", http.NoBody)
+	rec := httptest.NewRecorder()
+
+	fsys := fstest.MapFS{
+		"base.html":  {Data: []byte(`<title>{{meta "title"}}</title>{{block "content" .}}{{end}}`)},
+		"admin.html": {Data: []byte(`<h1>{{meta "title"}}</h1>{{block "content" .}}{{end}}`)},
+		"pages/about.html": {Data: []byte(`---
+title: About Us
+layout: admin.html
+cache: 60
+---
+{{define "content"}}about {{.}}{{end}}`)},
+		"pages/broken.html": {Data: []byte("---\ntitle: Broken\n---\n{{define \"content\"}}{{.Missing.Field}}{{end}}")},
+	}
+
+	render := templates.New()
+
+	err := render.AddWithLayout(fsys, "base.html", "pages/*.html")
+	assert.NoError(err)
+
+	meta, ok := render.Meta("about.html")
+	assert.True(ok)
+	assert.Equal(map[string]any{"title": "About Us", "layout": "admin.html", "cache": 60}, meta)
+
+	c := e.NewContext(req, rec)
+
+	output := bytes.NewBufferString("")
+	err = render.Render(output, "about.html", "data", c)
+	assert.NoError(err)
+	assert.Equal("<h1>About Us</h1>about data", output.String())
+
+	err = render.Render(output, "broken.html", map[string]any{"Missing": 1}, c)
+	assert.ErrorContains(err, "broken.html:4:")
+
+	_, ok = render.Meta("missing.html")
+	assert.False(ok)
+}
+
+func Test_FrontMatter_ViewModel(t *testing.T) {
+	assert := require.New(t)
+
+	fsys := fstest.MapFS{
+		"base.html": {Data: []byte(`<title>{{.Meta.title}}</title>{{block "content" .}}{{end}}`)},
+		"pages/about.html": {Data: []byte(`---
+title: About Us
+---
+{{define "content"}}{{.Meta.title}} {{.Data}}{{end}}`)},
+	}
+
+	render := templates.New(templates.WithViewModel())
+
+	err := render.AddWithLayout(fsys, "base.html", "pages/*.html")
+	assert.NoError(err)
+
+	s, err := render.RenderString("about.html", "data")
+	assert.NoError(err)
+	assert.Equal("<title>About Us</title>About Us data", s)
+}
@@ -0,0 +1,155 @@
+package templates
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"html"
+	"html/template"
+	"reflect"
+	"strings"
+)
+
+// CommonFuncs returns a set of commonly used template functions, which can be added to a renderer
+// using WithFuncs(templates.CommonFuncs()).
+//
+//	dict "key" value ...     build a map, useful for passing several values to a template
+//	default "fallback" value return fallback if value is empty
+//	coalesce values...       return the first value which isn't empty
+//	upper / lower            change the case of a string
+//	truncate 20 value        truncate a string to a number of characters, adding an ellipsis
+//	join ", " values         join the elements of a slice using a separator
+//	safeHTML value           mark a trusted string as safe HTML, this must never be used with user input
+//	json value               encode a value as JSON
+//	jsonScript "id" value    encode a value as JSON in a <script type="application/json"> element
+func CommonFuncs() template.FuncMap {
+	return template.FuncMap{
+		"dict":       dict,
+		"default":    defaultValue,
+		"coalesce":   coalesce,
+		"upper":      strings.ToUpper,
+		"lower":      strings.ToLower,
+		"truncate":   truncate,
+		"join":       join,
+		"safeHTML":   safeHTML,
+		"json":       toJSON,
+		"jsonScript": jsonScript,
+	}
+}
+
+func dict(pairs ...any) (map[string]any, error) {
+	if len(pairs)%2 != 0 {
+		return nil, fmt.Errorf("dict: odd number of arguments: %d", len(pairs))
+	}
+
+	m := make(map[string]any, len(pairs)/2)
+
+	for i := 0; i < len(pairs); i += 2 {
+		key, ok := pairs[i].(string)
+		if !ok {
+			return nil, fmt.Errorf("dict: key must be a string: %#v", pairs[i])
+		}
+
+		m[key] = pairs[i+1]
+	}
+
+	return m, nil
+}
+
+func defaultValue(fallback, value any) any {
+	if isEmpty(value) {
+		return fallback
+	}
+
+	return value
+}
+
+func coalesce(values ...any) any {
+	for _, v := range values {
+		if !isEmpty(v) {
+			return v
+		}
+	}
+
+	return nil
+}
+
+func truncate(length int, s string) string {
+	runes := []rune(s)
+	if len(runes) <= length {
+		return s
+	}
+
+	return string(runes[:length]) + "…"
+}
+
+func join(sep string, values any) (string, error) {
+	v := reflect.ValueOf(values)
+	if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+		return "", fmt.Errorf("join: expected a slice, got %T", values)
+	}
+
+	elems := make([]string, v.Len())
+	for i := range elems {
+		elems[i] = fmt.Sprint(v.Index(i).Interface())
+	}
+
+	return strings.Join(elems, sep), nil
+}
+
+func safeHTML(s string) template.HTML {
+	return template.HTML(s) //nolint:gosec // explicitly trusted by the template author
+}
+
+// toJSON encodes the value as JSON, json.Marshal escapes <, > and & so the result is safe to use
+// as a JavaScript value within a script element.
+func toJSON(v any) (template.JS, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+
+	return template.JS(data), nil //nolint:gosec // json.Marshal escapes HTML characters
+}
+
+// jsonScript encodes the value as JSON in a script element with the id, for passing initial state to
+// JavaScript which reads it using JSON.parse(document.getElementById(id).textContent). As json.Marshal
+// escapes <, > and &, the value can't close the element or open a comment.
+func jsonScript(id string, v any) (template.HTML, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+
+	return template.HTML(`<script type="application/json" id="` + html.EscapeString(id) + `">` + string(data) + `</script>`), nil //nolint:gosec // json.Marshal escapes HTML characters
+}
+
+// isEmpty returns true for nil and zero values, and empty strings, slices and maps.
+func isEmpty(value any) bool {
+	if value == nil {
+		return true
+	}
+
+	v := reflect.ValueOf(value)
+
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Pointer, reflect.Interface:
+		return v.IsNil()
+	default:
+		return v.IsZero()
+	}
+}
+
+// xmlEscape returns the XML escaped text of the value.
+func xmlEscape(v any) (string, error) {
+	buf := new(strings.Builder)
+
+	err := xml.EscapeText(buf, []byte(fmt.Sprint(v)))
+	if err != nil {
+		return "", err
+	}
+
+	return buf.String(), nil
+}
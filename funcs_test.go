@@ -0,0 +1,62 @@
+package templates_test
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"testing/fstest"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/require"
+	templates "github.com/wolfeidau/echo-go-templates"
+)
+
+func Test_CommonFuncs(t *testing.T) {
+	e := echo.New()
+
+	tests := []struct {
+		name     string
+		template string
+		data     any
+		expected string
+		err      string
+	}{
+		{name: "dict", template: `{{template "card" dict "Title" "hello" "Count" 2}}{{define "card"}}{{.Title}} {{.Count}}{{end}}`, expected: "hello 2"},
+		{name: "dict odd", template: `{{dict "Title"}}`, err: "dict: odd number of arguments: 1"},
+		{name: "default", template: `{{default "anon" .Name}} {{default "anon" .Other}}`, data: map[string]any{"Name": "", "Other": "mark"}, expected: "anon mark"},
+		{name: "coalesce", template: `{{coalesce .A .B .C}}`, data: map[string]any{"A": 0, "B": "", "C": "c"}, expected: "c"},
+		{name: "case", template: `{{upper "abc"}} {{lower "DEF"}}`, expected: "ABC def"},
+		{name: "truncate", template: `{{truncate 5 "hello world"}} {{"short" | truncate 10}}`, expected: "hello… short"},
+		{name: "join", template: `{{join ", " .}}`, data: []int{1, 2, 3}, expected: "1, 2, 3"},
+		{name: "safeHTML", template: `{{safeHTML "<b>bold</b>"}} {{"<b>escaped</b>"}}`, expected: "<b>bold</b> &lt;b&gt;escaped&lt;/b&gt;"},
+		{name: "json", template: `<script>var data = {{json .}};</script>`, data: map[string]int{"a": 1}, expected: `<script>var data = {"a":1};</script>`},
+		{name: "jsonScript", template: `{{jsonScript "state" .}}`, data: map[string]string{"bio": "</script><!--"}, expected: `<script type="application/json" id="state">{"bio":"\u003c/script\u003e\u003c!--"}</script>`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert := require.New(t)
+
+			fsys := fstest.MapFS{"test.html": {Data: []byte(tt.template)}}
+
+			render := templates.New(templates.WithFuncs(templates.CommonFuncs()))
+
+			err := render.Add(fsys, "test.html")
+			assert.NoError(err)
+
+			req := httptest.NewRequest(http.MethodGet, "/", http.NoBody)
+			c := e.NewContext(req, httptest.NewRecorder())
+
+			output := bytes.NewBufferString("")
+			err = render.Render(output, "test.html", tt.data, c)
+			if tt.err != "" {
+				assert.ErrorContains(err, tt.err)
+				return
+			}
+
+			assert.NoError(err)
+			assert.Equal(tt.expected, output.String())
+		})
+	}
+}
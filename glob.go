@@ -0,0 +1,92 @@
+package templates
+
+import (
+	"io/fs"
+	"path"
+	"strings"
+)
+
+// glob returns the names of all files matching the pattern, in addition to the syntax supported by
+// fs.Glob a "**" path segment matches zero or more directories, so "pages/**/*.html" matches html
+// files at any depth below pages.
+func glob(fsys fs.FS, pattern string) ([]string, error) {
+	if !strings.Contains(pattern, "**") {
+		return fs.Glob(fsys, pattern)
+	}
+
+	segments := strings.Split(pattern, "/")
+
+	// walk from the longest leading directory which contains no meta characters
+	var root []string
+	for _, segment := range segments {
+		if strings.ContainsAny(segment, `*?[\`) {
+			break
+		}
+		root = append(root, segment)
+	}
+
+	dir := "."
+	if len(root) > 0 {
+		dir = path.Join(root...)
+	}
+
+	if !exists(fsys, dir) {
+		return nil, nil
+	}
+
+	var matches []string
+
+	err := fs.WalkDir(fsys, dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if d.IsDir() {
+			return nil
+		}
+
+		ok, err := matchSegments(segments, strings.Split(p, "/"))
+		if err != nil {
+			return err
+		}
+
+		if ok {
+			matches = append(matches, p)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return matches, nil
+}
+
+func matchSegments(pattern, name []string) (bool, error) {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			for i := 0; i <= len(name); i++ {
+				ok, err := matchSegments(pattern[1:], name[i:])
+				if err != nil || ok {
+					return ok, err
+				}
+			}
+
+			return false, nil
+		}
+
+		if len(name) == 0 {
+			return false, nil
+		}
+
+		ok, err := path.Match(pattern[0], name[0])
+		if err != nil || !ok {
+			return false, err
+		}
+
+		pattern, name = pattern[1:], name[1:]
+	}
+
+	return len(name) == 0, nil
+}
@@ -0,0 +1,51 @@
+package templates_test
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"testing/fstest"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/require"
+	templates "github.com/wolfeidau/echo-go-templates"
+)
+
+func Test_RecursivePatterns(t *testing.T) {
+	assert := require.New(t)
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/", http.NoBody)
+	rec := httptest.NewRecorder()
+
+	fsys := fstest.MapFS{
+		"layout.html":                     {Data: []byte(`{{template "button" .}} {{block "content" .}}{{end}}`)},
+		"components/forms/button.html":    {Data: []byte(`{{define "button"}}button{{end}}`)},
+		"pages/index.html":                {Data: []byte(`{{define "content"}}index{{end}}`)},
+		"pages/admin/users/list.html":     {Data: []byte(`{{define "content"}}users{{end}}`)},
+		"pages/admin/users/list.html.bak": {Data: []byte(`backup`)},
+	}
+
+	render := templates.New(templates.WithNameFunc(templates.RelativePath))
+
+	err := render.AddWithLayoutAndIncludes(fsys, "layout.html", "components/**/*.html", "pages/**/*.html")
+	assert.NoError(err)
+
+	c := e.NewContext(req, rec)
+
+	tests := map[string]string{
+		"pages/index.html":            "button index",
+		"pages/admin/users/list.html": "button users",
+	}
+
+	for name, expected := range tests {
+		output := bytes.NewBufferString("")
+		err = render.Render(output, name, nil, c)
+		assert.NoError(err)
+		assert.Equal(expected, output.String())
+	}
+
+	err = render.Add(fsys, "missing/**/*.html")
+	assert.EqualError(err, "failed to list using file pattern: template: pattern matches no files: `missing/**/*.html`")
+}
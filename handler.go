@@ -0,0 +1,42 @@
+package templates
+
+import (
+	"bytes"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+// Handler returns a handler rendering the named template with the data, so simple pages don't need a
+// handler of their own:
+//
+//	e.GET("/about", render.Handler("about.html", nil))
+func (t *TemplateRenderer) Handler(name string, data any) echo.HandlerFunc {
+	return t.HandlerWith(name, func(echo.Context) (any, error) {
+		return data, nil
+	})
+}
+
+// HandlerWith returns a handler rendering the named template with the data returned by fn, errors
+// returned by fn are returned by the handler:
+//
+//	e.GET("/users", render.HandlerWith("users.html", func(c echo.Context) (any, error) {
+//		return store.ListUsers(c.Request().Context())
+//	}))
+func (t *TemplateRenderer) HandlerWith(name string, fn func(c echo.Context) (any, error)) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		data, err := fn(c)
+		if err != nil {
+			return err
+		}
+
+		buf := new(bytes.Buffer)
+
+		err = t.render(buf, name, data, c)
+		if err != nil {
+			return err
+		}
+
+		return t.blob(c, http.StatusOK, echo.MIMETextHTMLCharsetUTF8, buf.Bytes())
+	}
+}
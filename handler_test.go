@@ -0,0 +1,57 @@
+package templates_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"testing/fstest"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/require"
+	templates "github.com/wolfeidau/echo-go-templates"
+)
+
+func Test_Handler(t *testing.T) {
+	assert := require.New(t)
+
+	fsys := fstest.MapFS{
+		"about.html": {Data: []byte(`<p>{{.}}</p>`)},
+	}
+
+	render := templates.New()
+
+	err := render.Add(fsys, "*.html")
+	assert.NoError(err)
+
+	e := echo.New()
+	e.GET("/about", render.Handler("about.html", "static"))
+	e.GET("/users/:id", render.HandlerWith("about.html", func(c echo.Context) (any, error) {
+		if c.Param("id") == "0" {
+			return nil, echo.NewHTTPError(http.StatusNotFound, "no user")
+		}
+
+		return "user " + c.Param("id"), nil
+	}))
+	e.GET("/broken", render.HandlerWith("about.html", func(c echo.Context) (any, error) {
+		return nil, errors.New("failed")
+	}))
+
+	tests := []struct {
+		target string
+		code   int
+		body   string
+	}{
+		{"/about", http.StatusOK, "<p>static</p>"},
+		{"/users/1", http.StatusOK, "<p>user 1</p>"},
+		{"/users/0", http.StatusNotFound, `{"message":"no user"}` + "\n"},
+		{"/broken", http.StatusInternalServerError, `{"message":"Internal Server Error"}` + "\n"},
+	}
+
+	for _, tt := range tests {
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tt.target, nil))
+		assert.Equal(tt.code, rec.Code, tt.target)
+		assert.Equal(tt.body, rec.Body.String(), tt.target)
+	}
+}
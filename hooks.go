@@ -0,0 +1,24 @@
+package templates
+
+import (
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+// Hook is called before and after each template is rendered, for example to record metrics, modify
+// the data or log slow renders.
+type Hook interface {
+	// BeforeRender is called with the data passed to Render and returns the data used to render the
+	// template, returning an error aborts the render.
+	BeforeRender(c echo.Context, name string, data any) (any, error)
+	// AfterRender is called once the render is complete with its duration and the error if it failed.
+	AfterRender(c echo.Context, name string, dur time.Duration, err error)
+}
+
+// WithHook adds a Hook to the renderer, hooks are called in the order they are added.
+func WithHook(h Hook) Option {
+	return func(t *TemplateRenderer) {
+		t.hooks = append(t.hooks, h)
+	}
+}
@@ -0,0 +1,68 @@
+package templates_test
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/require"
+	templates "github.com/wolfeidau/echo-go-templates"
+)
+
+type recordingHook struct {
+	before []string
+	after  []string
+	errs   []error
+}
+
+func (h *recordingHook) BeforeRender(c echo.Context, name string, data any) (any, error) {
+	h.before = append(h.before, name)
+
+	if name == "denied.html" {
+		return nil, errors.New("denied")
+	}
+
+	return map[string]any{"Data": data, "Hooked": true}, nil
+}
+
+func (h *recordingHook) AfterRender(c echo.Context, name string, dur time.Duration, err error) {
+	h.after = append(h.after, name)
+	h.errs = append(h.errs, err)
+}
+
+func Test_WithHook(t *testing.T) {
+	assert := require.New(t)
+
+	fsys := fstest.MapFS{
+		"index.html":  {Data: []byte(`{{.Data}} {{.Hooked}}`)},
+		"denied.html": {Data: []byte(`denied`)},
+	}
+
+	hook := &recordingHook{}
+
+	render := templates.New(templates.WithHook(hook))
+
+	err := render.Add(fsys, "*.html")
+	assert.NoError(err)
+
+	e := echo.New()
+	c := e.NewContext(httptest.NewRequest(http.MethodGet, "/", http.NoBody), httptest.NewRecorder())
+
+	output := bytes.NewBufferString("")
+	err = render.Render(output, "index.html", "data", c)
+	assert.NoError(err)
+	assert.Equal("data true", output.String())
+
+	err = render.Render(output, "denied.html", nil, c)
+	assert.EqualError(err, "denied")
+
+	assert.Equal([]string{"index.html", "denied.html"}, hook.before)
+	assert.Equal([]string{"index.html", "denied.html"}, hook.after)
+	assert.NoError(hook.errs[0])
+	assert.EqualError(hook.errs[1], "denied")
+}
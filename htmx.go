@@ -0,0 +1,17 @@
+package templates
+
+import "net/http"
+
+const (
+	headerHXRequest        = "HX-Request"
+	headerHXBoosted        = "HX-Boosted"
+	headerHXHistoryRestore = "HX-History-Restore-Request"
+)
+
+// isHTMXPartial returns true if the request was made by HTMX to swap part of a page, boosted and
+// history restore requests expect the full page so they are excluded.
+func isHTMXPartial(r *http.Request) bool {
+	return r.Header.Get(headerHXRequest) == "true" &&
+		r.Header.Get(headerHXBoosted) != "true" &&
+		r.Header.Get(headerHXHistoryRestore) != "true"
+}
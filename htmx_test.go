@@ -0,0 +1,85 @@
+package templates_test
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/require"
+	templates "github.com/wolfeidau/echo-go-templates"
+	"github.com/wolfeidau/echo-go-templates/test/views"
+)
+
+func Test_WithHTMX(t *testing.T) {
+	assert := require.New(t)
+
+	e := echo.New()
+
+	render := templates.New(templates.WithHTMX("content"))
+
+	err := render.AddWithLayoutAndIncludes(views.Content, "layout.html", "includes/*.html", "pages/*.html")
+	assert.NoError(err)
+
+	tests := []struct {
+		name    string
+		headers map[string]string
+		expect  string
+	}{
+		{name: "full page", expect: `^header layout index \d{2}:\d{2}:\d{2} footer$`},
+		{name: "htmx", headers: map[string]string{"HX-Request": "true"}, expect: `^index \d{2}:\d{2}:\d{2} $`},
+		{name: "boosted", headers: map[string]string{"HX-Request": "true", "HX-Boosted": "true"}, expect: `^header layout`},
+		{name: "history restore", headers: map[string]string{"HX-Request": "true", "HX-History-Restore-Request": "true"}, expect: `^header layout`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", http.NoBody)
+			for k, v := range tt.headers {
+				req.Header.Set(k, v)
+			}
+			rec := httptest.NewRecorder()
+
+			output := bytes.NewBufferString("")
+			err := render.Render(output, "index.html", nil, e.NewContext(req, rec))
+			assert.NoError(err)
+			assert.Regexp(tt.expect, output.String())
+			assert.Equal("HX-Request", rec.Header().Get("Vary"))
+		})
+	}
+}
+
+func Test_WithHTMX_Vary(t *testing.T) {
+	assert := require.New(t)
+
+	e := echo.New()
+
+	render := templates.New(templates.WithHTMX("content"))
+
+	err := render.AddWithLayoutAndIncludes(views.Content, "layout.html", "includes/*.html", "pages/*.html")
+	assert.NoError(err)
+
+	req := httptest.NewRequest(http.MethodGet, "/", http.NoBody)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	c.Response().Header().Set(echo.HeaderVary, "Accept-Encoding, hx-request")
+
+	for i := 0; i < 2; i++ {
+		err = render.Render(new(bytes.Buffer), "index.html", nil, c)
+		assert.NoError(err)
+	}
+
+	assert.Equal([]string{"Accept-Encoding, hx-request"}, rec.Header().Values(echo.HeaderVary))
+
+	rec = httptest.NewRecorder()
+	c = e.NewContext(req, rec)
+
+	for i := 0; i < 2; i++ {
+		err = render.Render(new(bytes.Buffer), "index.html", nil, c)
+		assert.NoError(err)
+	}
+
+	assert.Equal([]string{"HX-Request"}, rec.Header().Values(echo.HeaderVary))
+}
@@ -0,0 +1,270 @@
+package templates
+
+import (
+	"fmt"
+	"html/template"
+	"io/fs"
+	"path"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
+	"golang.org/x/text/feature/plural"
+	"golang.org/x/text/language"
+	"gopkg.in/yaml.v3"
+)
+
+// LocaleContextKey is the default context key read by WithI18n to override the locale of a request, for
+// example set by middleware from a path prefix or cookie.
+const LocaleContextKey = "locale"
+
+// Translator translates messages for a locale, it is implemented by Catalog and can be implemented
+// using go-i18n or golang.org/x/text/message.
+type Translator interface {
+	// Translate returns the message for the key formatted with args.
+	Translate(locale, key string, args ...any) string
+	// TranslatePlural returns the plural form of the message for count, formatted with count followed by args.
+	TranslatePlural(locale, key string, count int, args ...any) string
+}
+
+// I18nConfig configures localization.
+type I18nConfig struct {
+	// Translator translates messages.
+	Translator Translator
+	// Locales are the supported locales, the first is used if no other matches the request.
+	Locales []string
+	// ContextKey is the context key which overrides the locale of a request, defaults to LocaleContextKey.
+	ContextKey string
+}
+
+// WithI18n adds the t, plural and locale template functions, which use the locale of each request. The
+// locale is read from the context key, or matched against the Accept-Language header of the request.
+//
+// Templates with a localized variant, named with the locale before the extension such as index.de.html,
+// render the variant for requests in that locale in place of the template.
+//
+//	<h1>{{t "welcome" .Name}}</h1>
+//	<p>{{plural "items" (len .Items)}}</p>
+//	<html lang="{{locale}}">
+func WithI18n(cfg I18nConfig) Option {
+	if cfg.ContextKey == "" {
+		cfg.ContextKey = LocaleContextKey
+	}
+
+	tags := make([]language.Tag, 0, len(cfg.Locales))
+	for _, l := range cfg.Locales {
+		tags = append(tags, language.Make(l))
+	}
+
+	matcher := language.NewMatcher(tags)
+
+	resolve := func(c echo.Context) string {
+		if len(cfg.Locales) == 0 {
+			return ""
+		}
+
+		if c == nil {
+			return cfg.Locales[0]
+		}
+
+		var prefs []string
+
+		if l, ok := c.Get(cfg.ContextKey).(string); ok && l != "" {
+			prefs = append(prefs, l)
+		}
+
+		prefs = append(prefs, c.Request().Header.Get("Accept-Language"))
+
+		_, index := language.MatchStrings(matcher, prefs...)
+
+		return cfg.Locales[index]
+	}
+
+	return func(t *TemplateRenderer) {
+		t.localeFunc = resolve
+
+		WithContextFuncs(func(c echo.Context) template.FuncMap {
+			return template.FuncMap{
+				"locale": func() string { return resolve(c) },
+				"t": func(key string, args ...any) string {
+					return cfg.Translator.Translate(resolve(c), key, args...)
+				},
+				"plural": func(key string, count int, args ...any) string {
+					return cfg.Translator.TranslatePlural(resolve(c), key, count, args...)
+				},
+			}
+		})(t)
+	}
+}
+
+// Locale returns the locale of the request resolved using the configuration passed to WithI18n, or an
+// empty string if localization isn't configured.
+func (t *TemplateRenderer) Locale(c echo.Context) string {
+	if t.localeFunc == nil {
+		return ""
+	}
+
+	return t.localeFunc(c)
+}
+
+// lookupLocalized returns the variant of the named template for the locale of the request, such as
+// index.de.html for index.html, falling back to the named template if there isn't one.
+func (t *TemplateRenderer) lookupLocalized(name string, c echo.Context) (*Template, bool) {
+	if t.localeFunc != nil {
+		for _, variant := range localizedNames(name, t.localeFunc(c)) {
+			if tmpl, ok := t.lookup(variant); ok {
+				return tmpl, true
+			}
+		}
+	}
+
+	return t.lookup(name)
+}
+
+// localizedNames returns the names of the variants of the template for the locale, most specific first,
+// so pt-BR returns index.pt-BR.html followed by index.pt.html.
+func localizedNames(name, locale string) []string {
+	if locale == "" {
+		return nil
+	}
+
+	ext := path.Ext(name)
+	base := strings.TrimSuffix(name, ext)
+
+	names := []string{base + "." + locale + ext}
+
+	if lang, _, ok := strings.Cut(locale, "-"); ok {
+		names = append(names, base+"."+lang+ext)
+	}
+
+	return names
+}
+
+// Catalog is a Translator using messages loaded from YAML or JSON files, one for each locale.
+type Catalog struct {
+	fallback string
+	messages map[string]map[string]Message // locale to key to message
+}
+
+// Message is a translated message, with a form for each plural category used by the locale. A message
+// without plural forms is written as a string, which is used as the other form:
+//
+//	welcome: Welcome %s
+//	items:
+//	  one: "%d item"
+//	  other: "%d items"
+type Message map[string]string
+
+// UnmarshalYAML decodes a message from either a string or a map of plural forms.
+func (m *Message) UnmarshalYAML(value *yaml.Node) error {
+	if value.Kind == yaml.ScalarNode {
+		*m = Message{"other": value.Value}
+		return nil
+	}
+
+	forms := make(map[string]string)
+
+	err := value.Decode(&forms)
+	if err != nil {
+		return err
+	}
+
+	*m = forms
+
+	return nil
+}
+
+// LoadCatalog loads the message files in fsys matching the patterns, the locale is the name of each file
+// without the extension, such as "en.yaml" or "pt-BR.json". Messages missing from a locale are looked up
+// in the fallback locale, and if missing from both the key is returned.
+func LoadCatalog(fsys fs.FS, fallback string, patterns ...string) (*Catalog, error) {
+	files, err := readFileNames(fsys, patterns...)
+	if err != nil {
+		return nil, err
+	}
+
+	cat := &Catalog{fallback: fallback, messages: make(map[string]map[string]Message)}
+
+	for _, f := range files {
+		data, err := fs.ReadFile(fsys, f)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to read messages %s", f)
+		}
+
+		messages := make(map[string]Message)
+
+		err = yaml.Unmarshal(data, &messages)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to parse messages %s", f)
+		}
+
+		locale := strings.TrimSuffix(path.Base(f), path.Ext(f))
+		cat.messages[locale] = messages
+	}
+
+	return cat, nil
+}
+
+// Translate returns the message for the key formatted with args.
+func (cat *Catalog) Translate(locale, key string, args ...any) string {
+	msg, _ := cat.lookup(locale, key)
+	if msg == nil {
+		return key
+	}
+
+	return formatMessage(msg["other"], args)
+}
+
+// TranslatePlural returns the plural form of the message for count, formatted with count followed by args.
+func (cat *Catalog) TranslatePlural(locale, key string, count int, args ...any) string {
+	msg, msgLocale := cat.lookup(locale, key)
+	if msg == nil {
+		return key
+	}
+
+	form, ok := msg[pluralForm(msgLocale, count)]
+	if !ok {
+		form = msg["other"]
+	}
+
+	return formatMessage(form, append([]any{count}, args...))
+}
+
+// lookup returns the message for the key, and the locale it was found in.
+func (cat *Catalog) lookup(locale, key string) (Message, string) {
+	for _, l := range []string{locale, cat.fallback} {
+		if msg, ok := cat.messages[l][key]; ok {
+			return msg, l
+		}
+	}
+
+	return nil, ""
+}
+
+var pluralForms = map[plural.Form]string{
+	plural.Other: "other",
+	plural.Zero:  "zero",
+	plural.One:   "one",
+	plural.Two:   "two",
+	plural.Few:   "few",
+	plural.Many:  "many",
+}
+
+// pluralForm returns the CLDR plural category of count in the locale.
+func pluralForm(locale string, count int) string {
+	n := count
+	if n < 0 {
+		n = -n
+	}
+
+	return pluralForms[plural.Cardinal.MatchPlural(language.Make(locale), n, 0, 0, 0, 0)]
+}
+
+// formatMessage formats the message with args, messages without verbs are returned unchanged.
+func formatMessage(msg string, args []any) string {
+	if len(args) == 0 || !strings.Contains(msg, "%") {
+		return msg
+	}
+
+	return fmt.Sprintf(msg, args...)
+}
@@ -0,0 +1,109 @@
+package templates_test
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"testing/fstest"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/require"
+	templates "github.com/wolfeidau/echo-go-templates"
+)
+
+func Test_WithI18n(t *testing.T) {
+	assert := require.New(t)
+
+	messages := fstest.MapFS{
+		"locales/en.yaml": {Data: []byte("welcome: Welcome %s\nitems:\n  one: \"%d item\"\n  other: \"%d items\"\nfooter: Thanks\n")},
+		"locales/fr.json": {Data: []byte(`{"welcome": "Bienvenue %s", "items": {"one": "%d article", "other": "%d articles"}}`)},
+	}
+
+	cat, err := templates.LoadCatalog(messages, "en", "locales/*")
+	assert.NoError(err)
+
+	fsys := fstest.MapFS{
+		"pages/index.html": {Data: []byte(`<html lang="{{locale}}">{{t "welcome" .Name}} {{plural "items" 0}} {{plural "items" 1}} {{plural "items" 2}} {{t "footer"}} {{t "missing"}}</html>`)},
+	}
+
+	render := templates.New(templates.WithI18n(templates.I18nConfig{Translator: cat, Locales: []string{"en", "fr"}}))
+
+	err = render.Add(fsys, "pages/*.html")
+	assert.NoError(err)
+
+	e := echo.New()
+	data := map[string]string{"Name": "Mark"}
+
+	tests := []struct {
+		name           string
+		acceptLanguage string
+		contextLocale  string
+		want           string
+	}{
+		{"default", "", "", `<html lang="en">Welcome Mark 0 items 1 item 2 items Thanks missing</html>`},
+		{"accept language", "fr-CA,fr;q=0.9,en;q=0.5", "", `<html lang="fr">Bienvenue Mark 0 article 1 article 2 articles Thanks missing</html>`},
+		{"context overrides", "fr", "en", `<html lang="en">Welcome Mark 0 items 1 item 2 items Thanks missing</html>`},
+		{"unsupported", "de", "", `<html lang="en">Welcome Mark 0 items 1 item 2 items Thanks missing</html>`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", http.NoBody)
+			if tt.acceptLanguage != "" {
+				req.Header.Set("Accept-Language", tt.acceptLanguage)
+			}
+
+			c := e.NewContext(req, httptest.NewRecorder())
+			if tt.contextLocale != "" {
+				c.Set(templates.LocaleContextKey, tt.contextLocale)
+			}
+
+			buf := new(bytes.Buffer)
+
+			err := render.Render(buf, "index.html", data, c)
+			require.NoError(t, err)
+			require.Equal(t, tt.want, buf.String())
+		})
+	}
+
+	s, err := render.RenderString("index.html", data)
+	assert.NoError(err)
+	assert.Equal(`<html lang="en">Welcome Mark 0 items 1 item 2 items Thanks missing</html>`, s)
+}
+
+func Test_WithI18n_Variants(t *testing.T) {
+	assert := require.New(t)
+
+	fsys := fstest.MapFS{
+		"layout.html":         {Data: []byte(`<html>{{block "content" .}}{{end}}</html>`)},
+		"pages/index.html":    {Data: []byte(`{{define "content"}}hello{{end}}`)},
+		"pages/index.de.html": {Data: []byte(`{{define "content"}}hallo{{end}}`)},
+		"pages/index.pt.html": {Data: []byte(`{{define "content"}}olá{{end}}`)},
+	}
+
+	cat, err := templates.LoadCatalog(fstest.MapFS{}, "en")
+	assert.NoError(err)
+
+	render := templates.New(templates.WithI18n(templates.I18nConfig{Translator: cat, Locales: []string{"en", "de", "pt-BR"}}))
+
+	err = render.AddWithLayout(fsys, "layout.html", "pages/*.html")
+	assert.NoError(err)
+
+	e := echo.New()
+
+	for acceptLanguage, want := range map[string]string{
+		"en":    "<html>hello</html>",
+		"de-AT": "<html>hallo</html>",
+		"pt-BR": "<html>olá</html>",
+	} {
+		req := httptest.NewRequest(http.MethodGet, "/", http.NoBody)
+		req.Header.Set("Accept-Language", acceptLanguage)
+
+		buf := new(bytes.Buffer)
+
+		err = render.Render(buf, "index.html", nil, e.NewContext(req, httptest.NewRecorder()))
+		assert.NoError(err)
+		assert.Equal(want, buf.String(), acceptLanguage)
+	}
+}
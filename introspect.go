@@ -0,0 +1,75 @@
+package templates
+
+import "sort"
+
+// Names returns the names of all the registered templates in sorted order.
+func (t *TemplateRenderer) Names() []string {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	return sortedNames(t.templates)
+}
+
+func sortedNames(templates map[string]*Template) []string {
+	names := make([]string, 0, len(templates))
+	for name := range templates {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+// Lookup returns the template registered with the name.
+func (t *TemplateRenderer) Lookup(name string) (*Template, bool) {
+	return t.lookup(name)
+}
+
+// Name returns the name the template is registered under.
+func (tmpl *Template) Name() string {
+	return tmpl.name
+}
+
+// Layout returns the name of the layout executed when rendering the template, which is empty if the
+// template doesn't use a layout.
+func (tmpl *Template) Layout() string {
+	return tmpl.layout
+}
+
+// Path returns the path of the template file in the file system it was registered from.
+func (tmpl *Template) Path() string {
+	return tmpl.path
+}
+
+// LayoutPaths returns the paths of the layout files, outermost first.
+func (tmpl *Template) LayoutPaths() []string {
+	return append([]string(nil), tmpl.layoutFiles...)
+}
+
+// IncludePatterns returns the include patterns the template was registered with, not including
+// those set using SetIncludes.
+func (tmpl *Template) IncludePatterns() []string {
+	return append([]string(nil), tmpl.includes...)
+}
+
+// Blocks returns the names of the templates defined in the template, its layouts and includes, in sorted
+// order. This includes one for each file along with those defined using {{define}} or {{block}} actions,
+// these are the blocks which can be rendered using RenderBlock. It returns nil if the template fails to parse.
+func (tmpl *Template) Blocks() []string {
+	if tmpl.ensureParsed() != nil {
+		return nil
+	}
+
+	var blocks []string
+
+	for name, tree := range tmpl.template.Trees() {
+		if tree != nil {
+			blocks = append(blocks, name)
+		}
+	}
+
+	sort.Strings(blocks)
+
+	return blocks
+}
@@ -0,0 +1,40 @@
+package templates_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	templates "github.com/wolfeidau/echo-go-templates"
+	"github.com/wolfeidau/echo-go-templates/test/views"
+)
+
+func Test_Introspection(t *testing.T) {
+	assert := require.New(t)
+
+	render := templates.New()
+
+	err := render.AddWithLayoutAndIncludes(views.Content, "layout.html", "includes/*.html", "pages/*.html")
+	assert.NoError(err)
+
+	err = render.Add(views.Content, "fragments/*.html")
+	assert.NoError(err)
+
+	assert.Equal([]string{"data.html", "index.html"}, render.Names())
+
+	tmpl, ok := render.Lookup("index.html")
+	assert.True(ok)
+	assert.Equal("index.html", tmpl.Name())
+	assert.Equal("layout.html", tmpl.Layout())
+	assert.Equal("pages/index.html", tmpl.Path())
+	assert.Equal([]string{"layout.html"}, tmpl.LayoutPaths())
+	assert.Equal([]string{"includes/*.html"}, tmpl.IncludePatterns())
+	assert.Equal([]string{"content", "footer", "footer.html", "header", "header.html", "index.html", "layout.html"}, tmpl.Blocks())
+
+	tmpl, ok = render.Lookup("data.html")
+	assert.True(ok)
+	assert.Empty(tmpl.Layout())
+	assert.Empty(tmpl.LayoutPaths())
+
+	_, ok = render.Lookup("missing.html")
+	assert.False(ok)
+}
@@ -0,0 +1,140 @@
+package templates
+
+import (
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// LoadConfig configures the directory conventions used by Load, empty fields use the defaults.
+type LoadConfig struct {
+	// LayoutsDir contains the layouts, defaults to "layouts".
+	LayoutsDir string
+	// PagesDir contains the pages, which are registered recursively, defaults to "pages".
+	PagesDir string
+	// PartialsDir contains the partials included in every page, defaults to "partials".
+	PartialsDir string
+	// Layout is the name of the layout file used for pages, defaults to "base.html".
+	Layout string
+	// Ext is the file extension of templates, defaults to ".html".
+	Ext string
+}
+
+func (cfg LoadConfig) withDefaults() LoadConfig {
+	if cfg.LayoutsDir == "" {
+		cfg.LayoutsDir = "layouts"
+	}
+	if cfg.PagesDir == "" {
+		cfg.PagesDir = "pages"
+	}
+	if cfg.PartialsDir == "" {
+		cfg.PartialsDir = "partials"
+	}
+	if cfg.Layout == "" {
+		cfg.Layout = "base.html"
+	}
+	if cfg.Ext == "" {
+		cfg.Ext = ".html"
+	}
+
+	return cfg
+}
+
+// Load registers all the pages in a conventional directory structure such as:
+//
+//	layouts
+//	  base.html
+//	  admin.html
+//	partials
+//	  header.html
+//	pages
+//	  index.html
+//	  admin
+//	    users.html
+//
+// Every page includes the partials, and uses the default layout unless it is in a sub directory
+// with the same name as a layout, in which case that layout is used, so admin/users.html above
+// uses admin.html. The partials and layouts directories are optional.
+func (t *TemplateRenderer) Load(fsys fs.FS, cfg LoadConfig) error {
+	cfg = cfg.withDefaults()
+	source := fsys
+	fsys = t.overlay(fsys)
+
+	var includes []string
+
+	partials := path.Join(cfg.PartialsDir, "*"+cfg.Ext)
+
+	matches, err := fs.Glob(fsys, partials)
+	if err != nil {
+		return errors.Wrap(err, "failed to list partials")
+	}
+
+	if len(matches) > 0 {
+		includes = append(includes, partials)
+	}
+
+	pages := make(map[string][]string)
+
+	err = fs.WalkDir(fsys, cfg.PagesDir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if d.IsDir() || path.Ext(p) != cfg.Ext {
+			return nil
+		}
+
+		layout := cfg.layoutFor(fsys, p)
+		pages[layout] = append(pages[layout], p)
+
+		return nil
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to walk pages")
+	}
+
+	layouts := make([]string, 0, len(pages))
+	for layout := range pages {
+		layouts = append(layouts, layout)
+	}
+
+	sort.Strings(layouts)
+
+	for _, layout := range layouts {
+		// the pages are registered by path, as their names may contain glob meta characters such as
+		// pages/[id].html
+		err = t.addFrom(func(*TemplateRenderer) fs.FS { return source }, layoutFiles(layout), includes, literal(pages[layout]))
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// layoutFor returns the layout for the page, which is empty if there are no layouts.
+func (cfg LoadConfig) layoutFor(fsys fs.FS, page string) string {
+	rel := strings.TrimPrefix(page, cfg.PagesDir+"/")
+
+	if dir, _, ok := strings.Cut(rel, "/"); ok {
+		section := path.Join(cfg.LayoutsDir, dir+cfg.Ext)
+		if exists(fsys, section) {
+			return section
+		}
+	}
+
+	layout := path.Join(cfg.LayoutsDir, cfg.Layout)
+	if exists(fsys, layout) {
+		return layout
+	}
+
+	return ""
+}
+
+func exists(fsys fs.FS, name string) bool {
+	_, err := fs.Stat(fsys, name)
+	return err == nil
+}
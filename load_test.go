@@ -0,0 +1,106 @@
+package templates_test
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"testing/fstest"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/require"
+	templates "github.com/wolfeidau/echo-go-templates"
+)
+
+func Test_Load(t *testing.T) {
+	assert := require.New(t)
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/", http.NoBody)
+	rec := httptest.NewRecorder()
+
+	fsys := fstest.MapFS{
+		"layouts/base.html":      {Data: []byte(`base {{template "header" .}}{{block "content" .}}{{end}}`)},
+		"layouts/admin.html":     {Data: []byte(`admin {{template "header" .}}{{block "content" .}}{{end}}`)},
+		"partials/header.html":   {Data: []byte(`{{define "header"}}header {{end}}`)},
+		"pages/index.html":       {Data: []byte(`{{define "content"}}index{{end}}`)},
+		"pages/admin/users.html": {Data: []byte(`{{define "content"}}users{{end}}`)},
+		"pages/docs/guide.html":  {Data: []byte(`{{define "content"}}guide{{end}}`)},
+	}
+
+	render := templates.New(templates.WithNameFunc(templates.RelativePath))
+
+	err := render.Load(fsys, templates.LoadConfig{})
+	assert.NoError(err)
+
+	c := e.NewContext(req, rec)
+
+	tests := map[string]string{
+		"pages/index.html":       "base header index",
+		"pages/admin/users.html": "admin header users",
+		"pages/docs/guide.html":  "base header guide",
+	}
+
+	for name, expected := range tests {
+		output := bytes.NewBufferString("")
+		err = render.Render(output, name, nil, c)
+		assert.NoError(err)
+		assert.Equal(expected, output.String())
+	}
+}
+
+func Test_Load_WithoutLayouts(t *testing.T) {
+	assert := require.New(t)
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/", http.NoBody)
+	rec := httptest.NewRecorder()
+
+	fsys := fstest.MapFS{
+		"views/data.txt": {Data: []byte(`data`)},
+	}
+
+	render := templates.New()
+
+	err := render.Load(fsys, templates.LoadConfig{PagesDir: "views", Ext: ".txt"})
+	assert.NoError(err)
+
+	output := bytes.NewBufferString("")
+	err = render.Render(output, "data.txt", nil, e.NewContext(req, rec))
+	assert.NoError(err)
+	assert.Equal("data", output.String())
+}
+
+func Test_Load_Overlay(t *testing.T) {
+	assert := require.New(t)
+
+	fsys := fstest.MapFS{
+		"layouts/base.html":  {Data: []byte(`base {{block "content" .}}{{end}}`)},
+		"pages/index.html":   {Data: []byte(`{{define "content"}}index{{end}}`)},
+		"pages/[id].html":    {Data: []byte(`{{define "content"}}item {{.}}{{end}}`)},
+		"pages/[id]/a.html":  {Data: []byte(`{{define "content"}}nested{{end}}`)},
+		"pages/plain/b.html": {Data: []byte(`{{define "content"}}plain{{end}}`)},
+	}
+
+	theme := fstest.MapFS{
+		"layouts/base.html": {Data: []byte(`theme {{block "content" .}}{{end}}`)},
+		"pages/themed.html": {Data: []byte(`{{define "content"}}themed{{end}}`)},
+	}
+
+	render := templates.New(templates.WithNameFunc(templates.RelativePath), templates.WithOverlay(theme))
+
+	err := render.Load(fsys, templates.LoadConfig{})
+	assert.NoError(err)
+	assert.Equal([]string{"pages/[id].html", "pages/[id]/a.html", "pages/index.html", "pages/plain/b.html", "pages/themed.html"}, render.Names())
+
+	s, err := render.RenderString("pages/[id].html", 42)
+	assert.NoError(err)
+	assert.Equal("theme item 42", s)
+
+	s, err = render.RenderString("pages/themed.html", nil)
+	assert.NoError(err)
+	assert.Equal("theme themed", s)
+
+	err = render.Reload()
+	assert.NoError(err)
+}
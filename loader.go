@@ -0,0 +1,223 @@
+package templates
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Loader loads templates from a source other than a file system, such as a database, S3 or a CMS.
+// Names are slash separated paths, such as "pages/index.html", which are matched by the patterns
+// passed to the Add methods.
+type Loader interface {
+	// List returns the templates available from the loader.
+	List() ([]Item, error)
+	// Read returns the source of the named template.
+	Read(name string) ([]byte, error)
+}
+
+// Item describes a template available from a Loader.
+type Item struct {
+	Name    string
+	Size    int64 // length of the source in bytes
+	ModTime time.Time
+}
+
+// LoaderFS returns a file system reading templates from the loader, so they can be registered using the
+// Add methods. The loader is called each time the templates are parsed, so calling Reload, or enabling
+// WithReload or AutoReload, picks up changes:
+//
+//	err := render.AddWithLayout(templates.LoaderFS(pgLoader), "layout.html", "pages/*.html")
+func LoaderFS(l Loader) fs.FS {
+	return loaderFS{l}
+}
+
+// AutoReload reloads the templates every interval until the context is cancelled, errors are logged and
+// the current templates left in place.
+func (t *TemplateRenderer) AutoReload(ctx context.Context, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := t.Reload(); err != nil {
+					t.defaultLog().Error().Err(err).Msg("reload templates failed")
+				}
+			}
+		}
+	}()
+}
+
+type loaderFS struct {
+	l Loader
+}
+
+func (f loaderFS) Open(name string) (fs.File, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrInvalid}
+	}
+
+	items, err := f.l.List()
+	if err != nil {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: err}
+	}
+
+	for _, item := range items {
+		if item.Name != name {
+			continue
+		}
+
+		data, err := f.l.Read(name)
+		if err != nil {
+			return nil, &fs.PathError{Op: "open", Path: name, Err: err}
+		}
+
+		info := loaderInfo{name: path.Base(name), size: int64(len(data)), modTime: item.ModTime}
+
+		return &loaderFile{info: info, r: bytes.NewReader(data)}, nil
+	}
+
+	entries, ok := dirEntries(items, name)
+	if !ok {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+	}
+
+	return &loaderFile{info: loaderInfo{name: path.Base(name), dir: true}, entries: entries}, nil
+}
+
+func (f loaderFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	items, err := f.l.List()
+	if err != nil {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: err}
+	}
+
+	entries, ok := dirEntries(items, name)
+	if !ok {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: fs.ErrNotExist}
+	}
+
+	return entries, nil
+}
+
+func (f loaderFS) ReadFile(name string) ([]byte, error) {
+	data, err := f.l.Read(name)
+	if err != nil {
+		return nil, &fs.PathError{Op: "read", Path: name, Err: errors.Wrap(err, "failed to read from loader")}
+	}
+
+	return data, nil
+}
+
+// dirEntries returns the entries of the directory, made up of the items in it and the directories
+// containing items below it.
+func dirEntries(items []Item, dir string) ([]fs.DirEntry, bool) {
+	prefix := ""
+	if dir != "." {
+		prefix = dir + "/"
+	}
+
+	var (
+		entries []fs.DirEntry
+		seen    = make(map[string]bool)
+		found   = dir == "."
+	)
+
+	for _, item := range items {
+		rest, ok := strings.CutPrefix(item.Name, prefix)
+		if !ok || rest == "" {
+			continue
+		}
+
+		found = true
+
+		child, _, isDir := strings.Cut(rest, "/")
+		if seen[child] {
+			continue
+		}
+
+		seen[child] = true
+
+		info := loaderInfo{name: child, dir: true}
+		if !isDir {
+			info = loaderInfo{name: child, size: item.Size, modTime: item.ModTime}
+		}
+
+		entries = append(entries, fs.FileInfoToDirEntry(info))
+	}
+
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Name() < entries[j].Name() })
+
+	return entries, found
+}
+
+type loaderFile struct {
+	info    loaderInfo
+	r       *bytes.Reader
+	entries []fs.DirEntry
+}
+
+func (f *loaderFile) Stat() (fs.FileInfo, error) { return f.info, nil }
+
+func (f *loaderFile) Read(p []byte) (int, error) {
+	if f.info.dir {
+		return 0, &fs.PathError{Op: "read", Path: f.info.name, Err: fs.ErrInvalid}
+	}
+
+	return f.r.Read(p)
+}
+
+func (f *loaderFile) Close() error { return nil }
+
+func (f *loaderFile) ReadDir(n int) ([]fs.DirEntry, error) {
+	if n <= 0 {
+		entries := f.entries
+		f.entries = nil
+
+		return entries, nil
+	}
+
+	if len(f.entries) == 0 {
+		return nil, io.EOF
+	}
+
+	if n > len(f.entries) {
+		n = len(f.entries)
+	}
+
+	entries := f.entries[:n]
+	f.entries = f.entries[n:]
+
+	return entries, nil
+}
+
+type loaderInfo struct {
+	name    string
+	size    int64
+	modTime time.Time
+	dir     bool
+}
+
+func (i loaderInfo) Name() string       { return i.name }
+func (i loaderInfo) Size() int64        { return i.size }
+func (i loaderInfo) ModTime() time.Time { return i.modTime }
+func (i loaderInfo) IsDir() bool        { return i.dir }
+func (i loaderInfo) Sys() any           { return nil }
+
+func (i loaderInfo) Mode() fs.FileMode {
+	if i.dir {
+		return fs.ModeDir | 0o555
+	}
+
+	return 0o444
+}
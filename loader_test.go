@@ -0,0 +1,97 @@
+package templates_test
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	templates "github.com/wolfeidau/echo-go-templates"
+)
+
+type mapLoader struct {
+	mu        sync.Mutex
+	templates map[string]string
+}
+
+func (l *mapLoader) List() ([]templates.Item, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	var items []templates.Item
+	for name, source := range l.templates {
+		items = append(items, templates.Item{Name: name, Size: int64(len(source))})
+	}
+
+	sort.Slice(items, func(i, j int) bool { return items[i].Name < items[j].Name })
+
+	return items, nil
+}
+
+func (l *mapLoader) Read(name string) ([]byte, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	source, ok := l.templates[name]
+	if !ok {
+		return nil, fmt.Errorf("template %s not found", name)
+	}
+
+	return []byte(source), nil
+}
+
+func (l *mapLoader) set(name, source string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.templates[name] = source
+}
+
+func Test_LoaderFS(t *testing.T) {
+	assert := require.New(t)
+
+	loader := &mapLoader{templates: map[string]string{
+		"layout.html":        `<html>{{block "content" .}}{{end}}</html>`,
+		"pages/index.html":   `{{define "content"}}index{{end}}`,
+		"pages/about/a.html": `{{define "content"}}about{{end}}`,
+	}}
+
+	fsys := templates.LoaderFS(loader)
+
+	err := fstest.TestFS(fsys, "layout.html", "pages/index.html", "pages/about/a.html")
+	assert.NoError(err)
+
+	render := templates.New()
+
+	err = render.AddWithLayout(fsys, "layout.html", "pages/*.html")
+	assert.NoError(err)
+
+	s, err := render.RenderString("index.html", nil)
+	assert.NoError(err)
+	assert.Equal("<html>index</html>", s)
+
+	loader.set("pages/index.html", `{{define "content"}}updated{{end}}`)
+
+	err = render.Reload()
+	assert.NoError(err)
+
+	s, err = render.RenderString("index.html", nil)
+	assert.NoError(err)
+	assert.Equal("<html>updated</html>", s)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	render.AutoReload(ctx, 10*time.Millisecond)
+
+	loader.set("pages/index.html", `{{define "content"}}reloaded{{end}}`)
+
+	assert.Eventually(func() bool {
+		s, err := render.RenderString("index.html", nil)
+		return err == nil && s == "<html>reloaded</html>"
+	}, time.Second, 10*time.Millisecond)
+}
@@ -0,0 +1,113 @@
+package templates
+
+import (
+	"container/list"
+	"strings"
+	"sync"
+	"time"
+)
+
+// lru is a size bounded cache which evicts the least recently used entry, entries also expire after
+// their ttl.
+type lru[V any] struct {
+	mu    sync.Mutex
+	size  int
+	ll    *list.List
+	items map[string]*list.Element
+	now   func() time.Time
+}
+
+type lruEntry[V any] struct {
+	key     string
+	value   V
+	expires time.Time
+}
+
+func newLRU[V any](size int) *lru[V] {
+	return &lru[V]{
+		size:  size,
+		ll:    list.New(),
+		items: make(map[string]*list.Element),
+		now:   time.Now,
+	}
+}
+
+// get returns the value stored with the key if it hasn't expired.
+func (c *lru[V]) get(key string) (V, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var zero V
+
+	el, ok := c.items[key]
+	if !ok {
+		return zero, false
+	}
+
+	entry := el.Value.(*lruEntry[V])
+	if !c.now().Before(entry.expires) {
+		c.ll.Remove(el)
+		delete(c.items, key)
+
+		return zero, false
+	}
+
+	c.ll.MoveToFront(el)
+
+	return entry.value, true
+}
+
+// set stores the value with the key until the ttl expires, evicting the least recently used entry if
+// the cache is full.
+func (c *lru[V]) set(key string, value V, ttl time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	expires := c.now().Add(ttl)
+
+	if el, ok := c.items[key]; ok {
+		entry := el.Value.(*lruEntry[V])
+		entry.value, entry.expires = value, expires
+		c.ll.MoveToFront(el)
+
+		return
+	}
+
+	c.items[key] = c.ll.PushFront(&lruEntry[V]{key: key, value: value, expires: expires})
+
+	for c.size > 0 && c.ll.Len() > c.size {
+		oldest := c.ll.Back()
+		c.ll.Remove(oldest)
+		delete(c.items, oldest.Value.(*lruEntry[V]).key)
+	}
+}
+
+// purge removes all the entries.
+func (c *lru[V]) purge() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.ll.Init()
+	c.items = make(map[string]*list.Element)
+}
+
+// len returns the number of entries, including those which have expired but not been evicted.
+func (c *lru[V]) len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.ll.Len()
+}
+
+// deletePrefix removes the entries with keys starting with the prefix.
+func (c *lru[V]) deletePrefix(prefix string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for key, el := range c.items {
+		if strings.HasPrefix(key, prefix) {
+			c.ll.Remove(el)
+			delete(c.items, key)
+		}
+	}
+}
@@ -0,0 +1,281 @@
+package templates
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"html/template"
+	"io"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// MarkdownBlock is the name of the block Markdown pages are defined in when they have a layout.
+const MarkdownBlock = "content"
+
+// MarkdownConverter converts Markdown to HTML, such as BasicMarkdown or the Convert method of goldmark:
+//
+//	md := goldmark.New(goldmark.WithExtensions(extension.GFM))
+//
+//	render := templates.New(templates.WithMarkdown(func(source []byte, w io.Writer) error {
+//		return md.Convert(source, w)
+//	}))
+type MarkdownConverter func(source []byte, w io.Writer) error
+
+// WithMarkdown registers files with a .md extension as Markdown pages, which are converted to HTML when
+// parsed. Pages with a layout are defined in the MarkdownBlock of the layout, and the front matter of
+// a page is passed to the layout as the data when rendering without any:
+//
+//	err := render.AddWithLayout(docs.Content, "layout.html", "docs/*.md")
+//	...
+//	return c.Render(http.StatusOK, "intro.md", nil) // {{.title}} is the title in the front matter
+//
+// The HTML is not a template, so actions in the Markdown are output as written.
+func WithMarkdown(convert MarkdownConverter) Option {
+	return func(t *TemplateRenderer) {
+		t.markdown = convert
+	}
+}
+
+// WithMarkdownFunc adds the markdown template function, which converts Markdown fields such as comments
+// and descriptions to HTML using convert, then removes unsafe markup from the result using sanitize:
+//
+//	render := templates.New(templates.WithMarkdownFunc(templates.BasicMarkdown, nil))
+//	...
+//	<div class="description">{{markdown .Product.Description}}</div>
+//
+// The sanitizer defaults to BasicSanitizer when nil, as the Markdown is typically user generated.
+func WithMarkdownFunc(convert MarkdownConverter, sanitize Sanitizer) Option {
+	if sanitize == nil {
+		sanitize = BasicSanitizer
+	}
+
+	return func(t *TemplateRenderer) {
+		t.templateFuncs["markdown"] = func(s string) (template.HTML, error) {
+			buf := new(bytes.Buffer)
+
+			err := convert([]byte(s), buf)
+			if err != nil {
+				return "", errors.Wrap(err, "failed to convert markdown")
+			}
+
+			return template.HTML(sanitize(buf.String())), nil //nolint:gosec // output of the sanitizer
+		}
+	}
+}
+
+// markdownBody converts the Markdown source of the template into a template defining the page.
+func (tmpl *Template) markdownBody() ([]byte, error) {
+	buf := new(bytes.Buffer)
+
+	err := tmpl.markdown(stripFrontMatter(tmpl.source), buf)
+	if err != nil {
+		return nil, err
+	}
+
+	left, right := tmpl.delims.leftOrDefault(), tmpl.delims.rightOrDefault()
+
+	// escape the delimiters so the output is rendered as is
+	escaped := strings.ReplaceAll(buf.String(), left, left+fmt.Sprintf("%q", left)+right)
+
+	if len(tmpl.layoutFiles) == 0 {
+		return []byte(escaped), nil
+	}
+
+	return []byte(left + fmt.Sprintf("define %q", MarkdownBlock) + right + escaped + left + "end" + right), nil
+}
+
+func isMarkdown(name string) bool {
+	return path.Ext(name) == ".md"
+}
+
+var (
+	orderedItem   = regexp.MustCompile(`^\d+[.)]\s+`)
+	unorderedItem = regexp.MustCompile(`^[-*+]\s+`)
+	headingLine   = regexp.MustCompile(`^(#{1,6})\s+(.*?)\s*#*\s*$`)
+	ruleLine      = regexp.MustCompile(`^(\*\s*){3,}$|^(-\s*){3,}$|^(_\s*){3,}$`)
+	imageSpan     = regexp.MustCompile(`!\[([^\]]*)\]\(([^)\s]+)\)`)
+	linkSpan      = regexp.MustCompile(`\[([^\]]+)\]\(([^)\s]+)\)`)
+	strongSpan    = regexp.MustCompile(`\*\*(.+?)\*\*|__(.+?)__`)
+	emSpan        = regexp.MustCompile(`\*(.+?)\*|\b_(.+?)_\b`)
+	spanHolder    = regexp.MustCompile("\x00([0-9]+)\x00")
+)
+
+// BasicMarkdown is a MarkdownConverter supporting a small subset of Markdown, without any dependencies:
+// headings, paragraphs, fenced code blocks, block quotes, horizontal rules, unordered and ordered lists
+// which aren't nested, and inline code, strong, emphasis, links and images. Raw HTML is escaped.
+func BasicMarkdown(source []byte, w io.Writer) error {
+	var (
+		out   strings.Builder
+		para  []string
+		list  string // the list element currently open
+		quote []string
+	)
+
+	flush := func() {
+		if len(para) > 0 {
+			out.WriteString("<p>" + inlineMarkdown(strings.Join(para, "\n")) + "</p>\n")
+			para = nil
+		}
+
+		if list != "" {
+			out.WriteString("</li>\n</" + list + ">\n")
+			list = ""
+		}
+
+		if len(quote) > 0 {
+			inner := new(bytes.Buffer)
+			_ = BasicMarkdown([]byte(strings.Join(quote, "\n")), inner)
+			out.WriteString("<blockquote>\n" + inner.String() + "</blockquote>\n")
+			quote = nil
+		}
+	}
+
+	lines := strings.Split(strings.ReplaceAll(string(source), "\r\n", "\n"), "\n")
+
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+		trimmed := strings.TrimSpace(line)
+
+		switch {
+		case trimmed == "":
+			flush()
+		case strings.HasPrefix(trimmed, "```"):
+			flush()
+
+			lang := strings.TrimSpace(strings.TrimPrefix(trimmed, "```"))
+
+			var code []string
+
+			for i++; i < len(lines) && !strings.HasPrefix(strings.TrimSpace(lines[i]), "```"); i++ {
+				code = append(code, lines[i])
+			}
+
+			if lang != "" {
+				out.WriteString(`<pre><code class="language-` + html.EscapeString(lang) + `">`)
+			} else {
+				out.WriteString("<pre><code>")
+			}
+
+			out.WriteString(html.EscapeString(strings.Join(code, "\n")) + "\n</code></pre>\n")
+		case strings.HasPrefix(trimmed, ">"):
+			if len(quote) == 0 {
+				flush()
+			}
+
+			quote = append(quote, strings.TrimPrefix(strings.TrimPrefix(trimmed, ">"), " "))
+		case headingLine.MatchString(trimmed):
+			flush()
+
+			m := headingLine.FindStringSubmatch(trimmed)
+			level := fmt.Sprint(len(m[1]))
+			out.WriteString("<h" + level + ">" + inlineMarkdown(m[2]) + "</h" + level + ">\n")
+		case ruleLine.MatchString(trimmed):
+			flush()
+			out.WriteString("<hr>\n")
+		case unorderedItem.MatchString(trimmed), orderedItem.MatchString(trimmed):
+			kind, marker := "ul", unorderedItem
+			if orderedItem.MatchString(trimmed) {
+				kind, marker = "ol", orderedItem
+			}
+
+			if list != kind {
+				flush()
+				out.WriteString("<" + kind + ">\n<li>")
+				list = kind
+			} else {
+				out.WriteString("</li>\n<li>")
+			}
+
+			out.WriteString(inlineMarkdown(marker.ReplaceAllString(trimmed, "")))
+		case list != "" && line != trimmed:
+			// an indented line continues the list item
+			out.WriteString("\n" + inlineMarkdown(trimmed))
+		default:
+			if list != "" || len(quote) > 0 {
+				flush()
+			}
+
+			para = append(para, trimmed)
+		}
+	}
+
+	flush()
+
+	_, err := io.WriteString(w, out.String())
+
+	return err
+}
+
+// inlineMarkdown converts the inline Markdown in text to HTML, code spans are output as is.
+func inlineMarkdown(text string) string {
+	parts := strings.Split(strings.ReplaceAll(text, "\x00", "\uFFFD"), "`")
+
+	for i, part := range parts {
+		part = html.EscapeString(part)
+
+		if i%2 == 1 && i < len(parts)-1 {
+			parts[i] = "<code>" + part + "</code>"
+			continue
+		}
+
+		// images and links are replaced by placeholders until emphasis is converted, so it isn't
+		// applied to their urls
+		var spans []string
+
+		hold := func(span string) string {
+			spans = append(spans, span)
+			return "\x00" + strconv.Itoa(len(spans)-1) + "\x00"
+		}
+
+		restore := func(s string) string {
+			return spanHolder.ReplaceAllStringFunc(s, func(h string) string {
+				n, _ := strconv.Atoi(h[1 : len(h)-1])
+				return spans[n]
+			})
+		}
+
+		part = imageSpan.ReplaceAllStringFunc(part, func(s string) string {
+			m := imageSpan.FindStringSubmatch(s)
+			return hold(`<img src="` + safeMarkdownURL(m[2]) + `" alt="` + m[1] + `">`)
+		})
+		part = linkSpan.ReplaceAllStringFunc(part, func(s string) string {
+			m := linkSpan.FindStringSubmatch(s)
+			return hold(`<a href="` + safeMarkdownURL(m[2]) + `">` + restore(emphasis(m[1])) + `</a>`)
+		})
+		part = restore(emphasis(part))
+
+		if i%2 == 1 {
+			// an unmatched backtick is output as is
+			part = "`" + part
+		}
+
+		parts[i] = part
+	}
+
+	return strings.Join(parts, "")
+}
+
+// emphasis converts the strong and emphasis spans in text to HTML.
+func emphasis(text string) string {
+	text = strongSpan.ReplaceAllString(text, "<strong>$1$2</strong>")
+	return emSpan.ReplaceAllString(text, "<em>$1$2</em>")
+}
+
+// safeMarkdownURL returns the escaped url, replacing urls with a scheme other than http, https or mailto.
+func safeMarkdownURL(url string) string {
+	scheme, _, ok := strings.Cut(url, ":")
+	if ok && !strings.ContainsAny(scheme, "/?#") {
+		switch strings.ToLower(scheme) {
+		case "http", "https", "mailto":
+		default:
+			return "#"
+		}
+	}
+
+	return url
+}
@@ -0,0 +1,123 @@
+package templates_test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/require"
+	templates "github.com/wolfeidau/echo-go-templates"
+)
+
+func Test_BasicMarkdown(t *testing.T) {
+	assert := require.New(t)
+
+	source := "# Title\n\nSome *emphasis*, **strong** and `{{code}}`\nwith a [link](https://example.com?a=1&b=2) and [bad](javascript:void).\n\n" +
+		"- one\n- two\n  continued\n\n1. first\n2. second\n\n> quoted\n> text\n\n---\n\n```go\nfmt.Println(\"<hi>\")\n```\n\n![logo](/logo.png)\n<script>x</script>\n"
+
+	buf := new(bytes.Buffer)
+
+	err := templates.BasicMarkdown([]byte(source), buf)
+	assert.NoError(err)
+	assert.Equal(`<h1>Title</h1>
+<p>Some <em>emphasis</em>, <strong>strong</strong> and <code>{{code}}</code>
+with a <a href="https://example.com?a=1&amp;b=2">link</a> and <a href="#">bad</a>.</p>
+<ul>
+<li>one</li>
+<li>two
+continued</li>
+</ul>
+<ol>
+<li>first</li>
+<li>second</li>
+</ol>
+<blockquote>
+<p>quoted
+text</p>
+</blockquote>
+<hr>
+<pre><code class="language-go">fmt.Println(&#34;&lt;hi&gt;&#34;)
+</code></pre>
+<p><img src="/logo.png" alt="logo">
+&lt;script&gt;x&lt;/script&gt;</p>
+`, buf.String())
+}
+
+func Test_BasicMarkdown_LinkURLs(t *testing.T) {
+	assert := require.New(t)
+
+	source := "[y](https://a.com/*a*) [**b**](https://a.com/__b__) *see ![x_1_](/img/_x_.png)* and [![i](/i_*_.png)](/l)\n"
+
+	buf := new(bytes.Buffer)
+
+	err := templates.BasicMarkdown([]byte(source), buf)
+	assert.NoError(err)
+	assert.Equal(`<p><a href="https://a.com/*a*">y</a> <a href="https://a.com/__b__"><strong>b</strong></a> `+
+		`<em>see <img src="/img/_x_.png" alt="x_1_"></em> and <a href="/l"><img src="/i_*_.png" alt="i"></a></p>
+`, buf.String())
+}
+
+func Test_WithMarkdown(t *testing.T) {
+	assert := require.New(t)
+
+	fsys := fstest.MapFS{
+		"layout.html":    {Data: []byte(`<html><title>{{.title}}</title>{{block "content" .}}{{end}}</html>`)},
+		"docs/intro.md":  {Data: []byte("---\ntitle: Intro\n---\n# {{.title}}\n\nHello *world*\n")},
+		"docs/page.html": {Data: []byte(`{{define "content"}}<p>{{.}}</p>{{end}}`)},
+	}
+
+	render := templates.New(templates.WithMarkdown(templates.BasicMarkdown))
+
+	err := render.AddWithLayout(fsys, "layout.html", "docs/*")
+	assert.NoError(err)
+
+	s, err := render.RenderString("intro.md", nil)
+	assert.NoError(err)
+	assert.Equal("<html><title>Intro</title><h1>{{.title}}</h1>\n<p>Hello <em>world</em></p>\n</html>", s)
+
+	s, err = render.RenderString("intro.md", map[string]string{"title": "Override"})
+	assert.NoError(err)
+	assert.Equal("<html><title>Override</title><h1>{{.title}}</h1>\n<p>Hello <em>world</em></p>\n</html>", s)
+
+	s, err = render.RenderString("page.html", map[string]string{"title": "Page"})
+	assert.NoError(err)
+	assert.Equal("<html><title>Page</title><p>map[title:Page]</p></html>", s)
+}
+
+func Test_WithMarkdownFunc(t *testing.T) {
+	assert := require.New(t)
+
+	render := templates.New(templates.WithMarkdownFunc(templates.BasicMarkdown, nil))
+
+	err := render.AddFromString("comment.html", "", `<div>{{markdown .}}</div>`)
+	assert.NoError(err)
+
+	out, err := render.RenderString("comment.html", "Some **bold** text")
+	assert.NoError(err)
+	assert.Equal("<div><p>Some <strong>bold</strong> text</p>\n</div>", out)
+
+	// converters which pass raw HTML through are sanitized
+	render = templates.New(templates.WithMarkdownFunc(func(source []byte, w io.Writer) error {
+		_, err := w.Write(source)
+		return err
+	}, nil))
+
+	err = render.AddFromString("comment.html", "", `{{markdown .}}`)
+	assert.NoError(err)
+
+	out, err = render.RenderString("comment.html", `<p>hi</p><script>alert(1)</script>`)
+	assert.NoError(err)
+	assert.Equal("<p>hi</p>", out)
+
+	render = templates.New(templates.WithMarkdownFunc(func(source []byte, w io.Writer) error {
+		return errors.New("boom")
+	}, nil))
+
+	err = render.AddFromString("comment.html", "", `{{markdown .}}`)
+	assert.NoError(err)
+
+	_, err = render.RenderString("comment.html", "text")
+	assert.ErrorContains(err, "failed to convert markdown: boom")
+}
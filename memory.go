@@ -0,0 +1,119 @@
+package templates
+
+import (
+	"fmt"
+	"io/fs"
+	"sort"
+	"sync"
+)
+
+// AddFromString registers a template parsed from content, using the layout if it isn't empty. The name
+// is the path of the template, and layouts, or other templates, registered using AddFromString or
+// AddFromMap can be used as the layout, so tests and generated templates don't need a file system.
+//
+//	err := render.AddFromMap(map[string]string{"layout.html": `<html>{{block "content" .}}{{end}}</html>`})
+//	...
+//	err = render.AddFromString("index.html", "layout.html", `{{define "content"}}hello{{end}}`)
+func (t *TemplateRenderer) AddFromString(name, layout, content string) error {
+	restore := t.memory.store(map[string]string{name: content})
+
+	err := t.addFrom(memoryFS, layoutFiles(layout), nil, literal([]string{name}))
+	if err != nil {
+		restore()
+	}
+
+	return err
+}
+
+// AddFromMap registers a template for each entry in the map of names to content, without a layout
+// unless one is given using front matter or a layout directive.
+func (t *TemplateRenderer) AddFromMap(templates map[string]string) error {
+	names := make([]string, 0, len(templates))
+	for name := range templates {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	restore := t.memory.store(templates)
+
+	err := t.addFrom(memoryFS, nil, nil, literal(names))
+	if err != nil {
+		restore()
+	}
+
+	return err
+}
+
+// memoryLoader is a Loader holding the templates registered from strings, it is shared by a renderer
+// and its groups so layouts can be used by templates added to either.
+type memoryLoader struct {
+	mu    sync.RWMutex
+	files map[string][]byte
+}
+
+// memoryFS returns the templates registered from strings with the registry of the renderer.
+func memoryFS(t *TemplateRenderer) fs.FS {
+	return t.memory.fs()
+}
+
+func newMemoryLoader() *memoryLoader {
+	return &memoryLoader{files: make(map[string][]byte)}
+}
+
+func (m *memoryLoader) fs() loaderFS {
+	return loaderFS{m}
+}
+
+// store adds the templates, returning a function which restores the previous contents.
+func (m *memoryLoader) store(templates map[string]string) func() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	previous := make(map[string][]byte, len(templates))
+
+	for name, content := range templates {
+		if old, ok := m.files[name]; ok {
+			previous[name] = old
+		}
+
+		m.files[name] = []byte(content)
+	}
+
+	return func() {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+
+		for name := range templates {
+			if old, ok := previous[name]; ok {
+				m.files[name] = old
+			} else {
+				delete(m.files, name)
+			}
+		}
+	}
+}
+
+func (m *memoryLoader) List() ([]Item, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	items := make([]Item, 0, len(m.files))
+	for name, data := range m.files {
+		items = append(items, Item{Name: name, Size: int64(len(data))})
+	}
+
+	return items, nil
+}
+
+func (m *memoryLoader) Read(name string) ([]byte, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	data, ok := m.files[name]
+	if !ok {
+		return nil, fmt.Errorf("template %s not found", name)
+	}
+
+	return data, nil
+}
@@ -0,0 +1,71 @@
+package templates_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	templates "github.com/wolfeidau/echo-go-templates"
+)
+
+func Test_AddFromString(t *testing.T) {
+	assert := require.New(t)
+
+	render := templates.New(templates.WithStrict())
+
+	err := render.AddFromMap(map[string]string{
+		"layouts/base.html": `<html>{{block "content" .}}{{end}}</html>`,
+		"fragment.html":     `<p>{{.}}</p>`,
+		"directive.html":    `{{/* layout: layouts/base.html */}}{{define "content"}}directive{{end}}`,
+	})
+	assert.NoError(err)
+
+	err = render.AddFromString("pages/index.html", "layouts/base.html", `{{define "content"}}hello {{.}}{{end}}`)
+	assert.NoError(err)
+
+	for name, want := range map[string]string{
+		"index.html":     "<html>hello world</html>",
+		"fragment.html":  "<p>world</p>",
+		"directive.html": "<html>directive</html>",
+	} {
+		s, err := render.RenderString(name, "world")
+		assert.NoError(err)
+		assert.Equal(want, s)
+	}
+
+	err = render.AddFromString("broken.html", "", `{{if}}`)
+	assert.Error(err)
+
+	err = render.AddFromString("pages/other.html", "missing.html", `hello`)
+	assert.ErrorContains(err, "missing.html")
+
+	err = render.Reload()
+	assert.NoError(err)
+
+	s, err := render.RenderString("index.html", "again")
+	assert.NoError(err)
+	assert.Equal("<html>hello again</html>", s)
+}
+
+func Test_AddFromMap_LiteralNames(t *testing.T) {
+	assert := require.New(t)
+
+	render := templates.New(templates.WithStrict())
+
+	err := render.AddFromMap(map[string]string{
+		"index.html": `index`,
+		"[id].html":  `item {{.}}`,
+	})
+	assert.NoError(err)
+
+	err = render.AddFromString("*.html", "", `star`)
+	assert.NoError(err)
+	assert.Equal([]string{"*.html", "[id].html", "index.html"}, render.Names())
+
+	s, err := render.RenderString("[id].html", 42)
+	assert.NoError(err)
+	assert.Equal("item 42", s)
+
+	s, err = render.RenderString("*.html", nil)
+	assert.NoError(err)
+	assert.Equal("star", s)
+}
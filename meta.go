@@ -0,0 +1,108 @@
+package templates
+
+import (
+	"html/template"
+	"strings"
+	"sync"
+
+	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
+)
+
+const metaContextKey = "_meta"
+
+// Meta describes a page for search engines and social sharing, it is rendered as title, description,
+// canonical, OpenGraph and Twitter tags by the metaTags template function added by WithMeta.
+type Meta struct {
+	Title       string
+	Description string
+	Canonical   string // absolute URL of the page
+	Image       string // absolute URL of the image shown when the page is shared
+	SiteName    string
+	Type        string // OpenGraph type, defaults to website
+	TwitterCard string // defaults to summary_large_image with an image, otherwise summary
+	TwitterSite string // @username of the site
+}
+
+// SetMeta sets the meta data of the page rendered for the request, empty fields use the defaults passed
+// to WithMeta.
+func SetMeta(c echo.Context, meta Meta) {
+	c.Set(metaContextKey, meta)
+}
+
+// WithMeta adds the metaTags template function which renders the head tags for the Meta set for the
+// request using SetMeta, merged with the defaults, and the pageMeta function which returns the merged Meta:
+//
+//	<head>{{metaTags}}</head>
+func WithMeta(defaults Meta) Option {
+	return WithContextFuncs(func(c echo.Context) template.FuncMap {
+		meta := func() Meta {
+			var m Meta
+			if c != nil {
+				m, _ = c.Get(metaContextKey).(Meta)
+			}
+
+			return m.withDefaults(defaults)
+		}
+
+		return template.FuncMap{
+			"pageMeta": meta,
+			"metaTags": func() (template.HTML, error) {
+				return renderMetaTags(meta())
+			},
+		}
+	})
+}
+
+// withDefaults returns the meta with empty fields set from the defaults.
+func (m Meta) withDefaults(defaults Meta) Meta {
+	setDefault(&m.Title, defaults.Title)
+	setDefault(&m.Description, defaults.Description)
+	setDefault(&m.Canonical, defaults.Canonical)
+	setDefault(&m.Image, defaults.Image)
+	setDefault(&m.SiteName, defaults.SiteName)
+	setDefault(&m.Type, defaults.Type)
+	setDefault(&m.TwitterCard, defaults.TwitterCard)
+	setDefault(&m.TwitterSite, defaults.TwitterSite)
+	setDefault(&m.Type, "website")
+
+	if m.TwitterCard == "" {
+		m.TwitterCard = "summary"
+		if m.Image != "" {
+			m.TwitterCard = "summary_large_image"
+		}
+	}
+
+	return m
+}
+
+func setDefault(value *string, fallback string) {
+	if *value == "" {
+		*value = fallback
+	}
+}
+
+var (
+	metaOnce     sync.Once
+	metaTemplate *template.Template
+	metaErr      error
+)
+
+func renderMetaTags(meta Meta) (template.HTML, error) {
+	metaOnce.Do(func() {
+		metaTemplate, metaErr = template.ParseFS(builtinFS, "builtin/meta.html")
+	})
+
+	if metaErr != nil {
+		return "", errors.Wrap(metaErr, "failed to parse meta template")
+	}
+
+	buf := new(strings.Builder)
+
+	err := metaTemplate.Execute(buf, meta)
+	if err != nil {
+		return "", err
+	}
+
+	return template.HTML(buf.String()), nil //nolint:gosec // output of a template
+}
@@ -0,0 +1,59 @@
+package templates_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"testing/fstest"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/require"
+	templates "github.com/wolfeidau/echo-go-templates"
+)
+
+func Test_WithMeta(t *testing.T) {
+	assert := require.New(t)
+
+	fsys := fstest.MapFS{
+		"page.html": {Data: []byte(`<head>{{metaTags}}</head>{{pageMeta.SiteName}}`)},
+	}
+
+	render := templates.New(templates.WithMeta(templates.Meta{SiteName: "Example", TwitterSite: "@example", Title: "Default"}))
+
+	err := render.Add(fsys, "*.html")
+	assert.NoError(err)
+
+	e := echo.New()
+	rec := httptest.NewRecorder()
+	c := e.NewContext(httptest.NewRequest(http.MethodGet, "/", nil), rec)
+
+	templates.SetMeta(c, templates.Meta{
+		Title:       `Tom & "Jerry"`,
+		Description: "A cartoon",
+		Canonical:   "https://example.com/tom?a=1&b=2",
+		Image:       "https://example.com/tom.png",
+	})
+
+	err = render.Render(rec, "page.html", nil, c)
+	assert.NoError(err)
+	assert.Equal(`<head><title>Tom &amp; &#34;Jerry&#34;</title>
+<meta property="og:title" content="Tom &amp; &#34;Jerry&#34;">
+<meta name="twitter:title" content="Tom &amp; &#34;Jerry&#34;">
+<meta name="description" content="A cartoon">
+<meta property="og:description" content="A cartoon">
+<meta name="twitter:description" content="A cartoon">
+<link rel="canonical" href="https://example.com/tom?a=1&amp;b=2">
+<meta property="og:url" content="https://example.com/tom?a=1&amp;b=2">
+<meta property="og:image" content="https://example.com/tom.png">
+<meta name="twitter:image" content="https://example.com/tom.png">
+<meta property="og:site_name" content="Example">
+<meta property="og:type" content="website">
+<meta name="twitter:card" content="summary_large_image">
+<meta name="twitter:site" content="@example">
+</head>Example`, rec.Body.String())
+
+	s, err := render.RenderString("page.html", nil)
+	assert.NoError(err)
+	assert.Contains(s, "<title>Default</title>")
+	assert.Contains(s, `<meta name="twitter:card" content="summary">`)
+}
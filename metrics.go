@@ -0,0 +1,46 @@
+package templates
+
+import (
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+// MetricsHook is a Hook which reports the duration of each render, and renders which failed, labelled
+// by template name. It takes functions rather than collectors so it works with any metrics library
+// without adding a dependency, for example with Prometheus:
+//
+//	durations := prometheus.NewHistogramVec(prometheus.HistogramOpts{
+//		Name: "template_render_duration_seconds",
+//	}, []string{"template"})
+//	failures := prometheus.NewCounterVec(prometheus.CounterOpts{
+//		Name: "template_render_errors_total",
+//	}, []string{"template"})
+//	registerer.MustRegister(durations, failures)
+//
+//	render := templates.New(templates.WithHook(&templates.MetricsHook{
+//		ObserveDuration: func(name string, seconds float64) { durations.WithLabelValues(name).Observe(seconds) },
+//		IncErrors:       func(name string) { failures.WithLabelValues(name).Inc() },
+//	}))
+type MetricsHook struct {
+	// ObserveDuration is called with the duration of every render in seconds, including failed renders.
+	ObserveDuration func(name string, seconds float64)
+	// IncErrors is called when a render fails.
+	IncErrors func(name string)
+}
+
+// BeforeRender returns the data unchanged.
+func (m *MetricsHook) BeforeRender(c echo.Context, name string, data any) (any, error) {
+	return data, nil
+}
+
+// AfterRender reports the duration and any error.
+func (m *MetricsHook) AfterRender(c echo.Context, name string, dur time.Duration, err error) {
+	if m.ObserveDuration != nil {
+		m.ObserveDuration(name, dur.Seconds())
+	}
+
+	if err != nil && m.IncErrors != nil {
+		m.IncErrors(name)
+	}
+}
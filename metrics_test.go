@@ -0,0 +1,46 @@
+package templates_test
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"testing/fstest"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/require"
+	templates "github.com/wolfeidau/echo-go-templates"
+)
+
+func Test_MetricsHook(t *testing.T) {
+	assert := require.New(t)
+
+	fsys := fstest.MapFS{
+		"index.html":  {Data: []byte(`index`)},
+		"broken.html": {Data: []byte(`{{.Missing.Field}}`)},
+	}
+
+	observed := map[string]int{}
+	errs := map[string]int{}
+
+	render := templates.New(templates.WithHook(&templates.MetricsHook{
+		ObserveDuration: func(name string, seconds float64) {
+			assert.GreaterOrEqual(seconds, 0.0)
+			observed[name]++
+		},
+		IncErrors: func(name string) { errs[name]++ },
+	}))
+
+	err := render.Add(fsys, "*.html")
+	assert.NoError(err)
+
+	e := echo.New()
+	c := e.NewContext(httptest.NewRequest(http.MethodGet, "/", http.NoBody), httptest.NewRecorder())
+
+	assert.NoError(render.Render(bytes.NewBufferString(""), "index.html", nil, c))
+	assert.NoError(render.Render(bytes.NewBufferString(""), "index.html", nil, c))
+	assert.Error(render.Render(bytes.NewBufferString(""), "broken.html", map[string]any{"Missing": 1}, c))
+
+	assert.Equal(map[string]int{"index.html": 2, "broken.html": 1}, observed)
+	assert.Equal(map[string]int{"broken.html": 1}, errs)
+}
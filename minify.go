@@ -0,0 +1,22 @@
+package templates
+
+// Minifier minifies content of a media type, it is implemented by *minify.M from
+// github.com/tdewolff/minify which isn't a dependency of this package.
+type Minifier interface {
+	Bytes(mediatype string, v []byte) ([]byte, error)
+}
+
+// MinifyHTML returns a Transformer which minifies the rendered HTML using m, for example using
+// tdewolff/minify with the html, css and js minifiers so inline styles and scripts are also minified:
+//
+//	m := minify.New()
+//	m.AddFunc("text/html", html.Minify)
+//	m.AddFunc("text/css", css.Minify)
+//	m.AddFuncRegexp(regexp.MustCompile("^(application|text)/(x-)?(java|ecma)script$"), js.Minify)
+//
+//	render := templates.New(templates.WithTransformer(templates.MinifyHTML(m)))
+func MinifyHTML(m Minifier) Transformer {
+	return TransformerFunc(func(name string, in []byte) ([]byte, error) {
+		return m.Bytes("text/html", in)
+	})
+}
@@ -0,0 +1,39 @@
+package templates_test
+
+import (
+	"regexp"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/require"
+	templates "github.com/wolfeidau/echo-go-templates"
+)
+
+type whitespaceMinifier struct {
+	mediatypes []string
+}
+
+func (m *whitespaceMinifier) Bytes(mediatype string, v []byte) ([]byte, error) {
+	m.mediatypes = append(m.mediatypes, mediatype)
+	return regexp.MustCompile(`>\s+<`).ReplaceAll(v, []byte("><")), nil
+}
+
+func Test_MinifyHTML(t *testing.T) {
+	assert := require.New(t)
+
+	fsys := fstest.MapFS{
+		"pages/index.html": {Data: []byte("<ul>\n  <li>{{.}}</li>\n</ul>")},
+	}
+
+	m := new(whitespaceMinifier)
+
+	render := templates.New(templates.WithTransformer(templates.MinifyHTML(m)))
+
+	err := render.Add(fsys, "pages/*.html")
+	assert.NoError(err)
+
+	s, err := render.RenderString("index.html", "one")
+	assert.NoError(err)
+	assert.Equal("<ul><li>one</li></ul>", s)
+	assert.Equal([]string{"text/html"}, m.mediatypes)
+}
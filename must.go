@@ -0,0 +1,60 @@
+package templates
+
+import "io/fs"
+
+// The Must variants of the Add methods panic if the templates can't be registered, for use when setting
+// up embedded templates where an error is always a programming mistake. They return the renderer so
+// calls can be chained:
+//
+//	render := templates.New().
+//		MustAddWithLayout(views.Content, "layout.html", "pages/*.html").
+//		MustAdd(views.Content, "fragments/*.html")
+
+// MustAdd is like Add but panics if the templates can't be registered.
+func (t *TemplateRenderer) MustAdd(fsys fs.FS, patterns ...string) *TemplateRenderer {
+	return t.must(t.Add(fsys, patterns...))
+}
+
+// MustAddWithLayout is like AddWithLayout but panics if the templates can't be registered.
+func (t *TemplateRenderer) MustAddWithLayout(fsys fs.FS, layout string, patterns ...string) *TemplateRenderer {
+	return t.must(t.AddWithLayout(fsys, layout, patterns...))
+}
+
+// MustAddWithLayouts is like AddWithLayouts but panics if the templates can't be registered.
+func (t *TemplateRenderer) MustAddWithLayouts(fsys fs.FS, layouts []string, patterns ...string) *TemplateRenderer {
+	return t.must(t.AddWithLayouts(fsys, layouts, patterns...))
+}
+
+// MustAddWithLayoutAndIncludes is like AddWithLayoutAndIncludes but panics if the templates can't be registered.
+func (t *TemplateRenderer) MustAddWithLayoutAndIncludes(fsys fs.FS, layout, includes string, patterns ...string) *TemplateRenderer {
+	return t.must(t.AddWithLayoutAndIncludes(fsys, layout, includes, patterns...))
+}
+
+// MustAddWithLayoutAndIncludePatterns is like AddWithLayoutAndIncludePatterns but panics if the templates
+// can't be registered.
+func (t *TemplateRenderer) MustAddWithLayoutAndIncludePatterns(fsys fs.FS, layout string, includes []string, patterns ...string) *TemplateRenderer {
+	return t.must(t.AddWithLayoutAndIncludePatterns(fsys, layout, includes, patterns...))
+}
+
+// MustAddFromString is like AddFromString but panics if the template can't be registered.
+func (t *TemplateRenderer) MustAddFromString(name, layout, content string) *TemplateRenderer {
+	return t.must(t.AddFromString(name, layout, content))
+}
+
+// MustAddFromMap is like AddFromMap but panics if the templates can't be registered.
+func (t *TemplateRenderer) MustAddFromMap(templates map[string]string) *TemplateRenderer {
+	return t.must(t.AddFromMap(templates))
+}
+
+// MustLoad is like Load but panics if the templates can't be registered.
+func (t *TemplateRenderer) MustLoad(fsys fs.FS, cfg LoadConfig) *TemplateRenderer {
+	return t.must(t.Load(fsys, cfg))
+}
+
+func (t *TemplateRenderer) must(err error) *TemplateRenderer {
+	if err != nil {
+		panic(err)
+	}
+
+	return t
+}
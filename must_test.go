@@ -0,0 +1,24 @@
+package templates_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	templates "github.com/wolfeidau/echo-go-templates"
+	"github.com/wolfeidau/echo-go-templates/test/views"
+)
+
+func Test_Must(t *testing.T) {
+	assert := require.New(t)
+
+	render := templates.New().
+		MustAddWithLayoutAndIncludes(views.Content, "layout.html", "includes/*.html", "pages/*.html").
+		MustAdd(views.Content, "fragments/*.html").
+		MustAddFromString("hello.html", "", "hello")
+
+	assert.Equal([]string{"data.html", "hello.html", "index.html"}, render.Names())
+
+	assert.PanicsWithError("failed to list using file pattern: template: pattern matches no files: `missing/*.html`", func() {
+		render.MustAdd(views.Content, "missing/*.html")
+	})
+}
@@ -0,0 +1,35 @@
+package templates
+
+import (
+	"path"
+	"strings"
+)
+
+// NameFunc derives the name a template is registered, and rendered, under from its path
+// in the file system.
+type NameFunc func(path string) string
+
+// BaseName names a template using the last element of its path, this is the default.
+func BaseName(p string) string {
+	return path.Base(p)
+}
+
+// RelativePath names a template using its full path relative to the root of the file system.
+func RelativePath(p string) string {
+	return p
+}
+
+// TrimExt wraps a NameFunc removing the file extension from the derived name.
+func TrimExt(fn NameFunc) NameFunc {
+	return func(p string) string {
+		name := fn(p)
+		return strings.TrimSuffix(name, path.Ext(name))
+	}
+}
+
+// Prefix wraps a NameFunc adding a prefix to the derived name.
+func Prefix(prefix string, fn NameFunc) NameFunc {
+	return func(p string) string {
+		return prefix + fn(p)
+	}
+}
@@ -0,0 +1,137 @@
+package templates
+
+import (
+	"html/template"
+	"io/fs"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+)
+
+// MenuItem is an item in a navigation menu, see WithNav.
+type MenuItem struct {
+	Title    string     `yaml:"title"`
+	Path     string     `yaml:"path"`
+	Children []MenuItem `yaml:"children"`
+}
+
+// NavItem is a menu item for the current request, returned by the menu and breadcrumbs template functions.
+type NavItem struct {
+	Title    string
+	Path     string
+	Current  bool // the path of the item is the path of the request
+	Active   bool // the item is current, or contains the current item or request path
+	Children []NavItem
+}
+
+// LoadNav reads the menus from a YAML file, which maps menu names to their items:
+//
+//	main:
+//	  - title: Docs
+//	    path: /docs
+//	    children:
+//	      - title: Install
+//	        path: /docs/install
+func LoadNav(fsys fs.FS, name string) (map[string][]MenuItem, error) {
+	data, err := fs.ReadFile(fsys, name)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read nav %s", name)
+	}
+
+	var menus map[string][]MenuItem
+
+	err = yaml.Unmarshal(data, &menus)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to parse nav %s", name)
+	}
+
+	return menus, nil
+}
+
+// WithNav adds the menu and breadcrumbs template functions which return the named menu for the current
+// request, with the items matching the request path marked as current and active:
+//
+//	{{range menu "main"}}<a href="{{.Path}}"{{if .Active}} class="active"{{end}}>{{.Title}}</a>{{end}}
+//
+//	{{range breadcrumbs "main"}}<a href="{{.Path}}">{{.Title}}</a>{{end}}
+//
+// An item is active if it contains the current item, or the request path is below its path, so /docs is
+// active for /docs/install/linux. Breadcrumbs are the active items from the top of the menu down.
+func WithNav(menus map[string][]MenuItem) Option {
+	return WithContextFuncs(func(c echo.Context) template.FuncMap {
+		requestPath := func() string {
+			if c == nil {
+				return ""
+			}
+
+			return c.Request().URL.Path
+		}
+
+		return template.FuncMap{
+			"menu": func(name string) []NavItem {
+				return navItems(menus[name], requestPath())
+			},
+			"breadcrumbs": func(name string) []NavItem {
+				return breadcrumbs(navItems(menus[name], requestPath()))
+			},
+		}
+	})
+}
+
+// navItems returns the items marked using the request path.
+func navItems(items []MenuItem, requestPath string) []NavItem {
+	if len(items) == 0 {
+		return nil
+	}
+
+	result := make([]NavItem, len(items))
+
+	for i, item := range items {
+		nav := NavItem{Title: item.Title, Path: item.Path, Children: navItems(item.Children, requestPath)}
+
+		nav.Current = requestPath != "" && item.Path == requestPath
+		nav.Active = nav.Current || isBelow(requestPath, item.Path)
+
+		for _, child := range nav.Children {
+			nav.Active = nav.Active || child.Active
+		}
+
+		result[i] = nav
+	}
+
+	return result
+}
+
+// isBelow returns true if the request path is below the path of the item.
+func isBelow(requestPath, itemPath string) bool {
+	if itemPath == "" || itemPath == "/" {
+		return false
+	}
+
+	return strings.HasPrefix(requestPath, strings.TrimSuffix(itemPath, "/")+"/")
+}
+
+// breadcrumbs returns the active item at each level, with the children removed.
+func breadcrumbs(items []NavItem) []NavItem {
+	var crumbs []NavItem
+
+	for len(items) > 0 {
+		var next []NavItem
+
+		for _, item := range items {
+			if item.Active {
+				next = item.Children
+				item.Children = nil
+				crumbs = append(crumbs, item)
+
+				break
+			}
+		}
+
+		items = next
+	}
+
+	return crumbs
+}
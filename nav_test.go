@@ -0,0 +1,59 @@
+package templates_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"testing/fstest"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/require"
+	templates "github.com/wolfeidau/echo-go-templates"
+)
+
+func Test_WithNav(t *testing.T) {
+	assert := require.New(t)
+
+	fsys := fstest.MapFS{
+		"nav.yaml": {Data: []byte(`main:
+  - title: Home
+    path: /
+  - title: Docs
+    path: /docs
+    children:
+      - title: Install
+        path: /docs/install
+      - title: Usage
+        path: /docs/usage
+`)},
+		"page.html": {Data: []byte(`{{range menu "main"}}[{{.Title}}{{if .Current}} current{{else if .Active}} active{{end}}]{{end}}|` +
+			`{{range breadcrumbs "main"}}/{{.Title}}{{end}}`)},
+	}
+
+	menus, err := templates.LoadNav(fsys, "nav.yaml")
+	assert.NoError(err)
+
+	render := templates.New(templates.WithNav(menus))
+
+	err = render.Add(fsys, "*.html")
+	assert.NoError(err)
+
+	tests := map[string]string{
+		"/":                   "[Home current][Docs]|/Home",
+		"/docs":               "[Home][Docs current]|/Docs",
+		"/docs/install":       "[Home][Docs active]|/Docs/Install",
+		"/docs/install/linux": "[Home][Docs active]|/Docs/Install",
+		"/about":              "[Home][Docs]|",
+	}
+
+	e := echo.New()
+
+	for target, expected := range tests {
+		rec := httptest.NewRecorder()
+		c := e.NewContext(httptest.NewRequest(http.MethodGet, target, nil), rec)
+
+		err = render.Render(rec, "page.html", nil, c)
+		assert.NoError(err)
+		assert.Equal(expected, rec.Body.String(), target)
+	}
+}
@@ -0,0 +1,87 @@
+package templates
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+// Negotiate renders the named template for browsers, or the data as JSON for clients which prefer
+// application/json in the Accept header, so a handler can serve both a UI and an API:
+//
+//	return render.Negotiate(c, http.StatusOK, "users.html", users)
+//
+// HTML is rendered when the Accept header is missing, or both are equally acceptable.
+func (t *TemplateRenderer) Negotiate(c echo.Context, code int, name string, data any) error {
+	addVary(c.Response().Header(), echo.HeaderAccept)
+
+	accept := c.Request().Header.Get(echo.HeaderAccept)
+	if acceptQuality(accept, echo.MIMEApplicationJSON) > acceptQuality(accept, echo.MIMETextHTML) {
+		return c.JSON(code, data)
+	}
+
+	buf := new(bytes.Buffer)
+
+	err := t.render(buf, name, data, c)
+	if err != nil {
+		return err
+	}
+
+	return t.blob(c, code, echo.MIMETextHTMLCharsetUTF8, buf.Bytes())
+}
+
+// acceptQuality returns the quality of the media type in the Accept header using the most specific
+// matching range, a missing header accepts everything.
+func acceptQuality(accept, mediaType string) float64 {
+	if accept == "" {
+		return 1
+	}
+
+	typ, _, _ := strings.Cut(mediaType, "/")
+
+	quality, specificity := 0.0, -1
+
+	for _, part := range strings.Split(accept, ",") {
+		params := strings.Split(part, ";")
+		rng := strings.ToLower(strings.TrimSpace(params[0]))
+
+		var s int
+
+		switch rng {
+		case mediaType:
+			s = 2
+		case typ + "/*":
+			s = 1
+		case "*/*":
+			s = 0
+		default:
+			continue
+		}
+
+		if s < specificity {
+			continue
+		}
+
+		quality, specificity = qValue(params[1:]), s
+	}
+
+	return quality
+}
+
+// qValue returns the q parameter of an Accept or Accept-Encoding header element, which defaults to 1.
+func qValue(params []string) float64 {
+	q := 1.0
+
+	for _, p := range params {
+		k, v, _ := strings.Cut(strings.TrimSpace(p), "=")
+		if strings.EqualFold(k, "q") {
+			if f, err := strconv.ParseFloat(v, 64); err == nil {
+				q = f
+			}
+		}
+	}
+
+	return q
+}
@@ -0,0 +1,54 @@
+package templates_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"testing/fstest"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/require"
+	templates "github.com/wolfeidau/echo-go-templates"
+)
+
+func Test_Negotiate(t *testing.T) {
+	fsys := fstest.MapFS{
+		"user.html": {Data: []byte(`<p>{{.Name}}</p>`)},
+	}
+
+	render := templates.New()
+
+	err := render.Add(fsys, "*.html")
+	require.NoError(t, err)
+
+	tests := []struct {
+		accept      string
+		contentType string
+		body        string
+	}{
+		{"", echo.MIMETextHTMLCharsetUTF8, "<p>mark</p>"},
+		{"text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8", echo.MIMETextHTMLCharsetUTF8, "<p>mark</p>"},
+		{"application/json", echo.MIMEApplicationJSONCharsetUTF8, `{"Name":"mark"}` + "\n"},
+		{"text/html;q=0.5, application/json", echo.MIMEApplicationJSONCharsetUTF8, `{"Name":"mark"}` + "\n"},
+		{"*/*", echo.MIMETextHTMLCharsetUTF8, "<p>mark</p>"},
+		{"application/*, text/*;q=0.1", echo.MIMEApplicationJSONCharsetUTF8, `{"Name":"mark"}` + "\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.accept, func(t *testing.T) {
+			assert := require.New(t)
+
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.Header.Set(echo.HeaderAccept, tt.accept)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+
+			err := render.Negotiate(c, http.StatusOK, "user.html", struct{ Name string }{"mark"})
+			assert.NoError(err)
+			assert.Equal(tt.contentType, rec.Header().Get(echo.HeaderContentType))
+			assert.Equal(tt.body, rec.Body.String())
+			assert.Equal(echo.HeaderAccept, rec.Header().Get(echo.HeaderVary))
+		})
+	}
+}
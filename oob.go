@@ -0,0 +1,129 @@
+package templates
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+// HTMXResponse composes a response to an HTMX request from a main fragment, which is swapped into the
+// target of the request, and out of band fragments which are swapped into the elements with the same id,
+// see TemplateRenderer.HTMXResponse.
+type HTMXResponse struct {
+	render    *TemplateRenderer
+	fragments []oobFragment
+}
+
+type oobFragment struct {
+	name string
+	swap string // empty for the main fragment
+	data any
+}
+
+// HTMXResponse returns a builder composing a response from rendered templates, each of which supports the
+// same "#" block suffix as Render. The hx-swap-oob attribute is added to the root element of the out of
+// band fragments, so updating a list and a counter elsewhere on the page is:
+//
+//	return render.HTMXResponse().
+//		Main("todos.html#list", todos).
+//		OOB("todos.html#count", len(todos)).
+//		Render(c, http.StatusOK)
+func (t *TemplateRenderer) HTMXResponse() *HTMXResponse {
+	return &HTMXResponse{render: t}
+}
+
+// Main adds a fragment which is swapped into the target of the request.
+func (r *HTMXResponse) Main(name string, data any) *HTMXResponse {
+	r.fragments = append(r.fragments, oobFragment{name: name, data: data})
+	return r
+}
+
+// OOB adds an out of band fragment which replaces the element with the same id as its root element.
+func (r *HTMXResponse) OOB(name string, data any) *HTMXResponse {
+	return r.OOBSwap(name, "true", data)
+}
+
+// OOBSwap adds an out of band fragment using the hx-swap-oob value, such as "beforeend:#messages".
+func (r *HTMXResponse) OOBSwap(name, swap string, data any) *HTMXResponse {
+	r.fragments = append(r.fragments, oobFragment{name: name, swap: swap, data: data})
+	return r
+}
+
+// Render writes the response with the status code.
+func (r *HTMXResponse) Render(c echo.Context, code int) error {
+	buf := new(bytes.Buffer)
+
+	err := r.write(buf, c)
+	if err != nil {
+		return err
+	}
+
+	return r.render.blob(c, code, echo.MIMETextHTMLCharsetUTF8, buf.Bytes())
+}
+
+// String renders the response without a request, for example to send it over a WebSocket.
+func (r *HTMXResponse) String() (string, error) {
+	buf := new(bytes.Buffer)
+
+	err := r.write(buf, nil)
+	if err != nil {
+		return "", err
+	}
+
+	return buf.String(), nil
+}
+
+func (r *HTMXResponse) write(buf *bytes.Buffer, c echo.Context) error {
+	for _, f := range r.fragments {
+		out := new(strings.Builder)
+
+		err := r.render.render(out, f.name, f.data, c)
+		if err != nil {
+			return err
+		}
+
+		if f.swap == "" {
+			buf.WriteString(out.String())
+			continue
+		}
+
+		fragment, err := addRootAttr(out.String(), "hx-swap-oob", f.swap)
+		if err != nil {
+			return fmt.Errorf("template: out of band fragment %s: %w", f.name, err)
+		}
+
+		buf.WriteString(fragment)
+	}
+
+	return nil
+}
+
+// addRootAttr adds the attribute to the first element in the fragment, unless it is already set.
+func addRootAttr(fragment, name, value string) (string, error) {
+	for i := 0; i < len(fragment)-1; i++ {
+		if fragment[i] != '<' || !isASCIILetter(fragment[i+1]) {
+			continue
+		}
+
+		end := i + 1
+		for end < len(fragment) && !strings.ContainsRune(" \t\r\n/>", rune(fragment[end])) {
+			end++
+		}
+
+		tagEnd := strings.IndexByte(fragment[i:], '>')
+		if tagEnd >= 0 && strings.Contains(strings.ToLower(fragment[i:i+tagEnd]), " "+name+"=") {
+			return fragment, nil
+		}
+
+		return fmt.Sprintf(`%s %s="%s"%s`, fragment[:end], name, html.EscapeString(value), fragment[end:]), nil
+	}
+
+	return "", fmt.Errorf("no root element to add %s to", name)
+}
+
+func isASCIILetter(b byte) bool {
+	return (b >= 'a' && b <= 'z') || (b >= 'A' && b <= 'Z')
+}
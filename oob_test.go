@@ -0,0 +1,50 @@
+package templates_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"testing/fstest"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/require"
+	templates "github.com/wolfeidau/echo-go-templates"
+)
+
+func Test_HTMXResponse(t *testing.T) {
+	assert := require.New(t)
+
+	fsys := fstest.MapFS{
+		"todos.html": {Data: []byte(`{{define "list"}}<ul id="todos">{{range .}}<li>{{.}}</li>{{end}}</ul>{{end}}` +
+			`{{define "count"}}
+<span id="count">{{len .}}</span>{{end}}` +
+			`{{define "row"}}<li hx-swap-oob="beforeend:#todos">{{.}}</li>{{end}}` +
+			`{{define "text"}}no elements{{end}}`)},
+	}
+
+	render := templates.New()
+
+	err := render.Add(fsys, "*.html")
+	assert.NoError(err)
+
+	e := echo.New()
+	rec := httptest.NewRecorder()
+	c := e.NewContext(httptest.NewRequest(http.MethodPost, "/todos", nil), rec)
+
+	todos := []string{"a", "b"}
+
+	err = render.HTMXResponse().
+		Main("todos.html#list", todos).
+		OOB("todos.html#count", todos).
+		OOBSwap("todos.html#row", "afterbegin:#todos", "c").
+		Render(c, http.StatusOK)
+	assert.NoError(err)
+
+	assert.Equal(echo.MIMETextHTMLCharsetUTF8, rec.Header().Get(echo.HeaderContentType))
+	assert.Equal(`<ul id="todos"><li>a</li><li>b</li></ul>`+
+		"\n"+`<span hx-swap-oob="true" id="count">2</span>`+
+		`<li hx-swap-oob="beforeend:#todos">c</li>`, rec.Body.String())
+
+	_, err = render.HTMXResponse().OOB("todos.html#text", nil).String()
+	assert.ErrorContains(err, "out of band fragment todos.html#text: no root element")
+}
@@ -0,0 +1,168 @@
+package templates
+
+import (
+	"html/template"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog"
+)
+
+// Option configures a TemplateRenderer.
+type Option func(*TemplateRenderer)
+
+// WithFuncs merges the provided template functions with the default template functions.
+func WithFuncs(funcs template.FuncMap) Option {
+	return func(t *TemplateRenderer) {
+		for k, v := range funcs {
+			t.templateFuncs[k] = v
+		}
+	}
+}
+
+// WithLogger sets the logger used when registering templates, and when rendering a request
+// which doesn't have a logger attached to its context.
+func WithLogger(logger zerolog.Logger) Option {
+	return func(t *TemplateRenderer) {
+		t.logger = &logger
+	}
+}
+
+// WithRequestLogFields adds the request ID, route and method of the request to the log entries written
+// while rendering it, so they can be correlated with the access logs. The request ID is read from the
+// X-Request-ID header set by the echo RequestID middleware.
+func WithRequestLogFields() Option {
+	return func(t *TemplateRenderer) {
+		t.requestLogFields = true
+	}
+}
+
+// WithSlowRenderThreshold logs a warning with the template name and data type when a render takes longer
+// than d, and marks the RenderEvent as slow, so slow templates are found without digging through latency
+// graphs.
+func WithSlowRenderThreshold(d time.Duration) Option {
+	return func(t *TemplateRenderer) {
+		t.slowRender = d
+	}
+}
+
+// WithRelativeNames registers templates using their path relative to the root of the file system,
+// rather than their base name, so "admin/index.html" and "public/index.html" don't collide.
+func WithRelativeNames() Option {
+	return WithNameFunc(RelativePath)
+}
+
+// WithNameFunc sets the function used to derive the registered name of a template from its path.
+func WithNameFunc(fn NameFunc) Option {
+	return func(t *TemplateRenderer) {
+		t.nameFunc = fn
+	}
+}
+
+// WithStrict returns an error when a template is registered under a name which is already in use,
+// rather than logging a warning and replacing the existing template.
+func WithStrict() Option {
+	return func(t *TemplateRenderer) {
+		t.strict = true
+	}
+}
+
+// WithReload reloads all the templates before each render so changes to template files are
+// picked up without a restart, this is intended for use during development.
+func WithReload() Option {
+	return func(t *TemplateRenderer) {
+		t.reload = true
+	}
+}
+
+// WithHTMX renders only the named block, typically "content", of templates registered with a layout
+// when the request was made by HTMX, so the same handler serves both full pages and partial updates.
+func WithHTMX(block string) Option {
+	return func(t *TemplateRenderer) {
+		t.htmxBlock = block
+	}
+}
+
+// WithDelims sets the action delimiters used to parse templates, for example "[[" and "]]" so
+// templates can contain Vue or Alpine snippets without escaping.
+func WithDelims(left, right string) Option {
+	return func(t *TemplateRenderer) {
+		t.delims = delims{left: left, right: right}
+	}
+}
+
+// WithTemplateOptions sets options on every parsed template, see template.Option, for example
+// "missingkey=error" fails renders which reference a missing map key rather than printing "<no value>".
+func WithTemplateOptions(opts ...string) Option {
+	return func(t *TemplateRenderer) {
+		t.templateOptions = append(t.templateOptions, opts...)
+	}
+}
+
+// WithTextTemplates parses templates using text/template rather than html/template, so output such as
+// plain text emails, robots.txt or CSV isn't HTML escaped. This is typically used with a group:
+//
+//	err := render.Group(templates.WithTextTemplates()).Add(views.Content, "emails/*.txt")
+func WithTextTemplates() Option {
+	return func(t *TemplateRenderer) {
+		t.text = true
+	}
+}
+
+// WithContentType sets the content type of the response when the templates are rendered using c.Render,
+// in place of the text/html content type it sets.
+func WithContentType(contentType string) Option {
+	return func(t *TemplateRenderer) {
+		t.contentType = contentType
+	}
+}
+
+// WithXMLTemplates parses templates using text/template, with the xml function to escape values, and
+// renders them with the application/xml content type. This is typically used with a group for feeds and
+// sitemaps:
+//
+//	err := render.Group(templates.WithXMLTemplates()).Add(views.Content, "feeds/*.xml")
+//
+// In templates values are escaped using {{xml .Title}}.
+func WithXMLTemplates() Option {
+	return func(t *TemplateRenderer) {
+		WithTextTemplates()(t)
+		WithContentType(echo.MIMEApplicationXMLCharsetUTF8)(t)
+		t.templateFuncs["xml"] = xmlEscape
+	}
+}
+
+// WithParseConcurrency sets the number of templates parsed concurrently by each Add call, which defaults
+// to GOMAXPROCS. File systems and loaders must be safe for concurrent use.
+func WithParseConcurrency(n int) Option {
+	return func(t *TemplateRenderer) {
+		t.parseWorkers = n
+	}
+}
+
+// WithLazyParsing defers parsing each template until it is first rendered, Add only reads the template
+// files and records their layouts and includes. This reduces startup time when many templates are
+// registered but few are rendered, such as in serverless deployments, at the cost of parse errors
+// only being returned on first render. Validate parses all the templates.
+func WithLazyParsing() Option {
+	return func(t *TemplateRenderer) {
+		t.lazy = true
+	}
+}
+
+// WithSprigFuncs adds the curated subset of Sprig functions returned by SprigFuncs.
+func WithSprigFuncs() Option {
+	return WithFuncs(SprigFuncs())
+}
+
+// WithContextFuncs adds template functions which are bound to the echo.Context of each request, such
+// as a currentUser or isActiveRoute helper.
+//
+// The function is called with a nil context when templates are parsed to discover the function names,
+// so the context must only be used within the returned functions. Templates are cloned for each render
+// when these functions are configured, which has a cost as the clone is escaped on first execution.
+func WithContextFuncs(fn func(c echo.Context) template.FuncMap) Option {
+	return func(t *TemplateRenderer) {
+		t.ctxFuncs = append(t.ctxFuncs, fn)
+	}
+}
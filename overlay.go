@@ -0,0 +1,118 @@
+package templates
+
+import (
+	"errors"
+	"io/fs"
+	"sort"
+)
+
+// Overlay returns a file system layering the file systems, where a file in a layer overrides the file
+// with the same path in the layers after it. Directory listings are merged, so globs match files from
+// every layer. This allows a theme to override some of the templates in the base views:
+//
+//	err := render.AddWithLayout(templates.Overlay(themeFS, views.Content), "layout.html", "pages/*.html")
+func Overlay(layers ...fs.FS) fs.FS {
+	return overlayFS(layers)
+}
+
+// WithOverlay layers the file systems over those passed to the Add methods, Load, Replace and
+// SetIncludes, see Overlay. This is typically used with a group:
+//
+//	err := render.Group(templates.WithOverlay(themeFS)).AddWithLayout(views.Content, "layout.html", "pages/*.html")
+func WithOverlay(layers ...fs.FS) Option {
+	return func(t *TemplateRenderer) {
+		t.overlays = append(t.overlays, layers...)
+	}
+}
+
+// overlay returns fsys with the overlays configured using WithOverlay layered over it.
+func (t *TemplateRenderer) overlay(fsys fs.FS) fs.FS {
+	if len(t.overlays) == 0 {
+		return fsys
+	}
+
+	layers := make(overlayFS, 0, len(t.overlays)+1)
+	layers = append(layers, t.overlays...)
+
+	return append(layers, fsys)
+}
+
+type overlayFS []fs.FS
+
+func (o overlayFS) Open(name string) (fs.File, error) {
+	for _, layer := range o {
+		f, err := layer.Open(name)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+
+		return f, err
+	}
+
+	return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+}
+
+func (o overlayFS) Stat(name string) (fs.FileInfo, error) {
+	for _, layer := range o {
+		info, err := fs.Stat(layer, name)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+
+		return info, err
+	}
+
+	return nil, &fs.PathError{Op: "stat", Path: name, Err: fs.ErrNotExist}
+}
+
+func (o overlayFS) ReadFile(name string) ([]byte, error) {
+	for _, layer := range o {
+		data, err := fs.ReadFile(layer, name)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+
+		return data, err
+	}
+
+	return nil, &fs.PathError{Op: "read", Path: name, Err: fs.ErrNotExist}
+}
+
+// ReadDir merges the entries of the directory in each layer, entries in earlier layers take precedence.
+func (o overlayFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	var (
+		entries []fs.DirEntry
+		seen    = make(map[string]bool)
+		found   bool
+	)
+
+	for _, layer := range o {
+		layerEntries, err := fs.ReadDir(layer, name)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		found = true
+
+		for _, entry := range layerEntries {
+			if seen[entry.Name()] {
+				continue
+			}
+
+			seen[entry.Name()] = true
+			entries = append(entries, entry)
+		}
+	}
+
+	if !found {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: fs.ErrNotExist}
+	}
+
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Name() < entries[j].Name() })
+
+	return entries, nil
+}
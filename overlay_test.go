@@ -0,0 +1,50 @@
+package templates_test
+
+import (
+	"io/fs"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/require"
+	templates "github.com/wolfeidau/echo-go-templates"
+)
+
+func Test_Overlay(t *testing.T) {
+	assert := require.New(t)
+
+	base := fstest.MapFS{
+		"layout.html":      {Data: []byte(`<html>{{block "content" .}}{{end}}</html>`)},
+		"pages/index.html": {Data: []byte(`{{define "content"}}base index{{end}}`)},
+		"pages/about.html": {Data: []byte(`{{define "content"}}base about{{end}}`)},
+	}
+
+	theme := fstest.MapFS{
+		"layout.html":        {Data: []byte(`<html class="acme">{{block "content" .}}{{end}}</html>`)},
+		"pages/index.html":   {Data: []byte(`{{define "content"}}acme index{{end}}`)},
+		"pages/contact.html": {Data: []byte(`{{define "content"}}acme contact{{end}}`)},
+	}
+
+	overlay := templates.Overlay(theme, base)
+
+	matches, err := fs.Glob(overlay, "pages/*.html")
+	assert.NoError(err)
+	assert.Equal([]string{"pages/about.html", "pages/contact.html", "pages/index.html"}, matches)
+
+	_, err = fs.ReadFile(overlay, "pages/missing.html")
+	assert.ErrorIs(err, fs.ErrNotExist)
+
+	render := templates.New()
+
+	err = render.Group(templates.WithOverlay(theme)).AddWithLayout(base, "layout.html", "pages/*.html")
+	assert.NoError(err)
+
+	for name, want := range map[string]string{
+		"index.html":   `<html class="acme">acme index</html>`,
+		"about.html":   `<html class="acme">base about</html>`,
+		"contact.html": `<html class="acme">acme contact</html>`,
+	} {
+		s, err := render.RenderString(name, nil)
+		assert.NoError(err)
+		assert.Equal(want, s)
+	}
+}
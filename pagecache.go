@@ -0,0 +1,311 @@
+package templates
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"io"
+	"reflect"
+	"strings"
+	"text/template/parse"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+// PageCacheStore stores rendered pages for WithPageCache, implementations backed by a shared store such
+// as Redis allow the cache to be shared by multiple instances.
+type PageCacheStore interface {
+	// Get returns the page stored with the key, if it exists and hasn't expired.
+	Get(key string) ([]byte, bool, error)
+	// Set stores the page with the key until the ttl expires.
+	Set(key string, body []byte, ttl time.Duration) error
+	// DeletePrefix removes the pages with keys starting with the prefix.
+	DeletePrefix(prefix string) error
+}
+
+// PageCacheConfig configures the page cache enabled using WithPageCache.
+type PageCacheConfig struct {
+	// Store holds the rendered pages, which defaults to an in-memory store holding up to 1000 pages.
+	Store PageCacheStore
+	// TTL is how long rendered pages are cached for, which defaults to one minute.
+	TTL time.Duration
+	// Skip is called before each render, returning true renders the page without the cache.
+	Skip func(c echo.Context) bool
+	// Precompress stores gzip compressed copies of the cached pages, which Serve sends to clients
+	// accepting gzip so the pages aren't compressed for each request. The copies aren't sent when
+	// transformers are configured, as they are applied to each response.
+	Precompress bool
+	// Brotli returns a writer compressing using brotli, such as brotli.NewWriter from andybalholm/brotli,
+	// which adds brotli compressed copies preferred over gzip when Precompress is enabled.
+	Brotli func(w io.Writer) io.WriteCloser
+}
+
+type pageCache struct {
+	store       PageCacheStore
+	ttl         time.Duration
+	skip        func(c echo.Context) bool
+	precompress bool
+	brotli      func(w io.Writer) io.WriteCloser
+}
+
+// WithPageCache caches the output of each render keyed by a hash of the template, block, locale and data,
+// so rendering the same page with the same data returns the stored output without executing the template.
+// Data which can't be marshalled to JSON is rendered without the cache.
+//
+// The data is hashed once the hooks and decorators have been applied, so values they add for the request
+// are part of the key. As the output is shared by all requests, templates calling request scoped
+// functions, such as csrfField, and templates rendered with WithViewModel aren't cached. This is
+// typically enabled using a group:
+//
+//	err := render.Group(templates.WithPageCache(templates.PageCacheConfig{TTL: 5 * time.Minute})).Add(views.Content, "docs/*.html")
+//
+// Use Invalidate to remove the cached output of a template when the content it displays changes. With
+// Precompress enabled, render pages using Serve to send the compressed copies.
+func WithPageCache(cfg PageCacheConfig) Option {
+	if cfg.Store == nil {
+		cfg.Store = NewMemoryPageStore(1000)
+	}
+
+	if cfg.TTL == 0 {
+		cfg.TTL = time.Minute
+	}
+
+	return func(t *TemplateRenderer) {
+		t.pageCache = &pageCache{
+			store:       cfg.Store,
+			ttl:         cfg.TTL,
+			skip:        cfg.Skip,
+			precompress: cfg.Precompress,
+			brotli:      cfg.Brotli,
+		}
+	}
+}
+
+// Invalidate removes the cached output of the named template from the page cache, it does nothing if the
+// page cache isn't enabled for the template.
+func (t *TemplateRenderer) Invalidate(name string) error {
+	pc := t.pageCache
+	if tmpl, ok := t.lookup(name); ok {
+		pc = tmpl.pageCache
+	}
+
+	if pc == nil {
+		return nil
+	}
+
+	return pc.store.DeletePrefix(pageCachePrefix(name))
+}
+
+// executeCached writes the cached output of the template, or executes it using render and caches the
+// output if there isn't any. It returns false if the page can't be cached.
+func (t *TemplateRenderer) executeCached(w io.Writer, tmpl *Template, block string, data any, c echo.Context, render func(w io.Writer) error) (bool, error) {
+	pc := tmpl.pageCache
+
+	if pc.skip != nil && pc.skip(c) {
+		return false, nil
+	}
+
+	// the output depends on the request as well as the data
+	if tmpl.ctxScoped || (t.viewModel && c != nil) {
+		t.ctxLog(c).Debug().Str("name", tmpl.name).Msg("page cache skipped for request scoped template")
+		return false, nil
+	}
+
+	key, ok := pageCacheKey(tmpl, block, t.Locale(c), data)
+	if !ok {
+		return false, nil
+	}
+
+	// compressed copies are written as they are, so they can't be used when the output is transformed
+	ps := precompressStateFrom(c)
+	if ps != nil && (!pc.precompress || len(t.transformers) > 0) {
+		ps = nil
+	}
+
+	if ps != nil {
+		ps.vary = true
+
+		if body, encoding, ok := pc.getCompressed(ps.accept, key); ok {
+			ps.encoding = encoding
+			_, err := w.Write(body)
+
+			return true, err
+		}
+	}
+
+	body, ok, err := pc.store.Get(key)
+	if err != nil {
+		t.ctxLog(c).Error().Err(err).Str("name", tmpl.name).Msg("read page cache failed")
+	}
+
+	if ok {
+		_, err = w.Write(body)
+		return true, err
+	}
+
+	buf := new(bytes.Buffer)
+
+	err = render(buf)
+	if err != nil {
+		return true, err
+	}
+
+	err = pc.store.Set(key, buf.Bytes(), pc.ttl)
+	if err != nil {
+		t.ctxLog(c).Error().Err(err).Str("name", tmpl.name).Msg("write page cache failed")
+	}
+
+	if pc.precompress {
+		err = pc.setCompressed(key, buf.Bytes())
+		if err != nil {
+			t.ctxLog(c).Error().Err(err).Str("name", tmpl.name).Msg("write compressed page cache failed")
+		}
+
+		if ps != nil {
+			if body, encoding, ok := pc.getCompressed(ps.accept, key); ok {
+				ps.encoding = encoding
+				_, err = w.Write(body)
+
+				return true, err
+			}
+		}
+	}
+
+	_, err = w.Write(buf.Bytes())
+
+	return true, err
+}
+
+// pageCacheKey returns the key of the rendered output, which is false if the data can't be hashed.
+func pageCacheKey(tmpl *Template, block, locale string, data any) (string, bool) {
+	h := sha256.New()
+
+	b, err := json.Marshal(data)
+	if err != nil || !hashable(data, b) {
+		return "", false
+	}
+
+	for _, s := range []string{fmt.Sprintf("%T", data), string(b), block, locale, strings.Join(tmpl.layoutFiles, ",")} {
+		h.Write([]byte(s))
+		h.Write([]byte{0})
+	}
+
+	return pageCachePrefix(tmpl.name) + hex.EncodeToString(h.Sum(nil)), true
+}
+
+// hashable reports whether the JSON encoding identifies the data, it doesn't for a struct with only
+// unexported fields which is encoded as an empty object.
+func hashable(data any, b []byte) bool {
+	if string(b) != "{}" {
+		return true
+	}
+
+	v := reflect.Indirect(reflect.ValueOf(data))
+
+	return v.Kind() != reflect.Struct || v.NumField() == 0
+}
+
+// callsContextFuncs reports whether the templates call any of the request scoped functions.
+func callsContextFuncs(e engine, ctxFuncs []func(c echo.Context) template.FuncMap) bool {
+	if len(ctxFuncs) == 0 {
+		return false
+	}
+
+	names := make(map[string]bool)
+
+	for _, fn := range ctxFuncs {
+		for name := range fn(nil) {
+			names[name] = true
+		}
+	}
+
+	found := false
+
+	for _, tree := range e.Trees() {
+		if tree == nil {
+			continue
+		}
+
+		walkNodes(tree.Root, func(node parse.Node) {
+			switch n := node.(type) {
+			case *parse.ActionNode:
+				found = found || pipeCalls(n.Pipe, names)
+			case *parse.IfNode:
+				found = found || pipeCalls(n.Pipe, names)
+			case *parse.RangeNode:
+				found = found || pipeCalls(n.Pipe, names)
+			case *parse.WithNode:
+				found = found || pipeCalls(n.Pipe, names)
+			case *parse.TemplateNode:
+				found = found || pipeCalls(n.Pipe, names)
+			}
+		})
+
+		if found {
+			return true
+		}
+	}
+
+	return false
+}
+
+// pipeCalls reports whether the pipeline calls any of the named functions.
+func pipeCalls(pipe *parse.PipeNode, names map[string]bool) bool {
+	if pipe == nil {
+		return false
+	}
+
+	for _, cmd := range pipe.Cmds {
+		for _, arg := range cmd.Args {
+			switch n := arg.(type) {
+			case *parse.IdentifierNode:
+				if names[n.Ident] {
+					return true
+				}
+			case *parse.PipeNode:
+				if pipeCalls(n, names) {
+					return true
+				}
+			case *parse.ChainNode:
+				if p, ok := n.Node.(*parse.PipeNode); ok && pipeCalls(p, names) {
+					return true
+				}
+			}
+		}
+	}
+
+	return false
+}
+
+func pageCachePrefix(name string) string {
+	return "page:" + name + ":"
+}
+
+// NewMemoryPageStore returns an in-memory PageCacheStore holding up to size pages, evicting the least
+// recently used page once full.
+func NewMemoryPageStore(size int) PageCacheStore {
+	return &memoryPageStore{pages: newLRU[[]byte](size)}
+}
+
+type memoryPageStore struct {
+	pages *lru[[]byte]
+}
+
+func (s *memoryPageStore) Get(key string) ([]byte, bool, error) {
+	body, ok := s.pages.get(key)
+	return body, ok, nil
+}
+
+func (s *memoryPageStore) Set(key string, body []byte, ttl time.Duration) error {
+	s.pages.set(key, append([]byte(nil), body...), ttl)
+	return nil
+}
+
+func (s *memoryPageStore) DeletePrefix(prefix string) error {
+	s.pages.deletePrefix(prefix)
+	return nil
+}
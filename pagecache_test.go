@@ -0,0 +1,312 @@
+package templates_test
+
+import (
+	"bytes"
+	"html/template"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"testing/fstest"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/require"
+	templates "github.com/wolfeidau/echo-go-templates"
+)
+
+func Test_WithPageCache(t *testing.T) {
+	assert := require.New(t)
+
+	calls := 0
+
+	fsys := fstest.MapFS{
+		"index.html": {Data: []byte(`{{count}} {{.name}}`)},
+		"other.html": {Data: []byte(`{{count}} other`)},
+	}
+
+	render := templates.New(
+		templates.WithFuncs(template.FuncMap{"count": func() int { calls++; return calls }}),
+		templates.WithPageCache(templates.PageCacheConfig{}),
+	)
+
+	err := render.Add(fsys, "*.html")
+	assert.NoError(err)
+
+	for i := 0; i < 2; i++ {
+		s, err := render.RenderString("index.html", map[string]any{"name": "a"})
+		assert.NoError(err)
+		assert.Equal("1 a", s)
+	}
+
+	s, err := render.RenderString("index.html", map[string]any{"name": "b"})
+	assert.NoError(err)
+	assert.Equal("2 b", s)
+
+	s, err = render.RenderString("other.html", nil)
+	assert.NoError(err)
+	assert.Equal("3 other", s)
+
+	err = render.Invalidate("index.html")
+	assert.NoError(err)
+
+	s, err = render.RenderString("index.html", map[string]any{"name": "a"})
+	assert.NoError(err)
+	assert.Equal("4 a", s)
+
+	s, err = render.RenderString("other.html", nil)
+	assert.NoError(err)
+	assert.Equal("3 other", s)
+
+	// data which can't be marshalled isn't cached
+	for _, expected := range []string{"5 a", "6 a"} {
+		s, err = render.RenderString("index.html", map[string]any{"name": "a", "fn": func() {}})
+		assert.NoError(err)
+		assert.Equal(expected, s)
+	}
+}
+
+func Test_WithPageCacheSkip(t *testing.T) {
+	assert := require.New(t)
+
+	calls := 0
+
+	fsys := fstest.MapFS{
+		"index.html": {Data: []byte(`{{count}}`)},
+	}
+
+	render := templates.New(
+		templates.WithFuncs(template.FuncMap{"count": func() int { calls++; return calls }}),
+		templates.WithPageCache(templates.PageCacheConfig{
+			Skip: func(c echo.Context) bool { return c != nil && c.Request().URL.Query().Has("nocache") },
+		}),
+	)
+
+	err := render.Add(fsys, "*.html")
+	assert.NoError(err)
+
+	e := echo.New()
+
+	for _, target := range []string{"/", "/", "/?nocache", "/"} {
+		rec := httptest.NewRecorder()
+		c := e.NewContext(httptest.NewRequest(http.MethodGet, target, nil), rec)
+
+		err = render.Render(rec, "index.html", nil, c)
+		assert.NoError(err)
+	}
+
+	assert.Equal(2, calls)
+}
+
+func Test_WithPageCache_Group(t *testing.T) {
+	assert := require.New(t)
+
+	calls := 0
+
+	fsys := fstest.MapFS{
+		"docs/index.html": {Data: []byte(`docs {{count}}`)},
+		"live.html":       {Data: []byte(`live {{count}}`)},
+	}
+
+	render := templates.New(templates.WithFuncs(template.FuncMap{"count": func() int { calls++; return calls }}))
+
+	err := render.Group(templates.WithPageCache(templates.PageCacheConfig{})).Add(fsys, "docs/*.html")
+	assert.NoError(err)
+
+	err = render.Add(fsys, "live.html")
+	assert.NoError(err)
+
+	for i := 0; i < 2; i++ {
+		out, err := render.RenderString("index.html", nil)
+		assert.NoError(err)
+		assert.Equal("docs 1", out)
+	}
+
+	out, err := render.RenderString("live.html", nil)
+	assert.NoError(err)
+	assert.Equal("live 2", out)
+
+	out, err = render.RenderString("live.html", nil)
+	assert.NoError(err)
+	assert.Equal("live 3", out)
+
+	assert.NoError(render.Invalidate("index.html"))
+
+	out, err = render.RenderString("index.html", nil)
+	assert.NoError(err)
+	assert.Equal("docs 4", out)
+}
+
+func Test_WithPageCache_ETag(t *testing.T) {
+	assert := require.New(t)
+
+	calls := 0
+
+	fsys := fstest.MapFS{
+		"index.html": {Data: []byte(`{{count}}<p>{{.Data}}</p>`)},
+	}
+
+	hook := &recordingHook{}
+	events := 0
+
+	render := templates.New(
+		templates.WithFuncs(template.FuncMap{"count": func() string { calls++; return "" }}),
+		templates.WithPageCache(templates.PageCacheConfig{}),
+		templates.WithETag(),
+		templates.WithHook(hook),
+		templates.WithRenderEvents(func(templates.RenderEvent) { events++ }),
+	)
+
+	err := render.Add(fsys, "*.html")
+	assert.NoError(err)
+
+	e := echo.New()
+	e.Renderer = render
+	e.GET("/", func(c echo.Context) error {
+		return c.Render(http.StatusOK, "index.html", "a")
+	})
+
+	get := func(ifNoneMatch string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		if ifNoneMatch != "" {
+			req.Header.Set("If-None-Match", ifNoneMatch)
+		}
+
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+
+		return rec
+	}
+
+	rec := get("")
+	assert.Equal(http.StatusOK, rec.Code)
+	assert.Equal("<p>a</p>", rec.Body.String())
+
+	etag := rec.Header().Get("ETag")
+	assert.NotEmpty(etag)
+
+	assert.NoError(render.Invalidate("index.html"))
+
+	// a miss matching the request still caches the page
+	rec = get(etag)
+	assert.Equal(http.StatusNotModified, rec.Code)
+	assert.Empty(rec.Body.String())
+	assert.Equal(etag, rec.Header().Get("ETag"))
+	assert.Equal(2, calls)
+
+	rec = get("")
+	assert.Equal(http.StatusOK, rec.Code)
+	assert.Equal("<p>a</p>", rec.Body.String())
+	assert.Equal(etag, rec.Header().Get("ETag"))
+
+	rec = get(etag)
+	assert.Equal(http.StatusNotModified, rec.Code)
+	assert.Empty(rec.Body.String())
+	assert.Equal(etag, rec.Header().Get("ETag"))
+
+	assert.Equal(2, calls)
+	assert.Len(hook.before, 4)
+	assert.Len(hook.after, 4)
+	assert.Equal(4, events)
+}
+
+type cacheTitle struct{ title string }
+
+func (p cacheTitle) Title() string { return p.title }
+
+type cacheHeading struct{ heading string }
+
+func (p cacheHeading) Title() string { return p.heading }
+
+func Test_WithPageCache_Key(t *testing.T) {
+	assert := require.New(t)
+
+	calls := 0
+
+	fsys := fstest.MapFS{
+		"user.html":   {Data: []byte(`{{count}} {{.user}}`)},
+		"title.html":  {Data: []byte(`{{count}} {{.Title}}`)},
+		"scoped.html": {Data: []byte(`{{count}} {{if user}}{{user}}{{end}}`)},
+		"plain.html":  {Data: []byte(`{{count}} plain`)},
+	}
+
+	render := templates.New(
+		templates.WithFuncs(template.FuncMap{"count": func() int { calls++; return calls }}),
+		templates.WithContextFuncs(func(c echo.Context) template.FuncMap {
+			return template.FuncMap{"user": func() string {
+				if c == nil {
+					return ""
+				}
+
+				return c.Request().Header.Get("X-User")
+			}}
+		}),
+		templates.WithDataDecorator(func(c echo.Context, data any) any {
+			if m, ok := data.(map[string]any); ok && c != nil {
+				m["user"] = c.Request().Header.Get("X-User")
+			}
+
+			return data
+		}),
+		templates.WithPageCache(templates.PageCacheConfig{}),
+	)
+
+	err := render.Add(fsys, "*.html")
+	assert.NoError(err)
+
+	e := echo.New()
+
+	renderAs := func(user, name string, data any) string {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("X-User", user)
+		c := e.NewContext(req, httptest.NewRecorder())
+
+		buf := new(bytes.Buffer)
+		assert.NoError(render.Render(buf, name, data, c))
+
+		return buf.String()
+	}
+
+	// values added by decorators are part of the key
+	assert.Equal("1 alice", renderAs("alice", "user.html", map[string]any{}))
+	assert.Equal("2 bob", renderAs("bob", "user.html", map[string]any{}))
+	assert.Equal("1 alice", renderAs("alice", "user.html", map[string]any{}))
+
+	// templates calling request scoped functions aren't cached
+	assert.Equal("3 alice", renderAs("alice", "scoped.html", nil))
+	assert.Equal("4 bob", renderAs("bob", "scoped.html", nil))
+	assert.Equal("5 plain", renderAs("alice", "plain.html", nil))
+	assert.Equal("5 plain", renderAs("bob", "plain.html", nil))
+
+	// structs with only unexported fields don't share the key of an empty object
+	assert.Equal("6 a", renderAs("", "title.html", cacheTitle{"a"}))
+	assert.Equal("7 b", renderAs("", "title.html", cacheTitle{"b"}))
+	assert.Equal("8 c", renderAs("", "title.html", cacheHeading{"c"}))
+}
+
+func Test_WithPageCache_ViewModel(t *testing.T) {
+	assert := require.New(t)
+
+	calls := 0
+
+	fsys := fstest.MapFS{
+		"index.html": {Data: []byte(`{{count}} {{.Data}}`)},
+	}
+
+	render := templates.New(
+		templates.WithFuncs(template.FuncMap{"count": func() int { calls++; return calls }}),
+		templates.WithViewModel(),
+		templates.WithPageCache(templates.PageCacheConfig{}),
+	)
+
+	err := render.Add(fsys, "*.html")
+	assert.NoError(err)
+
+	e := echo.New()
+
+	for _, expected := range []string{"1 a", "2 a"} {
+		c := e.NewContext(httptest.NewRequest(http.MethodGet, "/", nil), httptest.NewRecorder())
+
+		buf := new(bytes.Buffer)
+		assert.NoError(render.Render(buf, "index.html", "a", c))
+		assert.Equal(expected, buf.String())
+	}
+}
@@ -0,0 +1,183 @@
+package templates
+
+import (
+	"html/template"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
+)
+
+// PageParam is the default query parameter holding the page number.
+const PageParam = "page"
+
+// Paginator calculates the pages of a list, and builds the URLs of each page preserving the other query
+// parameters of the request.
+type Paginator struct {
+	Page    int // current page, starting at 1
+	PerPage int
+	Total   int // total number of items
+	Param   string
+	base    url.URL
+}
+
+// NewPaginator returns a paginator for the request, reading the current page from the page query
+// parameter, which is clamped to the available pages.
+func NewPaginator(c echo.Context, perPage, total int) *Paginator {
+	page, _ := strconv.Atoi(c.QueryParam(PageParam))
+
+	return NewPaginatorURL(c.Request().URL, page, perPage, total)
+}
+
+// NewPaginatorURL returns a paginator building page URLs from u, the page is clamped to the available pages.
+func NewPaginatorURL(u *url.URL, page, perPage, total int) *Paginator {
+	if perPage < 1 {
+		perPage = 1
+	}
+
+	if total < 0 {
+		total = 0
+	}
+
+	p := &Paginator{PerPage: perPage, Total: total, Param: PageParam, base: *u}
+
+	switch {
+	case page < 1:
+		p.Page = 1
+	case page > p.Pages():
+		p.Page = p.Pages()
+	default:
+		p.Page = page
+	}
+
+	return p
+}
+
+// Pages returns the number of pages, which is at least one.
+func (p *Paginator) Pages() int {
+	if p.Total == 0 {
+		return 1
+	}
+
+	return (p.Total + p.PerPage - 1) / p.PerPage
+}
+
+// Offset returns the index of the first item on the current page, for use in a database query.
+func (p *Paginator) Offset() int {
+	return (p.Page - 1) * p.PerPage
+}
+
+// HasPrev returns true if there is a page before the current page.
+func (p *Paginator) HasPrev() bool {
+	return p.Page > 1
+}
+
+// HasNext returns true if there is a page after the current page.
+func (p *Paginator) HasNext() bool {
+	return p.Page < p.Pages()
+}
+
+// Prev returns the number of the previous page.
+func (p *Paginator) Prev() int {
+	return p.clamp(p.Page - 1)
+}
+
+// Next returns the number of the next page.
+func (p *Paginator) Next() int {
+	return p.clamp(p.Page + 1)
+}
+
+// URL returns the URL of the page, preserving the other query parameters. The first page is linked
+// without the page parameter.
+func (p *Paginator) URL(page int) string {
+	u := p.base
+	q := u.Query()
+
+	if page = p.clamp(page); page == 1 {
+		q.Del(p.Param)
+	} else {
+		q.Set(p.Param, strconv.Itoa(page))
+	}
+
+	u.RawQuery = q.Encode()
+	u.Scheme, u.Host, u.User = "", "", nil
+
+	return u.String()
+}
+
+// Window returns the page numbers to link to, the first and last pages and the pages within size of the
+// current page, with a zero marking each gap:
+//
+//	1 0 4 5 6 7 8 0 20
+func (p *Paginator) Window(size int) []int {
+	last := p.Pages()
+
+	var pages []int
+
+	for page := 1; page <= last; page++ {
+		if page == 1 || page == last || (page >= p.Page-size && page <= p.Page+size) {
+			pages = append(pages, page)
+			continue
+		}
+
+		if pages[len(pages)-1] != 0 {
+			pages = append(pages, 0)
+		}
+	}
+
+	return pages
+}
+
+func (p *Paginator) clamp(page int) int {
+	if page < 1 {
+		return 1
+	}
+
+	if last := p.Pages(); page > last {
+		return last
+	}
+
+	return page
+}
+
+var (
+	paginationOnce     sync.Once
+	paginationTemplate *template.Template
+	paginationErr      error
+)
+
+// WithPagination adds the pagination template function which renders the bundled pagination controls for
+// a Paginator, and the pageURL and pageWindow functions for building custom controls:
+//
+//	{{pagination .Pager}}
+//
+//	{{range pageWindow .Pager 3}}{{if .}}<a href="{{pageURL $.Pager .}}">{{.}}</a>{{else}}…{{end}}{{end}}
+func WithPagination() Option {
+	return WithFuncs(template.FuncMap{
+		"pageURL":    func(p *Paginator, page int) string { return p.URL(page) },
+		"pageWindow": func(p *Paginator, size int) []int { return p.Window(size) },
+		"pagination": renderPagination,
+	})
+}
+
+func renderPagination(p *Paginator) (template.HTML, error) {
+	paginationOnce.Do(func() {
+		paginationTemplate, paginationErr = template.ParseFS(builtinFS, "builtin/pagination.html")
+	})
+
+	if paginationErr != nil {
+		return "", errors.Wrap(paginationErr, "failed to parse pagination template")
+	}
+
+	buf := new(strings.Builder)
+
+	err := paginationTemplate.Execute(buf, p)
+	if err != nil {
+		return "", err
+	}
+
+	return template.HTML(buf.String()), nil //nolint:gosec // output of a template
+}
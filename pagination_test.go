@@ -0,0 +1,83 @@
+package templates_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"testing/fstest"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/require"
+	templates "github.com/wolfeidau/echo-go-templates"
+)
+
+func Test_Paginator(t *testing.T) {
+	assert := require.New(t)
+
+	u, err := url.Parse("https://example.com/users?q=tom&page=3")
+	assert.NoError(err)
+
+	p := templates.NewPaginatorURL(u, 10, 10, 195)
+	assert.Equal(10, p.Page)
+	assert.Equal(20, p.Pages())
+	assert.Equal(90, p.Offset())
+	assert.True(p.HasPrev())
+	assert.True(p.HasNext())
+	assert.Equal([]int{1, 0, 8, 9, 10, 11, 12, 0, 20}, p.Window(2))
+	assert.Equal("/users?page=11&q=tom", p.URL(p.Next()))
+	assert.Equal("/users?q=tom", p.URL(1))
+	assert.Equal("/users?page=20&q=tom", p.URL(99))
+
+	p = templates.NewPaginatorURL(u, 50, 10, 25)
+	assert.Equal(3, p.Page)
+	assert.False(p.HasNext())
+	assert.Equal([]int{1, 2, 3}, p.Window(2))
+
+	p = templates.NewPaginatorURL(u, -1, 10, 0)
+	assert.Equal(1, p.Page)
+	assert.Equal(1, p.Pages())
+	assert.Equal([]int{1}, p.Window(2))
+}
+
+func Test_WithPagination(t *testing.T) {
+	assert := require.New(t)
+
+	fsys := fstest.MapFS{
+		"users.html":  {Data: []byte(`{{pagination .}}`)},
+		"custom.html": {Data: []byte(`{{range pageWindow . 1}}{{if .}}<a href="{{pageURL $ .}}">{{.}}</a>{{else}}…{{end}}{{end}}`)},
+	}
+
+	render := templates.New(templates.WithPagination())
+
+	err := render.Add(fsys, "*.html")
+	assert.NoError(err)
+
+	e := echo.New()
+	c := e.NewContext(httptest.NewRequest(http.MethodGet, "/users?q=a&b&page=2", nil), httptest.NewRecorder())
+
+	p := templates.NewPaginator(c, 10, 45)
+	assert.Equal(2, p.Page)
+
+	s, err := render.RenderString("users.html", p)
+	assert.NoError(err)
+	assert.Equal(`<nav class="pagination" aria-label="Pagination">
+  <ul>
+    <li><a href="/users?b=&amp;q=a" rel="prev">Previous</a></li>
+    <li><a href="/users?b=&amp;q=a">1</a></li>
+    <li><a href="/users?b=&amp;page=2&amp;q=a" aria-current="page">2</a></li>
+    <li><a href="/users?b=&amp;page=3&amp;q=a">3</a></li>
+    <li><a href="/users?b=&amp;page=4&amp;q=a">4</a></li>
+    <li><a href="/users?b=&amp;page=5&amp;q=a">5</a></li>
+    <li><a href="/users?b=&amp;page=3&amp;q=a" rel="next">Next</a></li>
+  </ul>
+</nav>`, s)
+
+	s, err = render.RenderString("custom.html", p)
+	assert.NoError(err)
+	assert.Equal(`<a href="/users?b=&amp;q=a">1</a><a href="/users?b=&amp;page=2&amp;q=a">2</a><a href="/users?b=&amp;page=3&amp;q=a">3</a>…<a href="/users?b=&amp;page=5&amp;q=a">5</a>`, s)
+
+	s, err = render.RenderString("users.html", templates.NewPaginator(c, 10, 5))
+	assert.NoError(err)
+	assert.Empty(s)
+}
@@ -0,0 +1,168 @@
+package templates
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	encodingGzip   = "gzip"
+	encodingBrotli = "br"
+
+	precompressStateKey = "templates.precompress"
+)
+
+// Serve renders the named template to the response like c.Render, sending the pre-compressed copy of
+// the page stored by a page cache with Precompress enabled when the client accepts it:
+//
+//	return render.Serve(c, http.StatusOK, "docs.html", page)
+//
+// The Gzip middleware compresses the response again, so skip it for routes serving cached pages. With
+// WithETag the ETag is computed from the body sent, so each compressed copy has its own ETag.
+func (t *TemplateRenderer) Serve(c echo.Context, code int, name string, data any) error {
+	ps := &precompressState{accept: c.Request().Header.Get(echo.HeaderAcceptEncoding)}
+
+	c.Set(precompressStateKey, ps)
+	defer c.Set(precompressStateKey, nil)
+
+	buf := new(bytes.Buffer)
+
+	err := t.render(buf, name, data, c)
+	if err != nil {
+		return err
+	}
+
+	if ps.vary {
+		addVary(c.Response().Header(), echo.HeaderAcceptEncoding)
+	}
+
+	if ps.encoding != "" {
+		c.Response().Header().Set(echo.HeaderContentEncoding, ps.encoding)
+	}
+
+	return t.blob(c, code, echo.MIMETextHTMLCharsetUTF8, buf.Bytes())
+}
+
+// precompressState is stored in the context of a page rendered by Serve, so the page cache can write a
+// pre-compressed copy of the page. The encoding is set when a compressed copy is written.
+type precompressState struct {
+	accept   string // Accept-Encoding header of the request
+	encoding string
+	vary     bool // the output depends on the Accept-Encoding header
+}
+
+// precompressStateFrom returns the state of the page being rendered by Serve, which is nil for other
+// renders.
+func precompressStateFrom(c echo.Context) *precompressState {
+	if c == nil {
+		return nil
+	}
+
+	ps, _ := c.Get(precompressStateKey).(*precompressState)
+
+	return ps
+}
+
+// getCompressed returns the compressed copy of the cached page preferred by the client along with its
+// encoding, returning false if the client prefers the page uncompressed or the copies aren't cached.
+func (pc *pageCache) getCompressed(accept, key string) ([]byte, string, bool) {
+	if accept == "" {
+		return nil, "", false
+	}
+
+	identity := encodingQuality(accept, "identity")
+
+	encodings := pc.encodings()
+
+	// the order of preference breaks ties between encodings with the same quality
+	sort.SliceStable(encodings, func(i, j int) bool {
+		return encodingQuality(accept, encodings[i]) > encodingQuality(accept, encodings[j])
+	})
+
+	for _, encoding := range encodings {
+		q := encodingQuality(accept, encoding)
+		if q <= 0 || q < identity {
+			break
+		}
+
+		body, ok, err := pc.store.Get(key + ":" + encoding)
+		if err != nil || !ok {
+			continue
+		}
+
+		return body, encoding, true
+	}
+
+	return nil, "", false
+}
+
+// encodingQuality returns the quality of the content coding in the Accept-Encoding header, codings
+// which aren't listed use the quality of the "*" element if there is one. The identity coding is
+// acceptable unless it is excluded.
+func encodingQuality(accept, coding string) float64 {
+	wildcard := -1.0
+
+	for _, part := range strings.Split(accept, ",") {
+		params := strings.Split(part, ";")
+
+		switch strings.ToLower(strings.TrimSpace(params[0])) {
+		case coding:
+			return qValue(params[1:])
+		case "*":
+			wildcard = qValue(params[1:])
+		}
+	}
+
+	if wildcard >= 0 {
+		return wildcard
+	}
+
+	if coding == "identity" {
+		return 1
+	}
+
+	return 0
+}
+
+// setCompressed stores the compressed copies of the page.
+func (pc *pageCache) setCompressed(key string, body []byte) error {
+	for _, encoding := range pc.encodings() {
+		buf := new(bytes.Buffer)
+
+		var zw io.WriteCloser = gzip.NewWriter(buf)
+		if encoding == encodingBrotli {
+			zw = pc.brotli(buf)
+		}
+
+		_, err := zw.Write(body)
+		if err != nil {
+			return err
+		}
+
+		err = zw.Close()
+		if err != nil {
+			return err
+		}
+
+		err = pc.store.Set(key+":"+encoding, buf.Bytes(), pc.ttl)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// encodings returns the compressed copies stored, in order of preference.
+func (pc *pageCache) encodings() []string {
+	if pc.brotli != nil {
+		return []string{encodingBrotli, encodingGzip}
+	}
+
+	return []string{encodingGzip}
+}
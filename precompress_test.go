@@ -0,0 +1,212 @@
+package templates_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"html/template"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"testing/fstest"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/require"
+	templates "github.com/wolfeidau/echo-go-templates"
+)
+
+// upperWriter stands in for a brotli writer.
+type upperWriter struct {
+	w io.Writer
+}
+
+func (u upperWriter) Write(p []byte) (int, error) { return u.w.Write(bytes.ToUpper(p)) }
+func (u upperWriter) Close() error                { return nil }
+
+func Test_Serve_Precompress(t *testing.T) {
+	assert := require.New(t)
+
+	calls := 0
+
+	fsys := fstest.MapFS{
+		"index.html": {Data: []byte(`<p>page {{count}}</p>`)},
+	}
+
+	render := templates.New(
+		templates.WithFuncs(template.FuncMap{"count": func() int { calls++; return calls }}),
+		templates.WithPageCache(templates.PageCacheConfig{
+			Precompress: true,
+			Brotli:      func(w io.Writer) io.WriteCloser { return upperWriter{w} },
+		}),
+	)
+
+	err := render.Add(fsys, "*.html")
+	assert.NoError(err)
+
+	e := echo.New()
+
+	serve := func(acceptEncoding string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/", http.NoBody)
+		if acceptEncoding != "" {
+			req.Header.Set(echo.HeaderAcceptEncoding, acceptEncoding)
+		}
+
+		rec := httptest.NewRecorder()
+
+		err := render.Serve(e.NewContext(req, rec), http.StatusOK, "index.html", nil)
+		assert.NoError(err)
+		assert.Equal(http.StatusOK, rec.Code)
+		assert.Equal(echo.HeaderAcceptEncoding, rec.Header().Get(echo.HeaderVary))
+		assert.Equal(echo.MIMETextHTMLCharsetUTF8, rec.Header().Get(echo.HeaderContentType))
+
+		return rec
+	}
+
+	rec := serve("gzip, deflate")
+	assert.Equal("gzip", rec.Header().Get(echo.HeaderContentEncoding))
+
+	zr, err := gzip.NewReader(rec.Body)
+	assert.NoError(err)
+
+	body, err := io.ReadAll(zr)
+	assert.NoError(err)
+	assert.Equal("<p>page 1</p>", string(body))
+
+	rec = serve("gzip, br")
+	assert.Equal("br", rec.Header().Get(echo.HeaderContentEncoding))
+	assert.Equal("<P>PAGE 1</P>", rec.Body.String())
+
+	rec = serve("")
+	assert.Empty(rec.Header().Get(echo.HeaderContentEncoding))
+	assert.Equal("<p>page 1</p>", rec.Body.String())
+
+	rec = serve("br;q=0, gzip;q=0")
+	assert.Empty(rec.Header().Get(echo.HeaderContentEncoding))
+	assert.Equal("<p>page 1</p>", rec.Body.String())
+
+	assert.Equal(1, calls)
+
+	// without the page cache the page is rendered uncompressed
+	render = templates.New(templates.WithFuncs(template.FuncMap{"count": func() int { return 0 }}))
+
+	err = render.Add(fsys, "*.html")
+	assert.NoError(err)
+
+	req := httptest.NewRequest(http.MethodGet, "/", http.NoBody)
+	req.Header.Set(echo.HeaderAcceptEncoding, "gzip")
+	rec = httptest.NewRecorder()
+
+	err = render.Serve(e.NewContext(req, rec), http.StatusCreated, "index.html", nil)
+	assert.NoError(err)
+	assert.Equal(http.StatusCreated, rec.Code)
+	assert.Empty(rec.Header().Get(echo.HeaderContentEncoding))
+	assert.Empty(rec.Header().Get(echo.HeaderVary))
+	assert.Equal("<p>page 0</p>", rec.Body.String())
+}
+
+func Test_Serve_ETag(t *testing.T) {
+	assert := require.New(t)
+
+	fsys := fstest.MapFS{
+		"index.html": {Data: []byte(`<p>page</p>`)},
+	}
+
+	render := templates.New(
+		templates.WithETag(),
+		templates.WithPageCache(templates.PageCacheConfig{Precompress: true}),
+	)
+
+	err := render.Add(fsys, "*.html")
+	assert.NoError(err)
+
+	e := echo.New()
+	e.GET("/", func(c echo.Context) error {
+		return render.Serve(c, http.StatusOK, "index.html", nil)
+	})
+
+	serve := func(acceptEncoding, ifNoneMatch string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/", http.NoBody)
+		req.Header.Set(echo.HeaderAcceptEncoding, acceptEncoding)
+		req.Header.Set("If-None-Match", ifNoneMatch)
+
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+
+		return rec
+	}
+
+	rec := serve("", "")
+	assert.Equal(http.StatusOK, rec.Code)
+	assert.Equal("<p>page</p>", rec.Body.String())
+
+	plain := rec.Header().Get("ETag")
+
+	rec = serve("gzip", "")
+	assert.Equal(http.StatusOK, rec.Code)
+	assert.Equal("gzip", rec.Header().Get(echo.HeaderContentEncoding))
+
+	gzipped := rec.Header().Get("ETag")
+	assert.NotEmpty(gzipped)
+	assert.NotEqual(plain, gzipped)
+
+	// the ETag of the uncompressed page doesn't match the compressed copy
+	rec = serve("gzip", plain)
+	assert.Equal(http.StatusOK, rec.Code)
+	assert.Equal(gzipped, rec.Header().Get("ETag"))
+
+	for _, tt := range []struct{ acceptEncoding, etag string }{{"", plain}, {"gzip", gzipped}} {
+		rec = serve(tt.acceptEncoding, tt.etag)
+		assert.Equal(http.StatusNotModified, rec.Code)
+		assert.Empty(rec.Body.String())
+		assert.Equal(tt.etag, rec.Header().Get("ETag"))
+	}
+}
+
+func Test_Serve_AcceptEncoding(t *testing.T) {
+	assert := require.New(t)
+
+	fsys := fstest.MapFS{
+		"index.html": {Data: []byte(`<p>page</p>`)},
+	}
+
+	render := templates.New(
+		templates.WithPageCache(templates.PageCacheConfig{
+			Precompress: true,
+			Brotli:      func(w io.Writer) io.WriteCloser { return upperWriter{w} },
+		}),
+	)
+
+	err := render.Add(fsys, "*.html")
+	assert.NoError(err)
+
+	e := echo.New()
+
+	tests := []struct {
+		accept   string
+		encoding string
+	}{
+		{"", ""},
+		{"gzip", "gzip"},
+		{"GZIP", "gzip"},
+		{"gzip;q=0", ""},
+		{"*", "br"},
+		{"*;q=0.5, br;q=0", "gzip"},
+		{"*;q=0, gzip", "gzip"},
+		{"br, gzip;q=0.5", "br"},
+		{"br;q=0.5, gzip", "gzip"},
+		{"gzip;q=0.5, identity", ""},
+		{"identity;q=0", ""},
+		{"identity;q=0, *", "br"},
+		{"deflate", ""},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, "/", http.NoBody)
+		req.Header.Set(echo.HeaderAcceptEncoding, tt.accept)
+		rec := httptest.NewRecorder()
+
+		err := render.Serve(e.NewContext(req, rec), http.StatusOK, "index.html", nil)
+		assert.NoError(err)
+		assert.Equal(tt.encoding, rec.Header().Get(echo.HeaderContentEncoding), tt.accept)
+	}
+}
@@ -0,0 +1,166 @@
+package templates
+
+import "sync"
+
+// op applies a change to a set of templates using the options of the renderer, successful ops are
+// recorded so they can be replayed when the templates are reloaded.
+type op func(t *TemplateRenderer, templates map[string]*Template) error
+
+// recordedOp is an op along with the renderer which applied it, and the names of the templates it
+// registered or replaced, and removed, when it was first applied.
+type recordedOp struct {
+	owner      *TemplateRenderer
+	apply      op
+	registered []string
+	removed    []string
+}
+
+// registry holds the registered templates, it is shared by a renderer and its groups.
+//
+// Changes are applied to a copy of the templates which is swapped in once complete, so
+// renders are never blocked while templates are being parsed.
+type registry struct {
+	writeMu   sync.Mutex // serialises changes to the registry
+	mu        sync.RWMutex
+	templates map[string]*Template
+	ops       []recordedOp
+	stats     *stats
+	memory    *memoryLoader
+	expected  sync.Map // template name to the reflect.Type of its data
+}
+
+func newRegistry() *registry {
+	return &registry{
+		templates: make(map[string]*Template),
+		stats:     &stats{templates: make(map[string]*TemplateStats)},
+		memory:    newMemoryLoader(),
+	}
+}
+
+// lookup returns the template registered with the name.
+func (r *registry) lookup(name string) (*Template, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	tmpl, ok := r.templates[name]
+
+	return tmpl, ok
+}
+
+// update applies the op for the owner to a copy of the registered templates, swapping in the copy and
+// recording the op if it succeeds.
+func (r *registry) update(owner *TemplateRenderer, o op) error {
+	r.writeMu.Lock()
+	defer r.writeMu.Unlock()
+
+	templates := make(map[string]*Template, len(r.templates))
+	for k, v := range r.templates {
+		templates[k] = v
+	}
+
+	err := o(owner, templates)
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	registered, removed := changedNames(r.templates, templates)
+	r.ops = compactOps(append(r.ops, recordedOp{owner: owner, apply: o, registered: registered, removed: removed}))
+	r.templates = templates
+	r.mu.Unlock()
+
+	return nil
+}
+
+// replay applies all the recorded ops to an empty set of templates, swapping it in if they all succeed.
+func (r *registry) replay() error {
+	r.writeMu.Lock()
+	defer r.writeMu.Unlock()
+
+	templates := make(map[string]*Template)
+
+	for _, o := range r.ops {
+		err := o.apply(o.owner, templates)
+		if err != nil {
+			return err
+		}
+	}
+
+	r.mu.Lock()
+	r.templates = templates
+	r.mu.Unlock()
+
+	return nil
+}
+
+// changedNames returns the names of the templates registered or replaced, and removed, between the sets.
+func changedNames(before, after map[string]*Template) (registered, removed []string) {
+	for name, tmpl := range after {
+		if before[name] != tmpl {
+			registered = append(registered, name)
+		}
+	}
+
+	for name := range before {
+		if _, ok := after[name]; !ok {
+			removed = append(removed, name)
+		}
+	}
+
+	return registered, removed
+}
+
+// compactOps drops the ops which no longer affect the templates, so repeatedly replacing templates
+// doesn't grow the ops replayed by Reload. An op is dropped once every template it changed is replaced
+// or removed by a later op, and a remove is dropped if no earlier op registers the template.
+func compactOps(ops []recordedOp) []recordedOp {
+	var (
+		compacted []recordedOp
+		changed   = make(map[string]bool) // changed by a later op
+		effective = make([]map[string]bool, len(ops))
+	)
+
+	for i := len(ops) - 1; i >= 0; i-- {
+		effective[i] = make(map[string]bool)
+
+		for _, names := range [][]string{ops[i].registered, ops[i].removed} {
+			for _, name := range names {
+				if !changed[name] {
+					effective[i][name] = true
+				}
+
+				changed[name] = true
+			}
+		}
+	}
+
+	registered := make(map[string]bool)
+
+	for i, o := range ops {
+		needed := false
+
+		for _, name := range o.registered {
+			needed = needed || effective[i][name]
+		}
+
+		for _, name := range o.removed {
+			needed = needed || (effective[i][name] && registered[name])
+		}
+
+		if !needed {
+			continue
+		}
+
+		for _, name := range o.registered {
+			registered[name] = true
+		}
+
+		for _, name := range o.removed {
+			registered[name] = false
+		}
+
+		compacted = append(compacted, o)
+	}
+
+	return compacted
+}
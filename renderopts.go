@@ -0,0 +1,112 @@
+package templates
+
+import (
+	"bytes"
+	"io"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+// RenderOption configures a single render using RenderWithOptions.
+type RenderOption func(*renderOptions)
+
+type renderOptions struct {
+	status      int
+	contentType string
+	headers     [][2]string
+	layout      *string
+}
+
+// RenderStatus sets the status code of the response, replacing the code passed to c.Render.
+func RenderStatus(code int) RenderOption {
+	return func(o *renderOptions) {
+		o.status = code
+	}
+}
+
+// RenderContentType sets the content type of the response.
+func RenderContentType(contentType string) RenderOption {
+	return func(o *renderOptions) {
+		o.contentType = contentType
+	}
+}
+
+// RenderHeader adds a header to the response.
+func RenderHeader(key, value string) RenderOption {
+	return func(o *renderOptions) {
+		o.headers = append(o.headers, [2]string{key, value})
+	}
+}
+
+// RenderLayout renders the template using the layout in place of the layout it was registered with, as
+// RenderWithLayout does, an empty layout renders the template without a layout.
+func RenderLayout(layout string) RenderOption {
+	return func(o *renderOptions) {
+		o.layout = &layout
+	}
+}
+
+// RenderWithOptions renders a template document as Render does, with the options applied to this render:
+//
+//	err := render.RenderWithOptions(c.Response(), "signup.html", form, c,
+//		templates.RenderStatus(http.StatusUnprocessableEntity),
+//		templates.RenderHeader("HX-Retarget", "#form"),
+//	)
+//
+// The output is buffered, and the options changing the response are applied once the render succeeds,
+// so a failed render is sent by the error handler without them. They are applied when the response is
+// written, so they also apply when the output is buffered by c.Render, and are ignored when c is nil.
+func (t *TemplateRenderer) RenderWithOptions(w io.Writer, name string, data any, c echo.Context, opts ...RenderOption) error {
+	o := &renderOptions{}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	if c == nil {
+		return t.renderWithOptions(w, name, data, c, o)
+	}
+
+	buf := new(bytes.Buffer)
+
+	err := t.renderWithOptions(buf, name, data, c, o)
+	if err != nil {
+		return sendNotFound(c, err)
+	}
+
+	o.apply(c.Response())
+
+	return t.withETag(w, c, func(w io.Writer) error {
+		_, err := buf.WriteTo(w)
+		return err
+	})
+}
+
+func (t *TemplateRenderer) renderWithOptions(w io.Writer, name string, data any, c echo.Context, o *renderOptions) error {
+	if o.layout == nil {
+		return t.render(w, name, data, c)
+	}
+
+	name, block, _ := strings.Cut(name, "#")
+	if block != "" {
+		return t.renderBlock(w, name, block, data, c)
+	}
+
+	return t.renderWithLayout(w, name, *o.layout, data, c)
+}
+
+func (o *renderOptions) apply(res *echo.Response) {
+	for _, h := range o.headers {
+		res.Header().Add(h[0], h[1])
+	}
+
+	if o.contentType != "" {
+		res.Header().Set(echo.HeaderContentType, o.contentType)
+	}
+
+	if o.status != 0 {
+		res.Before(func() {
+			res.Status = o.status
+		})
+	}
+}
@@ -0,0 +1,98 @@
+package templates_test
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"testing/fstest"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/require"
+	templates "github.com/wolfeidau/echo-go-templates"
+)
+
+func Test_RenderWithOptions(t *testing.T) {
+	assert := require.New(t)
+
+	fsys := fstest.MapFS{
+		"layout.html": {Data: []byte(`<main>{{block "content" .}}{{end}}</main>`)},
+		"other.html":  {Data: []byte(`<div>{{block "content" .}}{{end}}</div>`)},
+		"signup.html": {Data: []byte(`{{define "content"}}signup {{.}}{{end}}`)},
+	}
+
+	render := templates.New()
+
+	err := render.AddWithLayout(fsys, "layout.html", "signup.html")
+	assert.NoError(err)
+
+	e := echo.New()
+	e.GET("/direct", func(c echo.Context) error {
+		return render.RenderWithOptions(c.Response(), "signup.html", "form", c,
+			templates.RenderStatus(http.StatusUnprocessableEntity),
+			templates.RenderContentType(echo.MIMETextHTML),
+			templates.RenderHeader("HX-Retarget", "#form"),
+		)
+	})
+	e.GET("/buffered", func(c echo.Context) error {
+		buf := new(bytes.Buffer)
+
+		err := render.RenderWithOptions(buf, "signup.html", "form", c,
+			templates.RenderStatus(http.StatusAccepted),
+			templates.RenderLayout("other.html"),
+		)
+		if err != nil {
+			return err
+		}
+
+		return c.HTMLBlob(http.StatusOK, buf.Bytes())
+	})
+	e.GET("/nolayout", func(c echo.Context) error {
+		return render.RenderWithOptions(c.Response(), "signup.html#content", "form", c, templates.RenderLayout(""))
+	})
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/direct", nil))
+	assert.Equal(http.StatusUnprocessableEntity, rec.Code)
+	assert.Equal(echo.MIMETextHTML, rec.Header().Get(echo.HeaderContentType))
+	assert.Equal("#form", rec.Header().Get("HX-Retarget"))
+	assert.Equal("<main>signup form</main>", rec.Body.String())
+
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/buffered", nil))
+	assert.Equal(http.StatusAccepted, rec.Code)
+	assert.Equal(echo.MIMETextHTMLCharsetUTF8, rec.Header().Get(echo.HeaderContentType))
+	assert.Equal("<div>signup form</div>", rec.Body.String())
+
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/nolayout", nil))
+	assert.Equal("signup form", rec.Body.String())
+}
+
+func Test_RenderWithOptions_Error(t *testing.T) {
+	assert := require.New(t)
+
+	fsys := fstest.MapFS{
+		"broken.html": {Data: []byte(`<p>{{.Missing}}</p>`)},
+	}
+
+	render := templates.New()
+
+	err := render.Add(fsys, "*.html")
+	assert.NoError(err)
+
+	e := echo.New()
+	e.GET("/", func(c echo.Context) error {
+		return render.RenderWithOptions(c.Response(), "broken.html", "form", c,
+			templates.RenderStatus(http.StatusUnprocessableEntity),
+			templates.RenderHeader("HX-Retarget", "#form"),
+		)
+	})
+
+	// the options aren't applied to the error response
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.Equal(http.StatusInternalServerError, rec.Code)
+	assert.Empty(rec.Header().Get("HX-Retarget"))
+	assert.Equal(`{"message":"Internal Server Error"}`+"\n", rec.Body.String())
+}
@@ -0,0 +1,149 @@
+package templates
+
+import (
+	"html/template"
+	"net/url"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// Sanitizer removes unsafe markup from user generated HTML, such as BasicSanitizer or the Sanitize method
+// of a bluemonday policy:
+//
+//	render := templates.New(templates.WithSanitizer(bluemonday.UGCPolicy().Sanitize))
+type Sanitizer func(s string) string
+
+// WithSanitizer adds the sanitize template function, which passes user generated rich text through the
+// sanitizer and marks the result as safe HTML:
+//
+//	<div class="comment">{{sanitize .Comment.Body}}</div>
+//
+// Use this rather than safeHTML for anything which isn't written by the template author.
+func WithSanitizer(sanitize Sanitizer) Option {
+	return func(t *TemplateRenderer) {
+		t.templateFuncs["sanitize"] = func(s string) template.HTML {
+			return template.HTML(sanitize(s)) //nolint:gosec // output of the sanitizer
+		}
+	}
+}
+
+// sanitizeAllowed are the elements BasicSanitizer keeps, along with their allowed attributes.
+var sanitizeAllowed = map[atom.Atom][]string{
+	atom.A: {"href", "title"}, atom.Abbr: {"title"}, atom.B: nil, atom.Blockquote: nil, atom.Br: nil,
+	atom.Code: nil, atom.Del: nil, atom.Em: nil, atom.H1: nil, atom.H2: nil, atom.H3: nil, atom.H4: nil,
+	atom.H5: nil, atom.H6: nil, atom.Hr: nil, atom.I: nil, atom.Img: {"src", "alt", "title", "width", "height"},
+	atom.Li: nil, atom.Ol: nil, atom.P: nil, atom.Pre: nil, atom.S: nil, atom.Strong: nil, atom.Sub: nil,
+	atom.Sup: nil, atom.Table: nil, atom.Tbody: nil, atom.Td: nil, atom.Th: nil, atom.Thead: nil, atom.Tr: nil,
+	atom.U: nil, atom.Ul: nil,
+}
+
+// sanitizeDropped are the elements BasicSanitizer removes along with their content, other elements which
+// aren't allowed are removed leaving their content.
+var sanitizeDropped = map[atom.Atom]bool{
+	atom.Script: true, atom.Style: true, atom.Iframe: true, atom.Object: true, atom.Embed: true,
+	atom.Noscript: true, atom.Template: true, atom.Textarea: true, atom.Select: true, atom.Svg: true,
+	atom.Math: true, atom.Title: true,
+}
+
+// BasicSanitizer is a Sanitizer without any dependencies, suitable for user generated rich text such as
+// comments. It keeps a small set of formatting elements, links and images, removes every attribute other
+// than the few those need, drops links and images which aren't http, https, mailto or relative URLs, and
+// adds rel="nofollow noopener" to links.
+func BasicSanitizer(s string) string {
+	body := &html.Node{Type: html.ElementNode, Data: "body", DataAtom: atom.Body}
+
+	nodes, err := html.ParseFragment(strings.NewReader(s), body)
+	if err != nil {
+		return html.EscapeString(s)
+	}
+
+	var out strings.Builder
+
+	for _, n := range nodes {
+		sanitizeNode(&out, n)
+	}
+
+	return out.String()
+}
+
+func sanitizeNode(out *strings.Builder, n *html.Node) {
+	switch n.Type {
+	case html.TextNode:
+		out.WriteString(html.EscapeString(n.Data))
+		return
+	case html.ElementNode:
+	default:
+		return
+	}
+
+	if sanitizeDropped[n.DataAtom] {
+		return
+	}
+
+	attrs, allowed := sanitizeAllowed[n.DataAtom]
+	if allowed && !sanitizeURLs(n) {
+		allowed = false
+	}
+
+	if allowed {
+		out.WriteString("<" + n.Data)
+
+		for _, attr := range n.Attr {
+			if attr.Namespace == "" && contains(attrs, attr.Key) {
+				out.WriteString(" " + attr.Key + `="` + html.EscapeString(attr.Val) + `"`)
+			}
+		}
+
+		if n.DataAtom == atom.A {
+			out.WriteString(` rel="nofollow noopener"`)
+		}
+
+		out.WriteString(">")
+	}
+
+	for child := n.FirstChild; child != nil; child = child.NextSibling {
+		sanitizeNode(out, child)
+	}
+
+	if allowed && !isVoidElement(n.DataAtom) {
+		out.WriteString("</" + n.Data + ">")
+	}
+}
+
+// sanitizeURLs reports whether the URL attributes of the link or image are safe.
+func sanitizeURLs(n *html.Node) bool {
+	for _, attr := range n.Attr {
+		if attr.Key != "href" && attr.Key != "src" {
+			continue
+		}
+
+		u, err := url.Parse(strings.TrimSpace(attr.Val))
+		if err != nil {
+			return false
+		}
+
+		switch strings.ToLower(u.Scheme) {
+		case "", "http", "https", "mailto":
+		default:
+			return false
+		}
+	}
+
+	return true
+}
+
+func isVoidElement(a atom.Atom) bool {
+	return a == atom.Br || a == atom.Hr || a == atom.Img
+}
+
+func contains(values []string, v string) bool {
+	for _, s := range values {
+		if s == v {
+			return true
+		}
+	}
+
+	return false
+}
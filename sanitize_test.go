@@ -0,0 +1,61 @@
+package templates_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	templates "github.com/wolfeidau/echo-go-templates"
+)
+
+func Test_BasicSanitizer(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"formatting", `<p>Hello <strong>world</strong><br></p>`, `<p>Hello <strong>world</strong><br></p>`},
+		{"script", `<p>hi</p><script>alert(1)</script>`, `<p>hi</p>`},
+		{"event handler", `<p onclick="alert(1)" class="x">hi</p>`, `<p>hi</p>`},
+		{"link", `<a href="https://example.com" target="_blank">site</a>`, `<a href="https://example.com" rel="nofollow noopener">site</a>`},
+		{"javascript link", `<a href="javascript:alert(1)">click</a>`, `click`},
+		{"image", `<img src="/a.png" alt="a" onerror="alert(1)">`, `<img src="/a.png" alt="a">`},
+		{"unknown element", `<div><marquee>hi</marquee></div>`, `hi`},
+		{"text", `1 < 2 & "quoted"`, `1 &lt; 2 &amp; &#34;quoted&#34;`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, templates.BasicSanitizer(tt.in))
+		})
+	}
+}
+
+func Test_WithSanitizer(t *testing.T) {
+	assert := require.New(t)
+
+	render := templates.New(
+		templates.WithSanitizer(templates.BasicSanitizer),
+		templates.WithFuncs(templates.CommonFuncs()),
+	)
+
+	err := render.AddFromString("comment.html", "", `<div>{{sanitize .Body}}</div><div>{{safeHTML .Trusted}}</div>`)
+	assert.NoError(err)
+
+	out, err := render.RenderString("comment.html", map[string]string{
+		"Body":    `<em>nice</em><script>alert(1)</script>`,
+		"Trusted": `<b>ok</b>`,
+	})
+	assert.NoError(err)
+	assert.Equal(`<div><em>nice</em></div><div><b>ok</b></div>`, out)
+
+	// a custom sanitizer, such as a bluemonday policy
+	render = templates.New(templates.WithSanitizer(strings.ToUpper))
+
+	err = render.AddFromString("comment.html", "", `{{sanitize .}}`)
+	assert.NoError(err)
+
+	out, err = render.RenderString("comment.html", "<b>hi</b>")
+	assert.NoError(err)
+	assert.Equal("<B>HI</B>", out)
+}
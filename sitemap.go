@@ -0,0 +1,150 @@
+package templates
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+// SitemapMaxURLs is the maximum number of URLs in a sitemap, larger sitemaps are split using an index.
+const SitemapMaxURLs = 50000
+
+// SitemapURL is a page listed in a sitemap.
+type SitemapURL struct {
+	Loc        string
+	LastMod    time.Time
+	ChangeFreq string  // always, hourly, daily, weekly, monthly, yearly or never
+	Priority   float64 // 0.0 to 1.0, zero is omitted
+}
+
+// Sitemap collects the URLs of a site, it is safe for concurrent use so handlers and background jobs can
+// add URLs as content changes.
+type Sitemap struct {
+	mu   sync.RWMutex
+	urls map[string]SitemapURL
+}
+
+// NewSitemap returns an empty sitemap.
+func NewSitemap() *Sitemap {
+	return &Sitemap{urls: make(map[string]SitemapURL)}
+}
+
+// Add adds the URLs, replacing any with the same location.
+func (s *Sitemap) Add(urls ...SitemapURL) *Sitemap {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, u := range urls {
+		s.urls[u.Loc] = u
+	}
+
+	return s
+}
+
+// Remove removes the URL with the location.
+func (s *Sitemap) Remove(loc string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.urls, loc)
+}
+
+// URLs returns the URLs sorted by location.
+func (s *Sitemap) URLs() []SitemapURL {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	urls := make([]SitemapURL, 0, len(s.urls))
+	for _, u := range s.urls {
+		urls = append(urls, u)
+	}
+
+	sort.Slice(urls, func(i, j int) bool { return urls[i].Loc < urls[j].Loc })
+
+	return urls
+}
+
+// Pages returns the URLs split into pages of up to SitemapMaxURLs, each of which is written as a separate
+// sitemap listed in an index.
+func (s *Sitemap) Pages() [][]SitemapURL {
+	urls := s.URLs()
+
+	var pages [][]SitemapURL
+
+	for len(urls) > SitemapMaxURLs {
+		pages = append(pages, urls[:SitemapMaxURLs])
+		urls = urls[SitemapMaxURLs:]
+	}
+
+	return append(pages, urls)
+}
+
+// SitemapRef is a sitemap listed in a sitemap index.
+type SitemapRef struct {
+	Loc     string
+	LastMod time.Time
+}
+
+// WriteSitemap writes the URLs to w as a sitemap.
+func WriteSitemap(w io.Writer, urls []SitemapURL) error {
+	return executeBuiltin(w, "sitemap.xml", urls)
+}
+
+// WriteSitemapIndex writes a sitemap index listing the sitemaps to w.
+func WriteSitemapIndex(w io.Writer, sitemaps []SitemapRef) error {
+	return executeBuiltin(w, "sitemapindex.xml", sitemaps)
+}
+
+// RenderSitemap writes the URLs to the response as a sitemap, which is gzip compressed if the request path
+// ends with ".gz", such as /sitemap.xml.gz.
+//
+//	e.GET("/sitemap.xml", func(c echo.Context) error {
+//		return render.RenderSitemap(c, sitemap.URLs())
+//	})
+func (t *TemplateRenderer) RenderSitemap(c echo.Context, urls []SitemapURL) error {
+	return t.renderSitemap(c, "sitemap.xml", urls)
+}
+
+// RenderSitemapIndex writes a sitemap index listing the sitemaps to the response, which is gzip compressed
+// if the request path ends with ".gz".
+func (t *TemplateRenderer) RenderSitemapIndex(c echo.Context, sitemaps []SitemapRef) error {
+	return t.renderSitemap(c, "sitemapindex.xml", sitemaps)
+}
+
+func (t *TemplateRenderer) renderSitemap(c echo.Context, name string, data any) error {
+	buf := new(bytes.Buffer)
+
+	var (
+		w  io.Writer = buf
+		zw *gzip.Writer
+	)
+
+	if strings.HasSuffix(c.Request().URL.Path, ".gz") {
+		zw = gzip.NewWriter(buf)
+		w = zw
+	}
+
+	err := executeBuiltin(w, name, data)
+	if err != nil {
+		t.ctxLog(c).Error().Err(err).Str("name", name).Msg("render sitemap failed")
+		return err
+	}
+
+	if zw != nil {
+		err = zw.Close()
+		if err != nil {
+			return err
+		}
+
+		return c.Blob(http.StatusOK, "application/gzip", buf.Bytes())
+	}
+
+	return c.Blob(http.StatusOK, echo.MIMEApplicationXMLCharsetUTF8, buf.Bytes())
+}
@@ -0,0 +1,84 @@
+package templates_test
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/require"
+	templates "github.com/wolfeidau/echo-go-templates"
+)
+
+func Test_RenderSitemap(t *testing.T) {
+	assert := require.New(t)
+
+	sitemap := templates.NewSitemap().Add(
+		templates.SitemapURL{Loc: "https://example.com/b?x=1&y=2", Priority: 0.5, ChangeFreq: "daily"},
+		templates.SitemapURL{Loc: "https://example.com/a", LastMod: time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC)},
+	)
+
+	expected := `<?xml version="1.0" encoding="UTF-8"?>
+<urlset xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">
+  <url>
+    <loc>https://example.com/a</loc>
+    <lastmod>2023-06-01T00:00:00Z</lastmod>
+  </url>
+  <url>
+    <loc>https://example.com/b?x=1&amp;y=2</loc>
+    <changefreq>daily</changefreq>
+    <priority>0.5</priority>
+  </url>
+</urlset>
+`
+
+	render := templates.New()
+	e := echo.New()
+
+	rec := httptest.NewRecorder()
+	c := e.NewContext(httptest.NewRequest(http.MethodGet, "/sitemap.xml", nil), rec)
+
+	err := render.RenderSitemap(c, sitemap.URLs())
+	assert.NoError(err)
+	assert.Equal(echo.MIMEApplicationXMLCharsetUTF8, rec.Header().Get(echo.HeaderContentType))
+	assert.Equal(expected, rec.Body.String())
+
+	rec = httptest.NewRecorder()
+	c = e.NewContext(httptest.NewRequest(http.MethodGet, "/sitemap.xml.gz", nil), rec)
+
+	err = render.RenderSitemap(c, sitemap.URLs())
+	assert.NoError(err)
+	assert.Equal("application/gzip", rec.Header().Get(echo.HeaderContentType))
+
+	zr, err := gzip.NewReader(rec.Body)
+	assert.NoError(err)
+
+	data, err := io.ReadAll(zr)
+	assert.NoError(err)
+	assert.Equal(expected, string(data))
+}
+
+func Test_SitemapIndex(t *testing.T) {
+	assert := require.New(t)
+
+	sitemap := templates.NewSitemap()
+	for i := 0; i < templates.SitemapMaxURLs+1; i++ {
+		sitemap.Add(templates.SitemapURL{Loc: fmt.Sprintf("https://example.com/%d", i)})
+	}
+
+	pages := sitemap.Pages()
+	assert.Len(pages, 2)
+	assert.Len(pages[0], templates.SitemapMaxURLs)
+	assert.Len(pages[1], 1)
+
+	buf := new(strings.Builder)
+
+	err := templates.WriteSitemapIndex(buf, []templates.SitemapRef{{Loc: "https://example.com/sitemap-1.xml.gz"}})
+	assert.NoError(err)
+	assert.Contains(buf.String(), "<sitemap>\n    <loc>https://example.com/sitemap-1.xml.gz</loc>\n  </sitemap>")
+}
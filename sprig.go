@@ -0,0 +1,174 @@
+package templates
+
+import (
+	"fmt"
+	"html/template"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// SprigFuncs returns a curated subset of the Sprig template functions (https://masterminds.github.io/sprig/)
+// implemented without the dependency, with the same names and argument order so templates written for
+// Helm or Hugo work unchanged. Functions which touch the environment, file system, crypto or random
+// numbers are deliberately left out.
+//
+// The full Sprig library can be used instead by passing its FuncMap to WithFuncs:
+//
+//	render := templates.New(templates.WithFuncs(sprig.FuncMap()))
+func SprigFuncs() template.FuncMap {
+	return template.FuncMap{
+		// strings
+		"trim":       strings.TrimSpace,
+		"trimAll":    func(cutset, s string) string { return strings.Trim(s, cutset) },
+		"trimPrefix": func(prefix, s string) string { return strings.TrimPrefix(s, prefix) },
+		"trimSuffix": func(suffix, s string) string { return strings.TrimSuffix(s, suffix) },
+		"upper":      strings.ToUpper,
+		"lower":      strings.ToLower,
+		"title":      title,
+		"replace":    func(old, replacement, s string) string { return strings.ReplaceAll(s, old, replacement) },
+		"contains":   func(substr, s string) bool { return strings.Contains(s, substr) },
+		"hasPrefix":  func(prefix, s string) bool { return strings.HasPrefix(s, prefix) },
+		"hasSuffix":  func(suffix, s string) bool { return strings.HasSuffix(s, suffix) },
+		"repeat":     func(count int, s string) string { return strings.Repeat(s, count) },
+		"nospace":    func(s string) string { return strings.Join(strings.Fields(s), "") },
+		"trunc":      func(length int, s string) string { return truncate(length, s) },
+		"quote":      func(values ...any) string { return quoteAll(`"`, values) },
+		"squote":     func(values ...any) string { return quoteAll(`'`, values) },
+		"cat":        cat,
+		"indent":     indent,
+		"nindent":    func(spaces int, s string) string { return "\n" + indent(spaces, s) },
+		"splitList":  func(sep, s string) []string { return strings.Split(s, sep) },
+		"join":       join,
+		"toString":   func(v any) string { return fmt.Sprint(v) },
+
+		// defaults and flow control
+		"default":  defaultValue,
+		"empty":    isEmpty,
+		"coalesce": coalesce,
+		"ternary":  ternary,
+
+		// lists and dicts
+		"list":  func(values ...any) []any { return values },
+		"first": first,
+		"last":  last,
+		"dict":  dict,
+
+		// math, using int64 like sprig
+		"add": func(a, b any) int64 { return toInt64(a) + toInt64(b) },
+		"sub": func(a, b any) int64 { return toInt64(a) - toInt64(b) },
+		"mul": func(a, b any) int64 { return toInt64(a) * toInt64(b) },
+		"div": func(a, b any) int64 { return toInt64(a) / toInt64(b) },
+		"mod": func(a, b any) int64 { return toInt64(a) % toInt64(b) },
+		"max": func(a any, values ...any) int64 { return fold(a, values, func(x, y int64) bool { return y > x }) },
+		"min": func(a any, values ...any) int64 { return fold(a, values, func(x, y int64) bool { return y < x }) },
+
+		// dates and encoding
+		"now":    time.Now,
+		"date":   func(layout string, t time.Time) string { return t.Format(layout) },
+		"toJson": toJSON,
+	}
+}
+
+func title(s string) string {
+	prev := ' '
+
+	return strings.Map(func(r rune) rune {
+		if unicode.IsSpace(prev) {
+			prev = r
+			return unicode.ToTitle(r)
+		}
+
+		prev = r
+
+		return r
+	}, s)
+}
+
+func quoteAll(quote string, values []any) string {
+	quoted := make([]string, 0, len(values))
+
+	for _, v := range values {
+		if v != nil {
+			quoted = append(quoted, quote+fmt.Sprint(v)+quote)
+		}
+	}
+
+	return strings.Join(quoted, " ")
+}
+
+func cat(values ...any) string {
+	parts := make([]string, 0, len(values))
+
+	for _, v := range values {
+		if v != nil {
+			parts = append(parts, fmt.Sprint(v))
+		}
+	}
+
+	return strings.Join(parts, " ")
+}
+
+func indent(spaces int, s string) string {
+	pad := strings.Repeat(" ", spaces)
+	return pad + strings.ReplaceAll(s, "\n", "\n"+pad)
+}
+
+func ternary(whenTrue, whenFalse any, condition bool) any {
+	if condition {
+		return whenTrue
+	}
+
+	return whenFalse
+}
+
+func first(list any) any {
+	v := reflect.ValueOf(list)
+	if (v.Kind() != reflect.Slice && v.Kind() != reflect.Array) || v.Len() == 0 {
+		return nil
+	}
+
+	return v.Index(0).Interface()
+}
+
+func last(list any) any {
+	v := reflect.ValueOf(list)
+	if (v.Kind() != reflect.Slice && v.Kind() != reflect.Array) || v.Len() == 0 {
+		return nil
+	}
+
+	return v.Index(v.Len() - 1).Interface()
+}
+
+func fold(a any, values []any, replace func(x, y int64) bool) int64 {
+	result := toInt64(a)
+
+	for _, v := range values {
+		if n := toInt64(v); replace(result, n) {
+			result = n
+		}
+	}
+
+	return result
+}
+
+// toInt64 converts numbers and numeric strings to an int64, returning 0 for anything else.
+func toInt64(v any) int64 {
+	rv := reflect.ValueOf(v)
+
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return rv.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return int64(rv.Uint())
+	case reflect.Float32, reflect.Float64:
+		return int64(rv.Float())
+	case reflect.String:
+		n, _ := strconv.ParseInt(rv.String(), 10, 64)
+		return n
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,53 @@
+package templates_test
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"testing/fstest"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/require"
+	templates "github.com/wolfeidau/echo-go-templates"
+)
+
+func Test_WithSprigFuncs(t *testing.T) {
+	e := echo.New()
+
+	tests := []struct {
+		name     string
+		template string
+		data     any
+		expected string
+	}{
+		{name: "strings", template: `{{"  hello world " | trim | title}} {{"abc" | trimPrefix "a" | upper}} {{"a-b" | replace "-" "_"}}`, expected: "Hello World BC a_b"},
+		{name: "predicates", template: `{{contains "ell" "hello"}} {{hasPrefix "he" "hello"}} {{hasSuffix "x" "hello"}}`, expected: "true true false"},
+		{name: "quote", template: `{{quote "a" 1}} {{cat "a" nil "b"}} {{"ab" | repeat 2}} {{"a b c" | nospace}}`, expected: `&#34;a&#34; &#34;1&#34; a b abab abc`},
+		{name: "indent", template: "{{indent 2 \"a\\nb\"}}", expected: "  a\n  b"},
+		{name: "lists", template: `{{$l := list 1 2 3}}{{first $l}} {{last $l}} {{join "," $l}} {{splitList "," "x,y" | last}}`, expected: "1 3 1,2,3 y"},
+		{name: "math", template: `{{add 1 2}} {{sub 5 "2"}} {{mul 2 3}} {{div 7 2}} {{mod 7 2}} {{max 1 9 3}} {{min 4 2 8}}`, expected: "3 3 6 3 1 9 2"},
+		{name: "defaults", template: `{{.Name | default "anon"}} {{empty .Name}} {{ternary "yes" "no" true}}`, data: map[string]any{"Name": ""}, expected: "anon true yes"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert := require.New(t)
+
+			fsys := fstest.MapFS{"test.html": {Data: []byte(tt.template)}}
+
+			render := templates.New(templates.WithSprigFuncs())
+
+			err := render.Add(fsys, "test.html")
+			assert.NoError(err)
+
+			req := httptest.NewRequest(http.MethodGet, "/", http.NoBody)
+			c := e.NewContext(req, httptest.NewRecorder())
+
+			output := bytes.NewBufferString("")
+			err = render.Render(output, "test.html", tt.data, c)
+			assert.NoError(err)
+			assert.Equal(tt.expected, output.String())
+		})
+	}
+}
@@ -0,0 +1,66 @@
+package templates
+
+import (
+	"crypto/sha512"
+	"encoding/base64"
+	"html/template"
+	"io/fs"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// WithSRI adds the sriHash template function which returns the sha384 Subresource Integrity value of an
+// asset in fsys, a leading "/" is removed from the path:
+//
+//	<script src="/js/app.js" integrity="{{sriHash "/js/app.js"}}" crossorigin="anonymous"></script>
+//
+// Hashes are cached, and recomputed if the size or modification time of the file changes.
+func WithSRI(fsys fs.FS) Option {
+	hashes := &sriHashes{fsys: fsys}
+
+	return WithFuncs(template.FuncMap{
+		"sriHash": hashes.hash,
+	})
+}
+
+type sriHashes struct {
+	fsys  fs.FS
+	cache sync.Map // path to *sriEntry
+}
+
+type sriEntry struct {
+	size    int64
+	modTime time.Time
+	hash    string
+}
+
+func (s *sriHashes) hash(name string) (string, error) {
+	name = strings.TrimPrefix(name, "/")
+
+	info, err := fs.Stat(s.fsys, name)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to stat asset %s", name)
+	}
+
+	if v, ok := s.cache.Load(name); ok {
+		entry := v.(*sriEntry)
+		if entry.size == info.Size() && entry.modTime.Equal(info.ModTime()) {
+			return entry.hash, nil
+		}
+	}
+
+	data, err := fs.ReadFile(s.fsys, name)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to read asset %s", name)
+	}
+
+	sum := sha512.Sum384(data)
+	hash := "sha384-" + base64.StdEncoding.EncodeToString(sum[:])
+
+	s.cache.Store(name, &sriEntry{size: info.Size(), modTime: info.ModTime(), hash: hash})
+
+	return hash, nil
+}
@@ -0,0 +1,49 @@
+package templates_test
+
+import (
+	"crypto/sha512"
+	"encoding/base64"
+	"html"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	templates "github.com/wolfeidau/echo-go-templates"
+)
+
+func Test_WithSRI(t *testing.T) {
+	assert := require.New(t)
+
+	assets := fstest.MapFS{
+		"js/app.js": {Data: []byte("console.log('one')")},
+	}
+
+	fsys := fstest.MapFS{
+		"pages/index.html": {Data: []byte(`<script src="/js/app.js" integrity="{{sriHash "/js/app.js"}}"></script>`)},
+		"pages/bad.html":   {Data: []byte(`{{sriHash "missing.js"}}`)},
+	}
+
+	render := templates.New(templates.WithSRI(assets))
+
+	err := render.Add(fsys, "pages/*.html")
+	assert.NoError(err)
+
+	s, err := render.RenderString("index.html", nil)
+	assert.NoError(err)
+	assert.Equal(`<script src="/js/app.js" integrity="`+sri("console.log('one')")+`"></script>`, html.UnescapeString(s))
+
+	assets["js/app.js"] = &fstest.MapFile{Data: []byte("console.log('two')"), ModTime: time.Now()}
+
+	s, err = render.RenderString("index.html", nil)
+	assert.NoError(err)
+	assert.Equal(`<script src="/js/app.js" integrity="`+sri("console.log('two')")+`"></script>`, html.UnescapeString(s))
+
+	_, err = render.RenderString("bad.html", nil)
+	assert.ErrorContains(err, "failed to stat asset missing.js")
+}
+
+func sri(s string) string {
+	sum := sha512.Sum384([]byte(s))
+	return "sha384-" + base64.StdEncoding.EncodeToString(sum[:])
+}
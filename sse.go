@@ -0,0 +1,107 @@
+package templates
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+// SSEEvent sets the event name and id of a Server-Sent Event sent by RenderSSE, the data is passed to the
+// template. Values of other types are sent as unnamed events. The name and id must not contain line
+// breaks, as they would end the field and allow the rest to be read as other fields.
+type SSEEvent struct {
+	Name string
+	ID   string
+	Data any
+}
+
+// RenderSSE renders the named template for each value received from events, writing the output as a
+// Server-Sent Event and flushing it to the client, until events is closed or the request is cancelled.
+// The name supports the same "#" block suffix as Render, so fragments can be streamed to the HTMX SSE
+// extension:
+//
+//	return render.RenderSSE(c, "messages.html#message", messages)
+func (t *TemplateRenderer) RenderSSE(c echo.Context, name string, events <-chan any) error {
+	res := c.Response()
+	res.Header().Set(echo.HeaderContentType, "text/event-stream")
+	res.Header().Set(echo.HeaderCacheControl, "no-cache")
+	res.Header().Set(echo.HeaderConnection, "keep-alive")
+	res.WriteHeader(http.StatusOK)
+	flush(res)
+
+	done := c.Request().Context().Done()
+	buf := new(bytes.Buffer)
+
+	for {
+		select {
+		case <-done:
+			return nil
+		case v, ok := <-events:
+			if !ok {
+				return nil
+			}
+
+			buf.Reset()
+
+			err := t.writeSSEEvent(buf, c, name, v)
+			if err != nil {
+				return err
+			}
+
+			_, err = buf.WriteTo(res)
+			if err != nil {
+				return err
+			}
+
+			flush(res)
+		}
+	}
+}
+
+// writeSSEEvent renders the template for the value, writing it to buf as an event frame.
+func (t *TemplateRenderer) writeSSEEvent(buf *bytes.Buffer, c echo.Context, name string, v any) error {
+	event, ok := v.(SSEEvent)
+	if !ok {
+		event = SSEEvent{Data: v}
+	}
+
+	if strings.ContainsAny(event.Name, "\r\n") || strings.ContainsAny(event.ID, "\r\n") {
+		return fmt.Errorf("sse: line break in event name %q or id %q", event.Name, event.ID)
+	}
+
+	out := new(strings.Builder)
+
+	err := t.render(out, name, event.Data, c)
+	if err != nil {
+		return err
+	}
+
+	if event.Name != "" {
+		fmt.Fprintf(buf, "event: %s\n", event.Name)
+	}
+
+	if event.ID != "" {
+		fmt.Fprintf(buf, "id: %s\n", event.ID)
+	}
+
+	// each line of the output is sent as a data field, lines may end in CRLF, CR or LF
+	data := strings.NewReplacer("\r\n", "\n", "\r", "\n").Replace(out.String())
+
+	for _, line := range strings.Split(data, "\n") {
+		fmt.Fprintf(buf, "data: %s\n", line)
+	}
+
+	buf.WriteByte('\n')
+
+	return nil
+}
+
+// flush flushes the response if it supports flushing.
+func flush(res *echo.Response) {
+	if f, ok := res.Writer.(http.Flusher); ok {
+		f.Flush()
+	}
+}
@@ -0,0 +1,68 @@
+package templates_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"testing/fstest"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/require"
+	templates "github.com/wolfeidau/echo-go-templates"
+)
+
+func Test_RenderSSE(t *testing.T) {
+	assert := require.New(t)
+
+	fsys := fstest.MapFS{
+		"messages.html": {Data: []byte(`<ul>{{range .}}{{block "message" .}}<li>{{.}}</li>{{end}}{{end}}</ul>` + "\n")},
+	}
+
+	render := templates.New()
+
+	err := render.Add(fsys, "*.html")
+	assert.NoError(err)
+
+	events := make(chan any, 2)
+	events <- "hello"
+	events <- templates.SSEEvent{Name: "list", ID: "2", Data: []string{"a", "b"}}
+	close(events)
+
+	e := echo.New()
+	rec := &flushRecorder{ResponseRecorder: httptest.NewRecorder()}
+	c := e.NewContext(httptest.NewRequest(http.MethodGet, "/", nil), rec)
+
+	err = render.RenderSSE(c, "messages.html#message", events)
+	assert.NoError(err)
+
+	assert.Equal("text/event-stream", rec.Header().Get(echo.HeaderContentType))
+	assert.Equal("data: <li>hello</li>\n\nevent: list\nid: 2\ndata: <li>[a b]</li>\n\n", rec.Body.String())
+	assert.Len(rec.flushedAt, 3)
+}
+
+func Test_RenderSSE_LineBreaks(t *testing.T) {
+	assert := require.New(t)
+
+	fsys := fstest.MapFS{
+		"message.html": {Data: []byte("a\r\nb\rc\nd")},
+	}
+
+	render := templates.New()
+
+	err := render.Add(fsys, "*.html")
+	assert.NoError(err)
+
+	events := make(chan any, 2)
+	events <- templates.SSEEvent{Name: "message", Data: nil}
+	events <- templates.SSEEvent{Name: "message\r\ndata: injected", Data: nil}
+	close(events)
+
+	e := echo.New()
+	rec := &flushRecorder{ResponseRecorder: httptest.NewRecorder()}
+	c := e.NewContext(httptest.NewRequest(http.MethodGet, "/", nil), rec)
+
+	err = render.RenderSSE(c, "message.html", events)
+	assert.ErrorContains(err, "sse: line break in event name")
+
+	assert.Equal("event: message\ndata: a\ndata: b\ndata: c\ndata: d\n\n", rec.Body.String())
+}
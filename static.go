@@ -0,0 +1,76 @@
+package templates
+
+import (
+	"path"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
+)
+
+// MountStatic registers a GET route under the prefix for each registered template with a path matching
+// the pattern, so content pages don't each need a handler. The route is the path of the template relative
+// to the directory of the pattern without the extension, with index pages served at their directory:
+//
+//	err := render.MountStatic(e, "/", "pages/**/*.html")
+//
+// registers pages/about.html at /about, pages/docs/intro.html at /docs/intro and pages/docs/index.html at
+// /docs/. Templates are rendered with their front matter as data. Only templates registered before the
+// call are mounted.
+func (t *TemplateRenderer) MountStatic(e *echo.Echo, prefix, pattern string) error {
+	if _, err := path.Match(pattern, ""); err != nil {
+		return errors.Wrapf(err, "invalid pattern %s", pattern)
+	}
+
+	segments := strings.Split(pattern, "/")
+
+	// routes are relative to the longest leading directory which contains no meta characters
+	var root []string
+	for _, segment := range segments[:len(segments)-1] {
+		if strings.ContainsAny(segment, `*?[\`) {
+			break
+		}
+		root = append(root, segment)
+	}
+
+	for _, name := range t.Names() {
+		tmpl, ok := t.lookup(name)
+		if !ok {
+			continue
+		}
+
+		ok, err := matchSegments(segments, strings.Split(tmpl.path, "/"))
+		if err != nil {
+			return errors.Wrapf(err, "invalid pattern %s", pattern)
+		}
+
+		if !ok {
+			continue
+		}
+
+		route := staticRoute(prefix, strings.Split(tmpl.path, "/")[len(root):])
+
+		t.defaultLog().Debug().Str("name", name).Str("route", route).Msg("mount static page")
+
+		e.GET(route, t.Handler(name, tmpl.meta))
+	}
+
+	return nil
+}
+
+// staticRoute returns the route of the page with the path relative to the root of the pattern.
+func staticRoute(prefix string, rel []string) string {
+	page := path.Join(rel...)
+	page = strings.TrimSuffix(page, path.Ext(page))
+
+	if path.Base(page) == "index" {
+		dir := path.Join("/", prefix, path.Dir(page))
+		if dir == "/" {
+			return dir
+		}
+
+		return dir + "/"
+	}
+
+	return path.Join("/", prefix, page)
+}
@@ -0,0 +1,56 @@
+package templates_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"testing/fstest"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/require"
+	templates "github.com/wolfeidau/echo-go-templates"
+)
+
+func Test_MountStatic(t *testing.T) {
+	assert := require.New(t)
+
+	fsys := fstest.MapFS{
+		"layout.html":           {Data: []byte(`<title>{{meta "title"}}</title>{{block "content" .}}{{end}}`)},
+		"pages/index.html":      {Data: []byte("---\ntitle: Home\n---\n{{define \"content\"}}home{{end}}")},
+		"pages/about.html":      {Data: []byte("---\ntitle: About\n---\n{{define \"content\"}}about {{.title}}{{end}}")},
+		"pages/docs/index.html": {Data: []byte(`{{define "content"}}docs{{end}}`)},
+		"pages/docs/intro.html": {Data: []byte(`{{define "content"}}intro{{end}}`)},
+		"app/dashboard.html":    {Data: []byte(`{{define "content"}}dashboard{{end}}`)},
+	}
+
+	render := templates.New(templates.WithRelativeNames())
+
+	err := render.AddWithLayout(fsys, "layout.html", "pages/**/*.html", "app/*.html")
+	assert.NoError(err)
+
+	e := echo.New()
+
+	err = render.MountStatic(e, "/site", "pages/**/*.html")
+	assert.NoError(err)
+
+	tests := map[string]string{
+		"/site/":           "<title>Home</title>home",
+		"/site/about":      "<title>About</title>about About",
+		"/site/docs/":      "<title></title>docs",
+		"/site/docs/intro": "<title></title>intro",
+	}
+
+	for target, body := range tests {
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, target, nil))
+		assert.Equal(http.StatusOK, rec.Code, target)
+		assert.Equal(body, rec.Body.String(), target)
+	}
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/site/dashboard", nil))
+	assert.Equal(http.StatusNotFound, rec.Code)
+
+	err = render.MountStatic(e, "/", "[")
+	assert.ErrorContains(err, "invalid pattern")
+}
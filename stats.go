@@ -0,0 +1,97 @@
+package templates
+
+import (
+	"expvar"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+// TemplateStats are the render statistics for a template.
+type TemplateStats struct {
+	Name            string        `json:"name"`
+	Renders         int64         `json:"renders"`
+	Errors          int64         `json:"errors"`
+	AverageDuration time.Duration `json:"average_duration_ns"`
+	LastError       string        `json:"last_error,omitempty"`
+	LastRender      time.Time     `json:"last_render"`
+
+	totalDuration time.Duration
+}
+
+// stats records render statistics for each template name.
+type stats struct {
+	mu        sync.Mutex
+	templates map[string]*TemplateStats
+}
+
+func (s *stats) record(info RenderInfo) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ts, ok := s.templates[info.Name]
+	if !ok {
+		ts = &TemplateStats{Name: info.Name}
+		s.templates[info.Name] = ts
+	}
+
+	ts.Renders++
+	ts.totalDuration += info.Duration
+	ts.AverageDuration = ts.totalDuration / time.Duration(ts.Renders)
+	ts.LastRender = time.Now()
+
+	if info.Err != nil {
+		ts.Errors++
+		ts.LastError = info.Err.Error()
+	}
+}
+
+// Stats returns the render statistics for every registered template sorted by name, templates which
+// have never been rendered are included with zero renders to help find dead templates.
+func (t *TemplateRenderer) Stats() []TemplateStats {
+	t.mu.RLock()
+	names := make(map[string]struct{}, len(t.templates))
+	for name := range t.templates {
+		names[name] = struct{}{}
+	}
+	t.mu.RUnlock()
+
+	t.stats.mu.Lock()
+	defer t.stats.mu.Unlock()
+
+	result := make([]TemplateStats, 0, len(names))
+
+	for name := range t.stats.templates {
+		names[name] = struct{}{}
+	}
+
+	for name := range names {
+		if ts, ok := t.stats.templates[name]; ok {
+			result = append(result, *ts)
+			continue
+		}
+
+		result = append(result, TemplateStats{Name: name})
+	}
+
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+
+	return result
+}
+
+// StatsHandler returns a handler which serves the render statistics as JSON, for example on a
+// /debug/templates route.
+func (t *TemplateRenderer) StatsHandler() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		return c.JSON(http.StatusOK, t.Stats())
+	}
+}
+
+// PublishExpvar publishes the render statistics as an expvar with the name, this panics if the
+// name is already in use.
+func (t *TemplateRenderer) PublishExpvar(name string) {
+	expvar.Publish(name, expvar.Func(func() any { return t.Stats() }))
+}
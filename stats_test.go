@@ -0,0 +1,65 @@
+package templates_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"expvar"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"testing/fstest"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/require"
+	templates "github.com/wolfeidau/echo-go-templates"
+)
+
+func Test_Stats(t *testing.T) {
+	assert := require.New(t)
+
+	fsys := fstest.MapFS{
+		"index.html":  {Data: []byte(`index`)},
+		"broken.html": {Data: []byte(`{{.Missing.Field}}`)},
+		"unused.html": {Data: []byte(`unused`)},
+	}
+
+	render := templates.New()
+
+	err := render.Add(fsys, "*.html")
+	assert.NoError(err)
+
+	e := echo.New()
+	c := e.NewContext(httptest.NewRequest(http.MethodGet, "/", http.NoBody), httptest.NewRecorder())
+
+	assert.NoError(render.Render(bytes.NewBufferString(""), "index.html", nil, c))
+	assert.NoError(render.Render(bytes.NewBufferString(""), "index.html", nil, c))
+	assert.Error(render.Render(bytes.NewBufferString(""), "broken.html", map[string]any{"Missing": 1}, c))
+
+	stats := render.Stats()
+	assert.Len(stats, 3)
+
+	assert.Equal("broken.html", stats[0].Name)
+	assert.Equal(int64(1), stats[0].Renders)
+	assert.Equal(int64(1), stats[0].Errors)
+	assert.Contains(stats[0].LastError, "can't evaluate field Field")
+
+	assert.Equal("index.html", stats[1].Name)
+	assert.Equal(int64(2), stats[1].Renders)
+	assert.Equal(int64(0), stats[1].Errors)
+
+	assert.Equal(templates.TemplateStats{Name: "unused.html"}, stats[2])
+
+	e.GET("/debug/templates", render.StatsHandler())
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/debug/templates", http.NoBody))
+	assert.Equal(http.StatusOK, rec.Code)
+
+	var served []map[string]any
+	assert.NoError(json.Unmarshal(rec.Body.Bytes(), &served))
+	assert.Len(served, 3)
+	assert.Equal(float64(2), served[1]["renders"])
+
+	render.PublishExpvar("test_templates")
+	assert.Contains(expvar.Get("test_templates").String(), `"name":"index.html"`)
+}
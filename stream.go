@@ -0,0 +1,60 @@
+package templates
+
+import (
+	"bytes"
+
+	"github.com/labstack/echo/v4"
+)
+
+var headEnd = []byte("</head>")
+
+// Stream renders the named template directly to the response, rather than buffering the output as
+// c.Render does, flushing the output as soon as the end of the <head> element is written so the browser
+// can start fetching stylesheets and scripts while the rest of the page is rendered:
+//
+//	return render.Stream(c, http.StatusOK, "index.html", data)
+//
+// As the status and headers are sent before the template is executed, an error part way through leaves a
+// truncated page, and the debug error page, transformers and ETags aren't applied.
+func (t *TemplateRenderer) Stream(c echo.Context, code int, name string, data any) error {
+	res := c.Response()
+
+	if res.Header().Get(echo.HeaderContentType) == "" {
+		res.Header().Set(echo.HeaderContentType, echo.MIMETextHTMLCharsetUTF8)
+	}
+
+	res.WriteHeader(code)
+
+	return t.render(&headFlusher{res: res}, name, data, c)
+}
+
+// headFlusher writes to the response, flushing it once the end of the head element has been written.
+type headFlusher struct {
+	res     *echo.Response
+	window  []byte // the end of the output, in case the closing tag is split across writes
+	flushed bool
+}
+
+func (hf *headFlusher) Write(p []byte) (int, error) {
+	n, err := hf.res.Write(p)
+	if err != nil || hf.flushed {
+		return n, err
+	}
+
+	hf.window = append(hf.window, p...)
+
+	if bytes.Contains(bytes.ToLower(hf.window), headEnd) {
+		hf.flushed = true
+		hf.window = nil
+
+		flush(hf.res)
+
+		return n, nil
+	}
+
+	if keep := len(headEnd) - 1; len(hf.window) > keep {
+		hf.window = append(hf.window[:0], hf.window[len(hf.window)-keep:]...)
+	}
+
+	return n, nil
+}
@@ -0,0 +1,51 @@
+package templates_test
+
+import (
+	"html/template"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"testing/fstest"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/require"
+	templates "github.com/wolfeidau/echo-go-templates"
+)
+
+type flushRecorder struct {
+	*httptest.ResponseRecorder
+	flushedAt []string
+}
+
+func (fr *flushRecorder) Flush() {
+	fr.flushedAt = append(fr.flushedAt, fr.Body.String())
+	fr.ResponseRecorder.Flush()
+}
+
+func Test_Stream(t *testing.T) {
+	assert := require.New(t)
+
+	fsys := fstest.MapFS{
+		"layout.html": {Data: []byte(`<html><head><title>{{.}}</title></HEAD><body>{{block "content" .}}{{end}}</body></html>`)},
+		"index.html":  {Data: []byte(`{{define "content"}}<p>{{.}}</p>{{end}}`)},
+	}
+
+	render := templates.New(templates.WithTransformer(templates.TransformerFunc(func(name string, out []byte) ([]byte, error) {
+		return append(out, "transformed"...), nil
+	})))
+
+	err := render.AddWithLayout(fsys, "layout.html", "index.html")
+	assert.NoError(err)
+
+	e := echo.New()
+	rec := &flushRecorder{ResponseRecorder: httptest.NewRecorder()}
+	c := e.NewContext(httptest.NewRequest(http.MethodGet, "/", nil), rec)
+
+	err = render.Stream(c, http.StatusCreated, "index.html", template.HTML("hello"))
+	assert.NoError(err)
+
+	assert.Equal(http.StatusCreated, rec.Code)
+	assert.Equal(echo.MIMETextHTMLCharsetUTF8, rec.Header().Get(echo.HeaderContentType))
+	assert.Equal("<html><head><title>hello</title></HEAD><body><p>hello</p></body></html>", rec.Body.String())
+	assert.Equal([]string{"<html><head><title>hello</title></HEAD><body>"}, rec.flushedAt)
+}
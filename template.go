@@ -0,0 +1,334 @@
+package templates
+
+import (
+	"fmt"
+	"html/template"
+	"io/fs"
+	"path"
+	"strings"
+	"sync"
+
+	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
+)
+
+// Template stores the meta data for each template, and whether it uses a layout.
+type Template struct {
+	layout      string
+	name        string
+	filename    string
+	path        string
+	fsys        fs.FS
+	layoutFiles []string
+	includes    []string
+	shared      []fileSet
+	funcs       template.FuncMap
+	ctxFuncs    []func(c echo.Context) template.FuncMap
+	delims      delims
+	options     []string
+	source      []byte
+	meta        map[string]any
+	text        bool // parsed using text/template rather than html/template
+	contentType string
+	pageCache   *pageCache
+	ctxScoped   bool     // calls request scoped functions, so the output isn't page cached
+	preload     bool     // preload the stylesheets and scripts in the layouts
+	earlyHints  bool     // send the preloads in a 103 Early Hints response
+	preloads    []string // Link header values preloading the stylesheets and scripts
+	markdown    MarkdownConverter
+	template    engine
+	variants    sync.Map  // layout overrides, parsed on first use
+	bases       *baseSets // layouts and includes shared with the templates registered together
+	lazy        bool      // parsing is deferred until first use
+	parseOnce   sync.Once
+	parseErr    error
+}
+
+// fileSet is a set of template files matching patterns in a file system.
+type fileSet struct {
+	fsys     fs.FS
+	patterns []string
+}
+
+// delims are the action delimiters used to parse templates, empty values use the defaults.
+type delims struct {
+	left  string
+	right string
+}
+
+func (d delims) leftOrDefault() string {
+	if d.left == "" {
+		return "{{"
+	}
+
+	return d.left
+}
+
+func (d delims) rightOrDefault() string {
+	if d.right == "" {
+		return "}}"
+	}
+
+	return d.right
+}
+
+// execName returns the name of the template to execute, this is the layout if the template has one.
+func (tmpl *Template) execName() string {
+	if tmpl.layout != "" {
+		return tmpl.layout
+	}
+
+	return tmpl.filename
+}
+
+// parse parses the shared includes, layouts, includes and template file, in that order, so
+// definitions in the template file override the defaults in the layouts. The shared includes, layouts
+// and includes are parsed once for the templates registered together and cloned for each template.
+func (tmpl *Template) parse() error {
+	base, err := tmpl.bases.get(tmpl)
+	if err != nil {
+		return errors.Wrapf(err, "failed to parse template %s", tmpl.path)
+	}
+
+	var tmp engine
+
+	if base != nil {
+		clone, err := base.Clone()
+		if err != nil {
+			return errors.Wrapf(err, "failed to clone layouts and includes for template %s", tmpl.path)
+		}
+
+		tmp = clone.New(tmpl.filename)
+	} else {
+		tmp = tmpl.newEngine(tmpl.filename)
+	}
+
+	tmp.Funcs(template.FuncMap{"meta": tmpl.metaValue})
+
+	_, body, err := splitFrontMatter(tmpl.source, tmpl.delims)
+	if err != nil {
+		return errors.Wrapf(err, "failed to parse front matter in template %s", tmpl.path)
+	}
+
+	if tmpl.markdown != nil {
+		body, err = tmpl.markdownBody()
+		if err != nil {
+			return errors.Wrapf(err, "failed to convert markdown in template %s", tmpl.path)
+		}
+	}
+
+	err = tmp.Parse(string(body))
+	if err != nil {
+		return errors.Wrapf(err, "failed to parse template %s", tmpl.path)
+	}
+
+	// empty blocks in the template only need clearing if they may have been defined by another file
+	if base != nil {
+		for _, name := range emptyDefines(tmpl.filename, body, tmpl.delims) {
+			err = tmp.Parse(clearDefine(name, tmpl.delims))
+			if err != nil {
+				return errors.Wrapf(err, "failed to clear block %s in template %s", name, tmpl.path)
+			}
+		}
+	}
+
+	if tmpl.preload {
+		tmpl.preloads = preloadLinks(tmp, tmpl.layoutFiles)
+	}
+
+	if tmpl.pageCache != nil {
+		tmpl.ctxScoped = callsContextFuncs(tmp, tmpl.ctxFuncs)
+	}
+
+	tmpl.template = tmp
+
+	return nil
+}
+
+// newEngine returns an empty template with the template functions, the request scoped functions need to
+// be defined before parsing and are replaced when rendering.
+func (tmpl *Template) newEngine(name string) engine {
+	tmp := newEngine(tmpl.text, name, tmpl.delims, tmpl.options)
+	tmp.Funcs(tmpl.funcs)
+	tmp.Funcs(template.FuncMap{"meta": tmpl.metaValue})
+
+	for _, fn := range tmpl.ctxFuncs {
+		tmp.Funcs(fn(nil))
+	}
+
+	return tmp
+}
+
+// parseBase parses the shared includes, layouts and includes of the template, returning nil if there
+// aren't any. The result is named after the first file so it doesn't contain an empty template.
+func (tmpl *Template) parseBase() (engine, error) {
+	var (
+		tmp  engine
+		sets []fileSet
+	)
+
+	sets = append(sets, tmpl.shared...)
+	sets = append(sets, fileSet{fsys: tmpl.fsys, patterns: tmpl.layoutFiles}, fileSet{fsys: tmpl.fsys, patterns: tmpl.includes})
+
+	for _, set := range sets {
+		if len(set.patterns) == 0 {
+			continue
+		}
+
+		files, err := readFileNames(set.fsys, set.patterns...)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to list layouts and includes")
+		}
+
+		if tmp == nil {
+			tmp = tmpl.newEngine(path.Base(files[0]))
+		}
+
+		err = tmp.ParseFS(set.fsys, files...)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to parse layouts and includes")
+		}
+	}
+
+	return tmp, nil
+}
+
+// baseSets holds the parsed shared includes, layouts and includes of the templates registered together,
+// keyed by the layouts and includes, so they are parsed once rather than for every template.
+type baseSets struct {
+	mu   sync.Mutex
+	sets map[string]*baseSet
+}
+
+type baseSet struct {
+	once   sync.Once
+	engine engine
+	err    error
+}
+
+func newBaseSets() *baseSets {
+	return &baseSets{sets: make(map[string]*baseSet)}
+}
+
+// get returns the parsed shared includes, layouts and includes of the template, parsing them on first
+// use. When b is nil they are parsed for the template alone.
+func (b *baseSets) get(tmpl *Template) (engine, error) {
+	if b == nil {
+		return tmpl.parseBase()
+	}
+
+	// templates replaced together may have been registered with different shared includes
+	key := fmt.Sprintf("%p\x00%s\x01%s", tmpl.shared, strings.Join(tmpl.layoutFiles, "\x00"), strings.Join(tmpl.includes, "\x00"))
+
+	b.mu.Lock()
+
+	set, ok := b.sets[key]
+	if !ok {
+		set = &baseSet{}
+		b.sets[key] = set
+	}
+
+	b.mu.Unlock()
+
+	set.once.Do(func() {
+		set.engine, set.err = tmpl.parseBase()
+	})
+
+	return set.engine, set.err
+}
+
+// ensureParsed parses the template on first use if parsing was deferred using WithLazyParsing, the
+// result is cached so a template which fails to parse returns the same error until it is reloaded.
+func (tmpl *Template) ensureParsed() error {
+	if !tmpl.lazy {
+		return nil
+	}
+
+	tmpl.parseOnce.Do(func() {
+		tmpl.parseErr = tmpl.parse()
+	})
+
+	return tmpl.parseErr
+}
+
+// layoutName returns the name of the template executed for the layouts, this is the first layout.
+func layoutName(layouts []string) string {
+	if len(layouts) == 0 {
+		return ""
+	}
+
+	return path.Base(layouts[0])
+}
+
+// executable returns the parsed template, if there are request scoped functions it is cloned with
+// the functions bound to c. The parsed template is never executed in this case as html/template
+// doesn't allow cloning a template once it has been executed.
+func (tmpl *Template) executable(c echo.Context) (engine, error) {
+	err := tmpl.ensureParsed()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(tmpl.ctxFuncs) == 0 {
+		return tmpl.template, nil
+	}
+
+	clone, err := tmpl.template.Clone()
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to clone template %s", tmpl.path)
+	}
+
+	for _, fn := range tmpl.ctxFuncs {
+		clone.Funcs(fn(c))
+	}
+
+	return clone, nil
+}
+
+// metaValue returns the value of a key in the front matter of the template, it is available in
+// templates as the meta function.
+func (tmpl *Template) metaValue(key string) any {
+	return tmpl.meta[key]
+}
+
+// withLayouts returns a copy of the template parsed with the layouts in place of its own, copies are
+// cached so each combination is only parsed once.
+func (tmpl *Template) withLayouts(layouts []string) (*Template, error) {
+	key := strings.Join(layouts, "\x00")
+
+	if v, ok := tmpl.variants.Load(key); ok {
+		return v.(*Template), nil
+	}
+
+	variant := &Template{
+		layout:      layoutName(layouts),
+		name:        tmpl.name,
+		filename:    tmpl.filename,
+		path:        tmpl.path,
+		fsys:        tmpl.fsys,
+		text:        tmpl.text,
+		contentType: tmpl.contentType,
+		pageCache:   tmpl.pageCache,
+		preload:     tmpl.preload,
+		earlyHints:  tmpl.earlyHints,
+		markdown:    tmpl.markdown,
+		layoutFiles: layouts,
+		includes:    tmpl.includes,
+		shared:      tmpl.shared,
+		funcs:       tmpl.funcs,
+		ctxFuncs:    tmpl.ctxFuncs,
+		delims:      tmpl.delims,
+		options:     tmpl.options,
+		source:      tmpl.source,
+		meta:        tmpl.meta,
+	}
+
+	err := variant.parse()
+	if err != nil {
+		return nil, err
+	}
+
+	v, _ := tmpl.variants.LoadOrStore(key, variant)
+
+	return v.(*Template), nil
+}
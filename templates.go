@@ -1,16 +1,22 @@
 package templates
 
 import (
+	"bytes"
+	"context"
 	"fmt"
 	"html/template"
 	"io"
 	"io/fs"
 	"net/http"
 	"path"
+	"runtime"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/labstack/echo/v4"
 	"github.com/pkg/errors"
+	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
 )
 
@@ -20,153 +26,802 @@ var defaultTemplateFuncs = template.FuncMap{
 	},
 }
 
-// Template stores the meta data for each template, and whether it uses a layout.
-type Template struct {
-	layout   string
-	name     string
-	template *template.Template
-}
-
 // TemplateRenderer is a custom html/template renderer for Echo framework.
+//
+// Templates may be registered while the renderer is serving requests, the Add methods are safe to
+// call concurrently with Render.
 type TemplateRenderer struct {
-	templates     map[string]*Template
-	templateFuncs template.FuncMap
+	*registry
+	templateFuncs    template.FuncMap
+	logger           *zerolog.Logger
+	nameFunc         NameFunc
+	strict           bool
+	reload           bool
+	debug            bool
+	text             bool
+	contentType      string
+	sharedIncludes   []fileSet
+	htmxBlock        string
+	delims           delims
+	templateOptions  []string
+	ctxFuncs         []func(c echo.Context) template.FuncMap
+	decorators       []DataDecorator
+	globals          map[string]any
+	viewModel        bool
+	hooks            []Hook
+	transformers     []Transformer
+	localeFunc       func(c echo.Context) string
+	markdown         MarkdownConverter
+	overlays         []fs.FS
+	startSpan        StartSpanFunc
+	parseWorkers     int
+	lazy             bool
+	pageCache        *pageCache
+	etag             bool
+	events           []func(RenderEvent)
+	renderTimeout    time.Duration
+	maxOutput        int64
+	requestLogFields bool
+	slowRender       time.Duration
+	preload          bool
+	earlyHints       bool
 }
 
-// New setup a new template renderer.
-func New() *TemplateRenderer {
-	return &TemplateRenderer{
-		templates:     make(map[string]*Template),
-		templateFuncs: defaultTemplateFuncs,
+// New setup a new template renderer configured with the provided options.
+func New(opts ...Option) *TemplateRenderer {
+	t := &TemplateRenderer{
+		registry:      newRegistry(),
+		templateFuncs: make(template.FuncMap, len(defaultTemplateFuncs)),
+		nameFunc:      BaseName,
+	}
+
+	for k, v := range defaultTemplateFuncs {
+		t.templateFuncs[k] = v
 	}
+
+	for _, opt := range opts {
+		opt(t)
+	}
+
+	return t
 }
 
-// NewWithTemplateFuncs setup a new template renderer with custom template functions.
-func NewWithTemplateFuncs(templateFuncs template.FuncMap) *TemplateRenderer {
-	return &TemplateRenderer{
-		templates:     make(map[string]*Template),
-		templateFuncs: templateFuncs,
+// Group returns a renderer which shares the template registry of t, with the options applied
+// to the templates registered through it, for example to prefix the names of a set of templates
+// or to provide additional template functions:
+//
+//	err := render.Group(templates.WithFuncs(emailFuncs)).AddWithLayout(views.Content, "email.html", "emails/*.html")
+//
+// Options applied to a group don't change the options of t.
+func (t *TemplateRenderer) Group(opts ...Option) *TemplateRenderer {
+	g := *t
+
+	g.ctxFuncs = append([]func(c echo.Context) template.FuncMap{}, t.ctxFuncs...)
+	g.templateOptions = append([]string{}, t.templateOptions...)
+	g.decorators = append([]DataDecorator{}, t.decorators...)
+	g.hooks = append([]Hook{}, t.hooks...)
+	g.events = append([]func(RenderEvent){}, t.events...)
+	g.transformers = append([]Transformer{}, t.transformers...)
+	g.overlays = append([]fs.FS{}, t.overlays...)
+
+	g.templateFuncs = make(template.FuncMap, len(t.templateFuncs))
+	for k, v := range t.templateFuncs {
+		g.templateFuncs[k] = v
+	}
+
+	for _, opt := range opts {
+		opt(&g)
+	}
+
+	return &g
+}
+
+// addFuncs adds the template functions to a copy of the template functions of t, as the templates
+// already registered hold the current map and may still be parsing using it.
+func (t *TemplateRenderer) addFuncs(funcs template.FuncMap) {
+	merged := make(template.FuncMap, len(t.templateFuncs)+len(funcs))
+	for k, v := range t.templateFuncs {
+		merged[k] = v
+	}
+
+	for k, v := range funcs {
+		merged[k] = v
 	}
+
+	t.templateFuncs = merged
+}
+
+// NewWithTemplateFuncs setup a new template renderer with custom template functions, which are merged
+// with the default template functions rather than replacing them.
+//
+// Deprecated: use New(WithFuncs(templateFuncs)) instead.
+func NewWithTemplateFuncs(templateFuncs template.FuncMap) *TemplateRenderer {
+	return New(WithFuncs(templateFuncs))
 }
 
 // AddWithLayout register one or more templates using the provided layout.
 func (t *TemplateRenderer) AddWithLayout(fsys fs.FS, layout string, patterns ...string) error {
-	filenames, err := readFileNames(fsys, patterns...)
+	return t.add(fsys, layoutFiles(layout), nil, patterns)
+}
+
+// AddWithLayouts register one or more templates using a chain of nested layouts, the first layout is
+// the outermost and is executed when rendering, for example a base layout followed by a section layout
+// which defines blocks used in the base layout.
+func (t *TemplateRenderer) AddWithLayouts(fsys fs.FS, layouts []string, patterns ...string) error {
+	return t.add(fsys, layouts, nil, patterns)
+}
+
+// AddWithLayoutAndIncludes register one or more templates using the provided layout and includes.
+func (t *TemplateRenderer) AddWithLayoutAndIncludes(fsys fs.FS, layout, includes string, patterns ...string) error {
+	return t.add(fsys, layoutFiles(layout), []string{includes}, patterns)
+}
+
+// AddWithLayoutAndIncludePatterns register one or more templates using the provided layout and
+// all the includes matching any of the include patterns.
+func (t *TemplateRenderer) AddWithLayoutAndIncludePatterns(fsys fs.FS, layout string, includes []string, patterns ...string) error {
+	return t.add(fsys, layoutFiles(layout), includes, patterns)
+}
+
+// SetIncludes sets includes which are parsed into every template subsequently added to the renderer,
+// so common partials such as the header and footer don't need to be repeated in each Add call. It
+// isn't safe to call concurrently with the Add methods.
+func (t *TemplateRenderer) SetIncludes(fsys fs.FS, patterns ...string) error {
+	fsys = t.overlay(fsys)
+
+	_, err := readFileNames(fsys, patterns...)
 	if err != nil {
-		return errors.Wrap(err, "failed to list using file pattern")
+		return errors.Wrap(err, "failed to list includes")
 	}
 
-	for _, f := range filenames {
+	t.sharedIncludes = []fileSet{{fsys: fsys, patterns: patterns}}
+
+	return nil
+}
+
+// Add add a template to the registry.
+func (t *TemplateRenderer) Add(fsys fs.FS, patterns ...string) error {
+	return t.add(fsys, nil, nil, patterns)
+}
+
+// Replace re-parses the templates matching the patterns, swapping them for the registered templates
+// with the same names while keeping their layout and includes. This is permitted in strict mode,
+// and either all the matched templates are replaced or none are.
+func (t *TemplateRenderer) Replace(fsys fs.FS, patterns ...string) error {
+	fsys = t.overlay(fsys)
+
+	// the layouts and includes of the replaced templates are kept for Reload, as the ops which
+	// registered them are dropped
+	var kept map[string]*Template
 
-		tname := path.Base(f)
-		lname := path.Base(layout)
+	return t.update(t, func(r *TemplateRenderer, templates map[string]*Template) error {
+		filenames, err := readFileNames(fsys, patterns...)
+		if err != nil {
+			return errors.Wrap(err, "failed to list using file pattern")
+		}
+
+		existing := make(map[string]*Template)
+
+		parsed, err := r.parseAll(fsys, filenames, func(f string) ([]string, []string, []fileSet) {
+			tmpl, ok := kept[f]
+			if !ok {
+				tmpl, ok = templates[r.nameFunc(f)]
+			}
 
-		log.Debug().Str("filename", tname).Str("layout", layout).Msg("register template")
+			if !ok {
+				return nil, nil, r.sharedIncludes
+			}
 
-		tmp, err := template.New(tname).Funcs(t.templateFuncs).ParseFS(fsys, layout, f)
+			existing[f] = &Template{layoutFiles: tmpl.layoutFiles, includes: tmpl.includes, shared: tmpl.shared}
+
+			return tmpl.layoutFiles, tmpl.includes, tmpl.shared
+		})
 		if err != nil {
-			return errors.Wrapf(err, "failed to parse template %s", f)
+			return err
 		}
 
-		t.templates[tname] = &Template{
-			layout:   lname,
-			name:     tname,
-			template: tmp,
+		if kept == nil {
+			kept = existing
 		}
-	}
 
-	return nil
+		for _, tmpl := range parsed {
+			templates[tmpl.name] = tmpl
+		}
+
+		return nil
+	})
 }
 
-// AddWithLayoutAndIncludes register one or more templates using the provided layout and includes.
-func (t *TemplateRenderer) AddWithLayoutAndIncludes(fsys fs.FS, layout, includes string, patterns ...string) error {
-	filenames, err := readFileNames(fsys, patterns...)
-	if err != nil {
-		return errors.Wrap(err, "failed to list using file pattern")
+// Remove removes the template registered with the name, returning false if it wasn't found.
+func (t *TemplateRenderer) Remove(name string) bool {
+	var found bool
+
+	_ = t.update(t, func(_ *TemplateRenderer, templates map[string]*Template) error {
+		_, found = templates[name]
+		delete(templates, name)
+
+		return nil
+	})
+
+	return found
+}
+
+// Meta returns the front matter of the named template, see splitFrontMatter for the format.
+func (t *TemplateRenderer) Meta(name string) (map[string]any, bool) {
+	tmpl, ok := t.lookup(name)
+	if !ok {
+		return nil, false
 	}
 
-	for _, f := range filenames {
+	return tmpl.meta, true
+}
+
+// Reload re-parses all the templates by replaying the Add, Replace and Remove calls made on the
+// renderer and its groups, for example from an admin endpoint or SIGHUP handler. Calls whose templates
+// were all replaced or removed by later calls aren't replayed. If any template fails to parse the
+// currently registered templates are left in place.
+func (t *TemplateRenderer) Reload() error {
+	return t.replay()
+}
+
+func (t *TemplateRenderer) add(fsys fs.FS, layouts, includes, patterns []string) error {
+	return t.addFrom(func(*TemplateRenderer) fs.FS { return fsys }, layouts, includes, matching(patterns))
+}
+
+// addFrom registers the templates listed by files in the file system returned by source, which is called
+// with the renderer applying the op so templates added from memory are read from its registry.
+func (t *TemplateRenderer) addFrom(source func(r *TemplateRenderer) fs.FS, layouts, includes []string, files func(fsys fs.FS) ([]string, error)) error {
+	shared := t.sharedIncludes
 
-		tname := path.Base(f)
-		lname := path.Base(layout)
+	return t.update(t, func(r *TemplateRenderer, templates map[string]*Template) error {
+		fsys := r.overlay(source(r))
 
-		log.Debug().Str("filename", tname).Str("layout", layout).Msg("register template")
+		filenames, err := files(fsys)
+		if err != nil {
+			return errors.Wrap(err, "failed to list using file pattern")
+		}
 
-		tmp, err := template.New(tname).Funcs(t.templateFuncs).ParseFS(fsys, layout, includes, f)
+		parsed, err := r.parseAll(fsys, filenames, func(string) ([]string, []string, []fileSet) {
+			return layouts, includes, shared
+		})
 		if err != nil {
-			return errors.Wrapf(err, "failed to parse template %s", f)
+			return err
 		}
 
-		t.templates[tname] = &Template{
-			layout:   lname,
-			name:     tname,
-			template: tmp,
+		for _, tmpl := range parsed {
+			err = r.register(templates, tmpl)
+			if err != nil {
+				return err
+			}
 		}
+
+		return nil
+	})
+}
+
+// parseAll parses the template files concurrently, using the layouts and includes returned by files
+// for each, returning the templates in the same order as the files or the first error in that order.
+func (t *TemplateRenderer) parseAll(fsys fs.FS, filenames []string, files func(f string) ([]string, []string, []fileSet)) ([]*Template, error) {
+	var (
+		parsed = make([]*Template, len(filenames))
+		errs   = make([]error, len(filenames))
+		wg     sync.WaitGroup
+		sem    = make(chan struct{}, t.parseConcurrency())
+		bases  = newBaseSets()
+	)
+
+	for i, f := range filenames {
+		layouts, includes, shared := files(f)
+
+		wg.Add(1)
+		sem <- struct{}{}
+
+		go func(i int, f string) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+
+			parsed[i], errs[i] = t.parse(fsys, f, layouts, includes, shared, bases)
+		}(i, f)
 	}
 
-	return nil
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return parsed, nil
 }
 
-// Add add a template to the registry.
-func (t *TemplateRenderer) Add(fsys fs.FS, patterns ...string) error {
-	filenames, err := readFileNames(fsys, patterns...)
+func (t *TemplateRenderer) parseConcurrency() int {
+	if t.parseWorkers > 0 {
+		return t.parseWorkers
+	}
+
+	return runtime.GOMAXPROCS(0)
+}
+
+// parse parses the template file f along with the layouts, includes and shared includes, all of which
+// are optional. The layouts and includes are parsed once for each set of bases.
+func (t *TemplateRenderer) parse(fsys fs.FS, f string, layouts, includes []string, shared []fileSet, bases *baseSets) (*Template, error) {
+	source, err := fs.ReadFile(fsys, f)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read template %s", f)
+	}
+
+	meta, _, err := splitFrontMatter(source, t.delims)
 	if err != nil {
-		return errors.Wrap(err, "failed to read file names using file pattern")
+		return nil, errors.Wrapf(err, "failed to parse front matter in template %s", f)
 	}
 
-	for _, f := range filenames {
-		tname := path.Base(f)
+	if layout, ok := meta["layout"].(string); ok {
+		layouts = splitLayouts(layout)
+	}
 
-		log.Debug().Str("filename", tname).Msg("register message")
+	if directive := layoutDirective(source, t.delims); directive != nil {
+		layouts = directive
+	}
 
-		tmp, err := template.New(tname).Funcs(t.templateFuncs).ParseFS(fsys, f)
-		if err != nil {
-			return errors.Wrapf(err, "failed to parse template %s", f)
-		}
+	tmpl := &Template{
+		layout:      layoutName(layouts),
+		name:        t.nameFunc(f),
+		filename:    path.Base(f),
+		path:        f,
+		fsys:        fsys,
+		layoutFiles: layouts,
+		includes:    includes,
+		shared:      shared,
+		funcs:       t.templateFuncs,
+		ctxFuncs:    t.ctxFuncs,
+		delims:      t.delims,
+		options:     t.templateOptions,
+		text:        t.text,
+		contentType: t.contentType,
+		pageCache:   t.pageCache,
+		preload:     t.preload,
+		earlyHints:  t.earlyHints,
+		source:      source,
+		meta:        meta,
+		bases:       bases,
+		lazy:        t.lazy,
+	}
+
+	if t.markdown != nil && isMarkdown(f) {
+		tmpl.markdown = t.markdown
+	}
+
+	t.defaultLog().Debug().Str("filename", tmpl.name).Strs("layouts", layouts).Msg("register template")
+
+	if tmpl.lazy {
+		return tmpl, nil
+	}
+
+	err = tmpl.parse()
+	if err != nil {
+		return nil, err
+	}
+
+	return tmpl, nil
+}
 
-		t.templates[tname] = &Template{
-			name:     tname,
-			template: tmp,
+// register stores the template, replacing any existing template with the same name unless strict
+// mode is enabled.
+func (t *TemplateRenderer) register(templates map[string]*Template, tmpl *Template) error {
+	if _, ok := templates[tmpl.name]; ok {
+		if t.strict {
+			return fmt.Errorf("template: duplicate template name: %#q", tmpl.name)
 		}
+
+		t.defaultLog().Warn().Str("name", tmpl.name).Msg("replacing existing template")
 	}
 
+	templates[tmpl.name] = tmpl
+
 	return nil
 }
 
 // Render renders a template document.
+//
+// A fragment of the template can be rendered by appending the name of a block defined in the template,
+// or its layout and includes, to the name after a "#", for example "index.html#content" renders the
+// content block of index.html without the layout. This is useful for partial page updates using
+// libraries such as HTMX.
 func (t *TemplateRenderer) Render(w io.Writer, name string, data interface{}, c echo.Context) error {
-	log.Ctx(c.Request().Context()).Debug().Str("name", name).Msg("Render")
+	return sendNotFound(c, t.withETag(w, c, func(w io.Writer) error {
+		return t.render(w, name, data, c)
+	}))
+}
+
+// render renders a template document without setting the ETag, for responses composed from several
+// renders which set it on the complete body.
+func (t *TemplateRenderer) render(w io.Writer, name string, data interface{}, c echo.Context) error {
+	t.ctxLog(c).Debug().Str("name", name).Msg("Render")
+
+	name, block, _ := strings.Cut(name, "#")
+
+	tmpl, err := t.find(name, c)
+	if err != nil {
+		return err
+	}
+
+	if t.htmxBlock != "" && block == "" && tmpl.layout != "" && c != nil {
+		addVary(c.Response().Header(), headerHXRequest)
+
+		if isHTMXPartial(c.Request()) {
+			block = t.htmxBlock
+		}
+	}
 
-	tmpl, ok := t.templates[name]
+	return t.execute(w, tmpl, block, data, c)
+}
+
+// RenderBlock renders a block defined by a {{define}} or {{block}} action in the named template, or its
+// layout and includes, such as a table row rendered for an out of band HTMX swap.
+func (t *TemplateRenderer) RenderBlock(w io.Writer, name, block string, data interface{}, c echo.Context) error {
+	return sendNotFound(c, t.withETag(w, c, func(w io.Writer) error {
+		return t.renderBlock(w, name, block, data, c)
+	}))
+}
+
+func (t *TemplateRenderer) renderBlock(w io.Writer, name, block string, data interface{}, c echo.Context) error {
+	t.ctxLog(c).Debug().Str("name", name).Str("block", block).Msg("RenderBlock")
+
+	tmpl, err := t.find(name, c)
+	if err != nil {
+		return err
+	}
+
+	return t.execute(w, tmpl, block, data, c)
+}
+
+// RenderWithLayout renders a template document using the provided layout in place of the layout it was
+// registered with, an empty layout renders the template without a layout. The layout is parsed from the
+// same file system as the template on first use and cached.
+func (t *TemplateRenderer) RenderWithLayout(w io.Writer, name, layout string, data interface{}, c echo.Context) error {
+	return sendNotFound(c, t.withETag(w, c, func(w io.Writer) error {
+		return t.renderWithLayout(w, name, layout, data, c)
+	}))
+}
+
+func (t *TemplateRenderer) renderWithLayout(w io.Writer, name, layout string, data interface{}, c echo.Context) error {
+	t.ctxLog(c).Debug().Str("name", name).Str("layout", layout).Msg("RenderWithLayout")
+
+	tmpl, err := t.find(name, c)
+	if err != nil {
+		return err
+	}
+
+	tmpl, err = tmpl.withLayouts(layoutFiles(layout))
+	if err != nil {
+		t.ctxLog(c).Error().Err(err).Str("name", name).Str("layout", layout).Msg("parse layout failed")
+		return err
+	}
+
+	return t.execute(w, tmpl, "", data, c)
+}
+
+// RenderString renders a template document to a string without a request, for example in a background
+// job sending emails. The name supports the same "#" block suffix as Render. Hooks, decorators and
+// request scoped functions are called with a nil echo.Context.
+func (t *TemplateRenderer) RenderString(name string, data any) (string, error) {
+	buf := new(strings.Builder)
+
+	err := t.Render(buf, name, data, nil)
+	if err != nil {
+		return "", err
+	}
+
+	return buf.String(), nil
+}
+
+// RenderBytes renders a template document to a byte slice without a request, see RenderString.
+func (t *TemplateRenderer) RenderBytes(name string, data any) ([]byte, error) {
+	buf := new(bytes.Buffer)
+
+	err := t.Render(buf, name, data, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// find returns the named template, reloading the templates first if enabled. A templateNotFoundError
+// is returned if the template isn't found, leaving the caller to send the response.
+func (t *TemplateRenderer) find(name string, c echo.Context) (*Template, error) {
+	if t.reload {
+		err := t.Reload()
+		if err != nil {
+			t.ctxLog(c).Error().Err(err).Str("name", name).Msg("reload templates failed")
+			return nil, err
+		}
+	}
+
+	tmpl, ok := t.lookupLocalized(name, c)
 	if !ok {
-		log.Ctx(c.Request().Context()).Error().Str("name", name).Msg("template not found")
+		t.ctxLog(c).Error().Str("name", name).Msg("template not found")
+		return nil, &templateNotFoundError{name: name}
+	}
+
+	return tmpl, nil
+}
+
+// templateNotFoundError is returned by find when the template isn't registered. It wraps
+// echo.ErrInternalServerError, so the echo error handler sends a 500 response.
+type templateNotFoundError struct {
+	name string
+}
+
+func (e *templateNotFoundError) Error() string {
+	return fmt.Sprintf("template: no template named %#q", e.name)
+}
+
+func (e *templateNotFoundError) Unwrap() error {
+	return echo.ErrInternalServerError
+}
 
+// sendNotFound sends an internal server error response when the template isn't found by the public
+// render methods, responses composed from several renders leave it to the error handler so nothing is
+// written twice.
+func sendNotFound(c echo.Context, err error) error {
+	var nerr *templateNotFoundError
+	if c != nil && errors.As(err, &nerr) {
 		return c.NoContent(http.StatusInternalServerError)
 	}
 
-	// use the name of the template, or layout if it exists
-	execName := tmpl.name
-	if tmpl.layout != "" {
-		execName = tmpl.layout
+	return err
+}
+
+// executeNested executes the template directly with the data, for templates rendered by a template
+// function such as component and cache, as the view model, decorators, hooks and stats of a render
+// apply to the page rendering them.
+func (t *TemplateRenderer) executeNested(name string, data any) (string, error) {
+	tmpl, ok := t.lookup(name)
+	if !ok {
+		return "", &templateNotFoundError{name: name}
+	}
+
+	exec, err := tmpl.executable(nil)
+	if err != nil {
+		return "", err
+	}
+
+	out := new(strings.Builder)
+
+	err = exec.ExecuteTemplate(out, tmpl.execName(), data)
+	if err != nil {
+		return "", tmpl.renderError(err)
+	}
+
+	return out.String(), nil
+}
+
+// execute executes the named block of the template, or the template itself if block is empty.
+func (t *TemplateRenderer) execute(w io.Writer, tmpl *Template, block string, data interface{}, c echo.Context) error {
+	if block == "" {
+		err := t.checkExpected(tmpl.name, data)
+		if err != nil {
+			t.ctxLog(c).Error().Err(err).Str("name", tmpl.name).Msg("render template failed")
+			return err
+		}
+	}
+
+	if c != nil && tmpl.contentType != "" && c.Response().Header().Get(echo.HeaderContentType) == "" {
+		c.Response().Header().Set(echo.HeaderContentType, tmpl.contentType)
+	}
+
+	if c != nil && block == "" && tmpl.preload {
+		t.sendPreloads(c, tmpl)
 	}
 
+	return t.executeInstrumented(w, tmpl, block, data, c)
+}
+
+// executeInstrumented executes the template, recording the stats, trace span and render event and calling
+// the hooks once it is complete.
+func (t *TemplateRenderer) executeInstrumented(w io.Writer, tmpl *Template, block string, data interface{}, c echo.Context) error {
 	start := time.Now()
-	err := tmpl.template.ExecuteTemplate(w, execName, data)
+
+	var endSpan func(RenderInfo)
+
+	if t.startSpan != nil && c != nil {
+		req := c.Request()
+
+		ctx, end := t.startSpan(req.Context(), "render "+tmpl.name)
+		c.SetRequest(req.WithContext(ctx))
+		defer c.SetRequest(req)
+
+		endSpan = end
+	} else if t.startSpan != nil {
+		_, endSpan = t.startSpan(context.Background(), "render "+tmpl.name)
+	}
+
+	cw := &countingWriter{w: w}
+
+	var err error
+
+	// streamed output is written as it is rendered
+	_, streaming := w.(*headFlusher)
+
+	if (t.debug || len(t.transformers) > 0) && !streaming {
+		err = t.executeBuffered(cw, tmpl, block, data, c)
+	} else {
+		err = t.executeTemplate(cw, tmpl, block, data, c)
+	}
+
+	info := RenderInfo{
+		Name:     tmpl.name,
+		Layout:   tmpl.layout,
+		Block:    block,
+		Bytes:    cw.n,
+		Duration: time.Since(start),
+		Err:      err,
+	}
+
+	if endSpan != nil {
+		endSpan(info)
+	}
+
+	t.stats.record(info)
+	t.emitRenderEvent(c, info)
+
+	if t.isSlowRender(info.Duration) {
+		t.ctxLog(c).Warn().
+			Str("name", tmpl.name).
+			Str("layout", tmpl.layout).
+			Str("block", block).
+			Str("data_type", fmt.Sprintf("%T", data)).
+			Str("dur", info.Duration.String()).
+			Str("threshold", t.slowRender.String()).
+			Msg("slow render")
+	}
+
+	for _, h := range t.hooks {
+		h.AfterRender(c, tmpl.name, time.Since(start), err)
+	}
+
 	if err != nil {
-		log.Ctx(c.Request().Context()).Error().Err(err).Str("name", tmpl.name).Str("layout", tmpl.layout).Msg("render template failed")
+		t.ctxLog(c).Error().Err(err).Str("name", tmpl.name).Str("layout", tmpl.layout).Str("block", block).Msg("render template failed")
 		return err
 	}
 
-	log.Ctx(c.Request().Context()).Debug().Str("name", tmpl.name).Str("dur", time.Since(start).String()).Str("layout", tmpl.layout).Msg("execute template")
+	t.ctxLog(c).Debug().Str("name", tmpl.name).Str("dur", time.Since(start).String()).Str("layout", tmpl.layout).Msg("execute template")
 
 	return nil
 }
 
+func (t *TemplateRenderer) executeTemplate(w io.Writer, tmpl *Template, block string, data interface{}, c echo.Context) (err error) {
+	defer tmpl.recoverPanic(&err)
+
+	// don't render pages for clients which have disconnected
+	w, err = t.cancellable(w, tmpl, c)
+	if err != nil {
+		return tmpl.renderError(err)
+	}
+
+	if tmpl.markdown != nil && data == nil {
+		data = tmpl.meta
+	}
+
+	execName := block
+	if execName == "" {
+		execName = tmpl.execName()
+	}
+
+	for _, h := range t.hooks {
+		data, err = h.BeforeRender(c, tmpl.name, data)
+		if err != nil {
+			return err
+		}
+	}
+
+	exec, err := tmpl.executable(c)
+	if err != nil {
+		return err
+	}
+
+	for _, decorate := range t.decorators {
+		data = decorate(c, data)
+	}
+
+	execData := data
+	if t.viewModel {
+		execData = t.newViewModel(c, tmpl, data)
+	}
+
+	// the cache is keyed by the data once the hooks and decorators have added any request values
+	if tmpl.pageCache != nil {
+		cached, err := t.executeCached(w, tmpl, block, data, c, func(w io.Writer) error {
+			return tmpl.renderError(exec.ExecuteTemplate(w, execName, execData))
+		})
+		if cached {
+			return err
+		}
+	}
+
+	return tmpl.renderError(exec.ExecuteTemplate(w, execName, execData))
+}
+
+// defaultLog returns the logger used outside of a request, which defaults to the global logger.
+func (t *TemplateRenderer) defaultLog() *zerolog.Logger {
+	if t.logger != nil {
+		return t.logger
+	}
+
+	return &log.Logger
+}
+
+// ctxLog returns the logger attached to the request context, falling back to the logger
+// configured using WithLogger.
+func (t *TemplateRenderer) ctxLog(c echo.Context) *zerolog.Logger {
+	if c == nil {
+		return t.defaultLog()
+	}
+
+	l := log.Ctx(c.Request().Context())
+	if l.GetLevel() == zerolog.Disabled && t.logger != nil {
+		l = t.logger
+	}
+
+	if t.requestLogFields {
+		rl := l.With().
+			Str("request_id", requestID(c)).
+			Str("route", c.Path()).
+			Str("method", c.Request().Method).
+			Logger()
+
+		return &rl
+	}
+
+	return l
+}
+
+// layoutFiles returns the layout as a chain of layouts, which is empty if there is no layout.
+func layoutFiles(layout string) []string {
+	if layout == "" {
+		return nil
+	}
+
+	return []string{layout}
+}
+
+// addVary adds the request header name to the Vary response header unless it is already listed, as a
+// response may be composed from several renders which each add it.
+func addVary(h http.Header, name string) {
+	for _, value := range h.Values(echo.HeaderVary) {
+		for _, field := range strings.Split(value, ",") {
+			field = strings.TrimSpace(field)
+			if field == "*" || strings.EqualFold(field, name) {
+				return
+			}
+		}
+	}
+
+	h.Add(echo.HeaderVary, name)
+}
+
+// matching lists the files matching the patterns.
+func matching(patterns []string) func(fsys fs.FS) ([]string, error) {
+	return func(fsys fs.FS) ([]string, error) {
+		return readFileNames(fsys, patterns...)
+	}
+}
+
+// literal lists the file names as they are, for names which may contain glob meta characters.
+func literal(filenames []string) func(fsys fs.FS) ([]string, error) {
+	return func(fs.FS) ([]string, error) {
+		return filenames, nil
+	}
+}
+
 func readFileNames(fsys fs.FS, patterns ...string) ([]string, error) {
 	var filenames []string
 
 	for _, pattern := range patterns {
-		list, err := fs.Glob(fsys, pattern)
+		list, err := glob(fsys, pattern)
 		if err != nil {
 			return nil, errors.Wrap(err, "failed to list using file pattern")
 		}
@@ -2,13 +2,19 @@ package templates_test
 
 import (
 	"bytes"
+	"fmt"
+	"io/fs"
 	"net/http"
 	"net/http/httptest"
+	"strings"
+	"sync"
 	"testing"
+	"testing/fstest"
 	"text/template"
 	"time"
 
 	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog"
 	"github.com/stretchr/testify/require"
 	templates "github.com/wolfeidau/echo-go-templates"
 	"github.com/wolfeidau/echo-go-templates/test/views"
@@ -40,6 +46,69 @@ func Test_CustomFuncs_AddWithLayout(t *testing.T) {
 	assert.Regexp(`layout index \d{2}:\d{2}:\d{2} `, output.String())
 	assert.Equal(200, rec.Result().StatusCode)
 }
+
+func Test_WithFuncs(t *testing.T) {
+	assert := require.New(t)
+
+	fsys := fstest.MapFS{
+		"index.html": {Data: []byte(`{{upper .}} {{len getTime}}`)},
+	}
+
+	render := templates.New(templates.WithFuncs(template.FuncMap{"upper": strings.ToUpper}))
+
+	err := render.Add(fsys, "*.html")
+	assert.NoError(err)
+
+	s, err := render.RenderString("index.html", "hello")
+	assert.NoError(err)
+	assert.Equal("HELLO 8", s)
+}
+
+func Test_WithLogger(t *testing.T) {
+	assert := require.New(t)
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/", http.NoBody)
+	rec := httptest.NewRecorder()
+
+	logs := bytes.NewBufferString("")
+
+	render := templates.New(templates.WithLogger(zerolog.New(logs)))
+
+	err := render.Add(views.Content, "fragments/*.html")
+	assert.NoError(err)
+
+	c := e.NewContext(req, rec)
+
+	err = render.Render(bytes.NewBufferString(""), "missing.html", nil, c)
+	assert.NoError(err)
+
+	assert.Contains(logs.String(), `"filename":"data.html"`)
+	assert.Contains(logs.String(), `"message":"template not found"`)
+	assert.Equal(500, rec.Result().StatusCode)
+}
+
+func Test_WithRequestLogFields(t *testing.T) {
+	assert := require.New(t)
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodPost, "/users/1", http.NoBody)
+	req.Header.Set(echo.HeaderXRequestID, "abc123")
+	rec := httptest.NewRecorder()
+
+	logs := bytes.NewBufferString("")
+
+	render := templates.New(templates.WithLogger(zerolog.New(logs)), templates.WithRequestLogFields())
+
+	c := e.NewContext(req, rec)
+	c.SetPath("/users/:id")
+
+	err := render.Render(bytes.NewBufferString(""), "missing.html", nil, c)
+	assert.NoError(err)
+
+	assert.Contains(logs.String(), `"request_id":"abc123","route":"/users/:id","method":"POST","name":"missing.html","message":"template not found"`)
+}
+
 func Test_AddWithLayout(t *testing.T) {
 	assert := require.New(t)
 
@@ -86,6 +155,57 @@ func Test_AddWithLayoutAndIncludes(t *testing.T) {
 	assert.Equal(200, rec.Result().StatusCode)
 }
 
+func Test_AddWithLayoutAndIncludePatterns(t *testing.T) {
+	assert := require.New(t)
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/", http.NoBody)
+	rec := httptest.NewRecorder()
+
+	render := templates.New()
+
+	err := render.AddWithLayoutAndIncludePatterns(views.Content, "layout.html", []string{"includes/header.html", "includes/footer.html"}, "pages/*.html")
+	assert.NoError(err)
+
+	output := bytes.NewBufferString("")
+
+	c := e.NewContext(req, rec)
+
+	err = render.Render(output, "index.html", nil, c)
+	assert.NoError(err)
+
+	assert.Regexp(`header layout index \d{2}:\d{2}:\d{2} footer`, output.String())
+	assert.Equal(200, rec.Result().StatusCode)
+}
+
+func Test_SetIncludes(t *testing.T) {
+	assert := require.New(t)
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/", http.NoBody)
+	rec := httptest.NewRecorder()
+
+	render := templates.New()
+
+	err := render.SetIncludes(views.Content, "includes/*.html")
+	assert.NoError(err)
+
+	err = render.AddWithLayout(views.Content, "layout.html", "pages/*.html")
+	assert.NoError(err)
+
+	output := bytes.NewBufferString("")
+
+	c := e.NewContext(req, rec)
+
+	err = render.Render(output, "index.html", nil, c)
+	assert.NoError(err)
+
+	assert.Regexp(`header layout index \d{2}:\d{2}:\d{2} footer`, output.String())
+
+	err = render.SetIncludes(views.Content, "missing/*.html")
+	assert.Error(err)
+}
+
 func Test_Add(t *testing.T) {
 	assert := require.New(t)
 
@@ -108,3 +228,761 @@ func Test_Add(t *testing.T) {
 	assert.Equal("data", output.String())
 	assert.Equal(200, rec.Result().StatusCode)
 }
+
+func Test_WithRelativeNames(t *testing.T) {
+	assert := require.New(t)
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/", http.NoBody)
+	rec := httptest.NewRecorder()
+
+	render := templates.New(templates.WithRelativeNames())
+
+	err := render.AddWithLayout(views.Content, "layout2.html", "pages/*.html", "admin/*.html")
+	assert.NoError(err)
+
+	c := e.NewContext(req, rec)
+
+	output := bytes.NewBufferString("")
+	err = render.Render(output, "pages/index.html", nil, c)
+	assert.NoError(err)
+	assert.Regexp(`layout index \d{2}:\d{2}:\d{2} `, output.String())
+
+	output = bytes.NewBufferString("")
+	err = render.Render(output, "admin/index.html", nil, c)
+	assert.NoError(err)
+	assert.Equal("layout admin index ", output.String())
+}
+
+func Test_WithNameFunc(t *testing.T) {
+	assert := require.New(t)
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/", http.NoBody)
+	rec := httptest.NewRecorder()
+
+	render := templates.New(templates.WithNameFunc(templates.TrimExt(templates.RelativePath)))
+
+	err := render.AddWithLayout(views.Content, "layout2.html", "admin/*.html")
+	assert.NoError(err)
+
+	err = render.Group(templates.WithNameFunc(templates.Prefix("frag/", templates.TrimExt(templates.BaseName)))).Add(views.Content, "fragments/*.html")
+	assert.NoError(err)
+
+	c := e.NewContext(req, rec)
+
+	output := bytes.NewBufferString("")
+	err = render.Render(output, "admin/index", nil, c)
+	assert.NoError(err)
+	assert.Equal("layout admin index ", output.String())
+
+	output = bytes.NewBufferString("")
+	err = render.Render(output, "frag/data", nil, c)
+	assert.NoError(err)
+	assert.Equal("data", output.String())
+}
+
+func Test_WithStrict(t *testing.T) {
+	assert := require.New(t)
+
+	render := templates.New(templates.WithStrict())
+
+	err := render.AddWithLayout(views.Content, "layout2.html", "pages/*.html", "admin/*.html")
+	assert.EqualError(err, "template: duplicate template name: `index.html`")
+
+	render = templates.New(templates.WithStrict(), templates.WithRelativeNames())
+
+	err = render.AddWithLayout(views.Content, "layout2.html", "pages/*.html", "admin/*.html")
+	assert.NoError(err)
+}
+
+func Test_ConcurrentAddAndRender(t *testing.T) {
+	assert := require.New(t)
+
+	e := echo.New()
+
+	render := templates.New()
+
+	err := render.Add(views.Content, "fragments/*.html")
+	assert.NoError(err)
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < 10; i++ {
+		wg.Add(2)
+
+		go func() {
+			defer wg.Done()
+			assert.NoError(render.AddWithLayout(views.Content, "layout2.html", "pages/*.html"))
+		}()
+
+		go func() {
+			defer wg.Done()
+
+			req := httptest.NewRequest(http.MethodGet, "/", http.NoBody)
+			c := e.NewContext(req, httptest.NewRecorder())
+
+			output := bytes.NewBufferString("")
+			assert.NoError(render.Render(output, "data.html", nil, c))
+			assert.Equal("data", output.String())
+		}()
+	}
+
+	wg.Wait()
+}
+
+func Test_ReplaceAndRemove(t *testing.T) {
+	assert := require.New(t)
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/", http.NoBody)
+	rec := httptest.NewRecorder()
+
+	render := templates.New(templates.WithStrict())
+
+	err := render.AddWithLayout(views.Content, "layout2.html", "pages/*.html")
+	assert.NoError(err)
+
+	theme, err := fs.Sub(views.Content, "theme")
+	assert.NoError(err)
+
+	err = render.Replace(templates.Overlay(theme, views.Content), "pages/*.html")
+	assert.NoError(err)
+
+	c := e.NewContext(req, rec)
+
+	output := bytes.NewBufferString("")
+	err = render.Render(output, "index.html", nil, c)
+	assert.NoError(err)
+	assert.Equal("layout themed index ", output.String())
+
+	assert.True(render.Remove("index.html"))
+	assert.False(render.Remove("index.html"))
+
+	err = render.Render(output, "index.html", nil, c)
+	assert.NoError(err)
+	assert.Equal(500, rec.Result().StatusCode)
+}
+
+func Test_Reload(t *testing.T) {
+	assert := require.New(t)
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/", http.NoBody)
+	rec := httptest.NewRecorder()
+
+	fsys := fstest.MapFS{
+		"layout.html":      {Data: []byte(`layout {{block "content" .}}{{end}}`)},
+		"pages/index.html": {Data: []byte(`{{define "content"}}index{{end}}`)},
+		"pages/about.html": {Data: []byte(`{{define "content"}}about{{end}}`)},
+	}
+
+	render := templates.New()
+
+	err := render.AddWithLayout(fsys, "layout.html", "pages/*.html")
+	assert.NoError(err)
+	assert.True(render.Remove("about.html"))
+
+	fsys["pages/index.html"] = &fstest.MapFile{Data: []byte(`{{define "content"}}reloaded{{end}}`)}
+
+	err = render.Reload()
+	assert.NoError(err)
+
+	c := e.NewContext(req, rec)
+
+	output := bytes.NewBufferString("")
+	err = render.Render(output, "index.html", nil, c)
+	assert.NoError(err)
+	assert.Equal("layout reloaded", output.String())
+
+	fsys["pages/index.html"] = &fstest.MapFile{Data: []byte(`{{define "content"}}{{end`)}
+
+	err = render.Reload()
+	assert.Error(err)
+
+	output = bytes.NewBufferString("")
+	err = render.Render(output, "index.html", nil, c)
+	assert.NoError(err)
+	assert.Equal("layout reloaded", output.String())
+
+	err = render.Render(output, "about.html", nil, c)
+	assert.NoError(err)
+	assert.Equal(500, rec.Result().StatusCode)
+}
+
+func Test_Reload_DropsReplacedOps(t *testing.T) {
+	assert := require.New(t)
+
+	mapFS := fstest.MapFS{
+		"layout.html":      {Data: []byte(`layout {{block "content" .}}{{end}}`)},
+		"pages/index.html": {Data: []byte(`{{define "content"}}index{{end}}`)},
+		"pages/about.html": {Data: []byte(`{{define "content"}}about{{end}}`)},
+		"pages/old.html":   {Data: []byte(`{{define "content"}}old{{end}}`)},
+		"pages/solo.html":  {Data: []byte(`{{define "content"}}solo{{end}}`)},
+	}
+	fsys := &readCountFS{FS: mapFS, counts: make(map[string]int)}
+
+	render := templates.New()
+
+	err := render.AddWithLayout(fsys, "layout.html", "pages/index.html", "pages/about.html")
+	assert.NoError(err)
+
+	err = render.AddWithLayout(fsys, "layout.html", "pages/old.html")
+	assert.NoError(err)
+	assert.True(render.Remove("old.html"))
+
+	// the Add is dropped once solo.html is replaced, the Replace keeps its layout
+	err = render.AddWithLayout(fsys, "layout.html", "pages/solo.html")
+	assert.NoError(err)
+	assert.NoError(render.Replace(fsys, "pages/solo.html"))
+
+	for i := 0; i < 10; i++ {
+		err = render.Replace(fsys, "pages/index.html")
+		assert.NoError(err)
+	}
+
+	fsys.counts = make(map[string]int)
+	mapFS["pages/index.html"] = &fstest.MapFile{Data: []byte(`{{define "content"}}reloaded{{end}}`)}
+
+	err = render.Reload()
+	assert.NoError(err)
+
+	// index.html is read by the Add and the latest Replace, rather than every Replace, and the removed
+	// template is not read at all
+	assert.Equal(2, fsys.counts["pages/index.html"])
+	assert.Equal(1, fsys.counts["pages/about.html"])
+	assert.Zero(fsys.counts["pages/old.html"])
+
+	out, err := render.RenderString("index.html", nil)
+	assert.NoError(err)
+	assert.Equal("layout reloaded", out)
+
+	out, err = render.RenderString("about.html", nil)
+	assert.NoError(err)
+	assert.Equal("layout about", out)
+
+	out, err = render.RenderString("solo.html", nil)
+	assert.NoError(err)
+	assert.Equal("layout solo", out)
+	assert.Equal(1, fsys.counts["pages/solo.html"])
+
+	_, ok := render.Lookup("old.html")
+	assert.False(ok)
+}
+
+func Test_WithReload(t *testing.T) {
+	assert := require.New(t)
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/", http.NoBody)
+	rec := httptest.NewRecorder()
+
+	fsys := fstest.MapFS{
+		"data.html": {Data: []byte(`data`)},
+	}
+
+	render := templates.New(templates.WithReload())
+
+	err := render.Add(fsys, "*.html")
+	assert.NoError(err)
+
+	fsys["data.html"] = &fstest.MapFile{Data: []byte(`changed`)}
+
+	c := e.NewContext(req, rec)
+
+	output := bytes.NewBufferString("")
+	err = render.Render(output, "data.html", nil, c)
+	assert.NoError(err)
+	assert.Equal("changed", output.String())
+}
+
+func Test_AddWithLayouts(t *testing.T) {
+	assert := require.New(t)
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/", http.NoBody)
+	rec := httptest.NewRecorder()
+
+	fsys := fstest.MapFS{
+		"base.html":        {Data: []byte(`<body>{{block "content" .}}{{end}}</body>`)},
+		"admin.html":       {Data: []byte(`{{define "content"}}<nav>admin</nav>{{block "page" .}}{{end}}{{end}}`)},
+		"admin/users.html": {Data: []byte(`{{define "page"}}users{{end}}`)},
+	}
+
+	render := templates.New()
+
+	err := render.AddWithLayouts(fsys, []string{"base.html", "admin.html"}, "admin/*.html")
+	assert.NoError(err)
+
+	output := bytes.NewBufferString("")
+
+	err = render.Render(output, "users.html", nil, e.NewContext(req, rec))
+	assert.NoError(err)
+	assert.Equal("<body><nav>admin</nav>users</body>", output.String())
+}
+
+// readCountFS counts the number of times each file is read.
+type readCountFS struct {
+	fs.FS
+	mu     sync.Mutex
+	counts map[string]int
+}
+
+func (f *readCountFS) Open(name string) (fs.File, error) {
+	file, err := f.FS.Open(name)
+	if err != nil {
+		return nil, err
+	}
+
+	if info, err := file.Stat(); err != nil || info.IsDir() {
+		return file, err
+	}
+
+	return &readCountFile{File: file, read: func() {
+		f.mu.Lock()
+		f.counts[name]++
+		f.mu.Unlock()
+	}}, nil
+}
+
+type readCountFile struct {
+	fs.File
+	read func()
+	once sync.Once
+}
+
+func (f *readCountFile) Read(p []byte) (int, error) {
+	f.once.Do(f.read)
+	return f.File.Read(p)
+}
+
+func Test_AddWithLayout_ParsesLayoutOnce(t *testing.T) {
+	assert := require.New(t)
+
+	fsys := &readCountFS{
+		FS: fstest.MapFS{
+			"layout.html":  {Data: []byte(`<body>{{block "content" .}}{{end}}{{block "footer" .}}footer{{end}}</body>`)},
+			"other.html":   {Data: []byte(`<main>{{block "content" .}}{{end}}</main>`)},
+			"pages/a.html": {Data: []byte(`{{define "content"}}a {{meta "title"}}{{end}}{{define "footer"}}a footer{{end}}`)},
+			"pages/b.html": {Data: []byte("---\ntitle: B\n---\n{{define \"content\"}}b {{meta \"title\"}}{{end}}")},
+			"pages/c.html": {Data: []byte(`{{/* layout: other.html */}}{{define "content"}}c{{end}}`)},
+		},
+		counts: make(map[string]int),
+	}
+
+	render := templates.New(templates.WithParseConcurrency(2))
+
+	err := render.AddWithLayout(fsys, "layout.html", "pages/*.html")
+	assert.NoError(err)
+
+	assert.Equal(1, fsys.counts["layout.html"])
+	assert.Equal(1, fsys.counts["other.html"])
+
+	// definitions in one page don't leak into the others
+	for name, expected := range map[string]string{
+		"a.html": "<body>a a footer</body>",
+		"b.html": "<body>b Bfooter</body>",
+		"c.html": "<main>c</main>",
+	} {
+		out, err := render.RenderString(name, nil)
+		assert.NoError(err)
+		assert.Equal(expected, out, name)
+	}
+}
+
+func Test_AddWithLayoutAndIncludes_ParsesIncludesOnce(t *testing.T) {
+	assert := require.New(t)
+
+	fsys := &readCountFS{
+		FS: fstest.MapFS{
+			"layout.html":          {Data: []byte(`<body>{{template "header" .}}{{block "content" .}}{{end}}</body>`)},
+			"includes/header.html": {Data: []byte(`{{define "header"}}<h1>{{.}}</h1>{{end}}`)},
+			"includes/card.html":   {Data: []byte(`{{define "card"}}<div>{{.}}</div>{{end}}`)},
+			"pages/a.html":         {Data: []byte(`{{define "content"}}{{template "card" "a"}}{{end}}`)},
+			"pages/b.html":         {Data: []byte(`{{define "content"}}b{{end}}{{define "header"}}<h2>{{.}}</h2>{{end}}`)},
+		},
+		counts: make(map[string]int),
+	}
+
+	render := templates.New(templates.WithParseConcurrency(2))
+
+	err := render.AddWithLayoutAndIncludes(fsys, "layout.html", "includes/*.html", "pages/*.html")
+	assert.NoError(err)
+
+	assert.Equal(1, fsys.counts["includes/header.html"])
+	assert.Equal(1, fsys.counts["includes/card.html"])
+
+	out, err := render.RenderString("a.html", "title")
+	assert.NoError(err)
+	assert.Equal("<body><h1>title</h1><div>a</div></body>", out)
+
+	// pages override includes without changing the other pages
+	out, err = render.RenderString("b.html", "title")
+	assert.NoError(err)
+	assert.Equal("<body><h2>title</h2>b</body>", out)
+
+	out, err = render.RenderString("a.html", "again")
+	assert.NoError(err)
+	assert.Equal("<body><h1>again</h1><div>a</div></body>", out)
+}
+
+func Test_RenderWithLayout(t *testing.T) {
+	assert := require.New(t)
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/", http.NoBody)
+	rec := httptest.NewRecorder()
+
+	render := templates.New()
+
+	err := render.AddWithLayoutAndIncludes(views.Content, "layout.html", "includes/*.html", "pages/*.html")
+	assert.NoError(err)
+
+	c := e.NewContext(req, rec)
+
+	for i := 0; i < 2; i++ {
+		output := bytes.NewBufferString("")
+		err = render.RenderWithLayout(output, "index.html", "layout2.html", nil, c)
+		assert.NoError(err)
+		assert.Regexp(`^layout index \d{2}:\d{2}:\d{2} $`, output.String())
+	}
+
+	output := bytes.NewBufferString("")
+	err = render.Render(output, "index.html", nil, c)
+	assert.NoError(err)
+	assert.Regexp(`^header layout index \d{2}:\d{2}:\d{2} footer$`, output.String())
+
+	err = render.RenderWithLayout(output, "index.html", "missing.html", nil, c)
+	assert.Error(err)
+}
+
+func Test_RenderFragment(t *testing.T) {
+	assert := require.New(t)
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/", http.NoBody)
+	rec := httptest.NewRecorder()
+
+	render := templates.New()
+
+	err := render.AddWithLayoutAndIncludes(views.Content, "layout.html", "includes/*.html", "pages/*.html")
+	assert.NoError(err)
+
+	c := e.NewContext(req, rec)
+
+	output := bytes.NewBufferString("")
+	err = render.Render(output, "index.html#content", nil, c)
+	assert.NoError(err)
+	assert.Regexp(`^index \d{2}:\d{2}:\d{2} $`, output.String())
+
+	output = bytes.NewBufferString("")
+	err = render.Render(output, "index.html#header", nil, c)
+	assert.NoError(err)
+	assert.Equal("header ", output.String())
+
+	err = render.Render(output, "index.html#missing", nil, c)
+	assert.Error(err)
+}
+
+func Test_RenderBlock(t *testing.T) {
+	assert := require.New(t)
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/", http.NoBody)
+	rec := httptest.NewRecorder()
+
+	fsys := fstest.MapFS{
+		"layout.html":     {Data: []byte(`<body>{{block "content" .}}{{end}}</body>`)},
+		"pages/list.html": {Data: []byte(`{{define "content"}}<table>{{range .}}{{template "row" .}}{{end}}</table>{{end}}{{define "row"}}<tr>{{.}}</tr>{{end}}`)},
+	}
+
+	render := templates.New()
+
+	err := render.AddWithLayout(fsys, "layout.html", "pages/*.html")
+	assert.NoError(err)
+
+	c := e.NewContext(req, rec)
+
+	output := bytes.NewBufferString("")
+	err = render.RenderBlock(output, "list.html", "row", "three", c)
+	assert.NoError(err)
+	assert.Equal("<tr>three</tr>", output.String())
+
+	output = bytes.NewBufferString("")
+	err = render.Render(output, "list.html", []string{"one", "two"}, c)
+	assert.NoError(err)
+	assert.Equal("<body><table><tr>one</tr><tr>two</tr></table></body>", output.String())
+}
+
+func Test_WithDelims(t *testing.T) {
+	assert := require.New(t)
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/", http.NoBody)
+	rec := httptest.NewRecorder()
+
+	fsys := fstest.MapFS{
+		"base.html":        {Data: []byte(`<title>[[meta "title"]]</title>[[block "content" .]][[end]]`)},
+		"bare.html":        {Data: []byte(`bare [[block "content" .]][[end]]`)},
+		"pages/index.html": {Data: []byte("---\ntitle: Index\n---\n[[define \"content\"]]<p>{{ message }}</p> [[.]][[end]]")},
+		"pages/other.html": {Data: []byte(`[[/* layout: bare.html */]][[define "content"]]other[[end]]`)},
+	}
+
+	render := templates.New(templates.WithDelims("[[", "]]"))
+
+	err := render.AddWithLayout(fsys, "base.html", "pages/*.html")
+	assert.NoError(err)
+
+	c := e.NewContext(req, rec)
+
+	output := bytes.NewBufferString("")
+	err = render.Render(output, "index.html", "data", c)
+	assert.NoError(err)
+	assert.Equal("<title>Index</title><p>{{ message }}</p> data", output.String())
+
+	output = bytes.NewBufferString("")
+	err = render.Render(output, "other.html", nil, c)
+	assert.NoError(err)
+	assert.Equal("bare other", output.String())
+}
+
+func Test_WithTemplateOptions(t *testing.T) {
+	assert := require.New(t)
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/", http.NoBody)
+	rec := httptest.NewRecorder()
+
+	fsys := fstest.MapFS{
+		"title.html": {Data: []byte(`{{.Title}}`)},
+	}
+
+	c := e.NewContext(req, rec)
+
+	render := templates.New()
+
+	err := render.Add(fsys, "*.html")
+	assert.NoError(err)
+
+	output := bytes.NewBufferString("")
+	err = render.Render(output, "title.html", map[string]any{}, c)
+	assert.NoError(err)
+
+	render = templates.New(templates.WithTemplateOptions("missingkey=error"))
+
+	err = render.Add(fsys, "*.html")
+	assert.NoError(err)
+
+	err = render.Render(output, "title.html", map[string]any{}, c)
+	assert.ErrorContains(err, `map has no entry for key "Title"`)
+}
+
+func Test_Group_WithFuncs(t *testing.T) {
+	assert := require.New(t)
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/", http.NoBody)
+	rec := httptest.NewRecorder()
+
+	fsys := fstest.MapFS{
+		"emails/welcome.html": {Data: []byte(`{{greet .}}`)},
+		"pages/welcome.html":  {Data: []byte(`{{greet .}}`)},
+	}
+
+	render := templates.New(templates.WithFuncs(template.FuncMap{
+		"greet": func(name string) string { return "Welcome " + name },
+	}))
+
+	err := render.Group(
+		templates.WithFuncs(template.FuncMap{
+			"greet": func(name string) string { return "Dear " + name },
+		}),
+		templates.WithNameFunc(templates.RelativePath),
+	).Add(fsys, "emails/*.html")
+	assert.NoError(err)
+
+	err = render.Add(fsys, "pages/*.html")
+	assert.NoError(err)
+
+	c := e.NewContext(req, rec)
+
+	output := bytes.NewBufferString("")
+	err = render.Render(output, "emails/welcome.html", "Mark", c)
+	assert.NoError(err)
+	assert.Equal("Dear Mark", output.String())
+
+	output = bytes.NewBufferString("")
+	err = render.Render(output, "welcome.html", "Mark", c)
+	assert.NoError(err)
+	assert.Equal("Welcome Mark", output.String())
+}
+
+func Test_WithContextFuncs(t *testing.T) {
+	assert := require.New(t)
+
+	e := echo.New()
+
+	fsys := fstest.MapFS{
+		"layout.html":     {Data: []byte(`{{if isActive "/about"}}<b>about</b>{{end}} {{block "content" .}}{{end}}`)},
+		"pages/user.html": {Data: []byte(`{{define "content"}}{{currentUser}}{{end}}`)},
+	}
+
+	render := templates.New(templates.WithContextFuncs(func(c echo.Context) template.FuncMap {
+		return template.FuncMap{
+			"currentUser": func() string { return c.Get("user").(string) },
+			"isActive":    func(p string) bool { return c.Request().URL.Path == p },
+		}
+	}))
+
+	err := render.AddWithLayout(fsys, "layout.html", "pages/*.html")
+	assert.NoError(err)
+
+	for _, user := range []string{"mark", "bob"} {
+		req := httptest.NewRequest(http.MethodGet, "/about", http.NoBody)
+		c := e.NewContext(req, httptest.NewRecorder())
+		c.Set("user", user)
+
+		output := bytes.NewBufferString("")
+		err = render.Render(output, "user.html", nil, c)
+		assert.NoError(err)
+		assert.Equal("<b>about</b> "+user, output.String())
+	}
+}
+
+func Test_RenderString(t *testing.T) {
+	assert := require.New(t)
+
+	fsys := fstest.MapFS{
+		"layout.html":       {Data: []byte(`<html>{{block "content" .}}{{end}}</html>`)},
+		"emails/hello.html": {Data: []byte(`{{define "content"}}Hello {{.Name}}{{csrf}}{{.}}{{end}}`)},
+	}
+
+	render := templates.New(templates.WithHTMX("content"), templates.WithCSRF("", ""))
+
+	err := render.AddWithLayout(fsys, "layout.html", "emails/*.html")
+	assert.NoError(err)
+
+	s, err := render.RenderString("hello.html", map[string]string{"Name": "mark"})
+	assert.NoError(err)
+	assert.Equal("<html>Hello markmap[Name:mark]</html>", s)
+
+	b, err := render.RenderBytes("hello.html#content", map[string]string{"Name": "mark"})
+	assert.NoError(err)
+	assert.Equal("Hello markmap[Name:mark]", string(b))
+
+	_, err = render.RenderString("missing.html", nil)
+	assert.EqualError(err, "template: no template named `missing.html`")
+}
+
+func Test_WithTextTemplates(t *testing.T) {
+	assert := require.New(t)
+
+	fsys := fstest.MapFS{
+		"layout.txt":       {Data: []byte(`Hi {{.Name}},{{block "content" .}}{{end}}`)},
+		"emails/hello.txt": {Data: []byte(`{{define "content"}} <b>{{.Body}}</b> & {{upper .Name}}{{end}}`)},
+		"pages/index.html": {Data: []byte(`<p>{{.Body}}</p>`)},
+	}
+
+	render := templates.New(templates.WithFuncs(templates.CommonFuncs()))
+
+	err := render.Group(templates.WithTextTemplates()).AddWithLayout(fsys, "layout.txt", "emails/*.txt")
+	assert.NoError(err)
+
+	err = render.Add(fsys, "pages/*.html")
+	assert.NoError(err)
+
+	data := map[string]string{"Name": "O'Brien", "Body": "<hello>"}
+
+	s, err := render.RenderString("hello.txt", data)
+	assert.NoError(err)
+	assert.Equal("Hi O'Brien, <b><hello></b> & O'BRIEN", s)
+
+	s, err = render.RenderString("index.html", data)
+	assert.NoError(err)
+	assert.Equal("<p>&lt;hello&gt;</p>", s)
+
+	assert.NoError(render.Validate())
+}
+
+func Test_ParseConcurrency(t *testing.T) {
+	assert := require.New(t)
+
+	fsys := fstest.MapFS{
+		"layout.html": {Data: []byte(`<html>{{block "content" .}}{{end}}</html>`)},
+	}
+
+	for i := 0; i < 50; i++ {
+		fsys[fmt.Sprintf("pages/page%02d.html", i)] = &fstest.MapFile{Data: []byte(fmt.Sprintf(`{{define "content"}}page %d{{end}}`, i))}
+	}
+
+	render := templates.New(templates.WithParseConcurrency(4))
+
+	err := render.AddWithLayout(fsys, "layout.html", "pages/*.html")
+	assert.NoError(err)
+	assert.Len(render.Names(), 50)
+
+	s, err := render.RenderString("page42.html", nil)
+	assert.NoError(err)
+	assert.Equal("<html>page 42</html>", s)
+
+	fsys["pages/page10.html"] = &fstest.MapFile{Data: []byte(`{{if}}`)}
+	fsys["pages/page30.html"] = &fstest.MapFile{Data: []byte(`{{if}}`)}
+
+	err = templates.New().AddWithLayout(fsys, "layout.html", "pages/*.html")
+	assert.ErrorContains(err, "failed to parse template pages/page10.html")
+}
+
+func Test_WithLazyParsing(t *testing.T) {
+	assert := require.New(t)
+
+	fsys := fstest.MapFS{
+		"layout.html":       {Data: []byte(`<html>{{block "content" .}}{{end}}</html>`)},
+		"pages/index.html":  {Data: []byte(`{{define "content"}}index{{end}}`)},
+		"pages/broken.html": {Data: []byte(`{{if}}`)},
+	}
+
+	render := templates.New(templates.WithLazyParsing())
+
+	err := render.AddWithLayout(fsys, "layout.html", "pages/*.html")
+	assert.NoError(err)
+	assert.ElementsMatch([]string{"index.html", "broken.html"}, render.Names())
+
+	s, err := render.RenderString("index.html", nil)
+	assert.NoError(err)
+	assert.Equal("<html>index</html>", s)
+
+	_, err = render.RenderString("broken.html", nil)
+	assert.ErrorContains(err, "failed to parse template pages/broken.html")
+
+	err = render.Validate()
+	assert.ErrorContains(err, "failed to parse template pages/broken.html")
+
+	err = templates.New(templates.WithLazyParsing()).AddWithLayout(fsys, "layout.html", "pages/missing.html")
+	assert.Error(err)
+}
+
+func Test_WithXMLTemplates(t *testing.T) {
+	assert := require.New(t)
+
+	fsys := fstest.MapFS{
+		"feed.xml": {Data: []byte(`<?xml version="1.0" encoding="UTF-8"?><title>{{xml .}}</title>`)},
+	}
+
+	render := templates.New()
+
+	err := render.Group(templates.WithXMLTemplates()).Add(fsys, "*.xml")
+	assert.NoError(err)
+
+	e := echo.New()
+	e.Renderer = render
+	e.GET("/feed.xml", func(c echo.Context) error {
+		return c.Render(http.StatusOK, "feed.xml", "Tom & Jerry")
+	})
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/feed.xml", nil))
+
+	assert.Equal(http.StatusOK, rec.Code)
+	assert.Equal(echo.MIMEApplicationXMLCharsetUTF8, rec.Header().Get(echo.HeaderContentType))
+	assert.Equal(`<?xml version="1.0" encoding="UTF-8"?><title>Tom &amp; Jerry</title>`, rec.Body.String())
+}
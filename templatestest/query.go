@@ -0,0 +1,150 @@
+package templatestest
+
+import (
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// FindAll returns the elements below the node matching the selector, in document order. Selectors are a
+// tag name, #id, .class or a tag name followed by an id or classes such as "li.active", separated by
+// spaces to match descendants such as "nav a.current".
+func FindAll(n *html.Node, selector string) []*html.Node {
+	nodes := []*html.Node{n}
+
+	for _, part := range strings.Fields(selector) {
+		sel := parseSelector(part)
+
+		var matches []*html.Node
+
+		seen := make(map[*html.Node]bool)
+
+		for _, root := range nodes {
+			walk(root, func(el *html.Node) {
+				if el != root && !seen[el] && sel.matches(el) {
+					seen[el] = true
+					matches = append(matches, el)
+				}
+			})
+		}
+
+		nodes = matches
+	}
+
+	return nodes
+}
+
+// Find returns the first element below the node matching the selector, or nil if there isn't one.
+func Find(n *html.Node, selector string) *html.Node {
+	if nodes := FindAll(n, selector); len(nodes) > 0 {
+		return nodes[0]
+	}
+
+	return nil
+}
+
+// Text returns the text content of the node with leading and trailing white space removed, it returns an
+// empty string for a nil node.
+func Text(n *html.Node) string {
+	if n == nil {
+		return ""
+	}
+
+	buf := new(strings.Builder)
+
+	walk(n, func(el *html.Node) {
+		if el.Type == html.TextNode {
+			buf.WriteString(el.Data)
+		}
+	})
+
+	return strings.TrimSpace(buf.String())
+}
+
+// Attr returns the value of the attribute of the node, or an empty string if it isn't set.
+func Attr(n *html.Node, key string) string {
+	if n == nil {
+		return ""
+	}
+
+	for _, a := range n.Attr {
+		if a.Key == key {
+			return a.Val
+		}
+	}
+
+	return ""
+}
+
+type selector struct {
+	tag     string
+	id      string
+	classes []string
+}
+
+func parseSelector(s string) selector {
+	var sel selector
+
+	for s != "" {
+		end := strings.IndexAny(s[1:], "#.") + 1
+		if end == 0 {
+			end = len(s)
+		}
+
+		part := s[:end]
+		s = s[end:]
+
+		switch part[0] {
+		case '#':
+			sel.id = part[1:]
+		case '.':
+			sel.classes = append(sel.classes, part[1:])
+		default:
+			sel.tag = part
+		}
+	}
+
+	return sel
+}
+
+func (sel selector) matches(n *html.Node) bool {
+	if n.Type != html.ElementNode {
+		return false
+	}
+
+	if sel.tag != "" && sel.tag != n.Data {
+		return false
+	}
+
+	if sel.id != "" && Attr(n, "id") != sel.id {
+		return false
+	}
+
+	classes := strings.Fields(Attr(n, "class"))
+
+	for _, class := range sel.classes {
+		found := false
+
+		for _, c := range classes {
+			if c == class {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			return false
+		}
+	}
+
+	return true
+}
+
+// walk calls fn for the node and each of its descendants in document order.
+func walk(n *html.Node, fn func(*html.Node)) {
+	fn(n)
+
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		walk(c, fn)
+	}
+}
@@ -0,0 +1,108 @@
+// Package templatestest provides helpers for testing templates, rendering them with fixture data and
+// comparing the output with golden files, or querying the parsed HTML:
+//
+//	func TestIndex(t *testing.T) {
+//		out := templatestest.Render(t, render, "index.html", fixture)
+//		templatestest.AssertGolden(t, "testdata/index.golden.html", out)
+//
+//		doc := templatestest.ParseHTML(t, out)
+//		require.Equal(t, "Home", templatestest.Text(templatestest.Find(doc, "h1")))
+//	}
+//
+// Golden files are written by running the tests with the -update flag.
+package templatestest
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	templates "github.com/wolfeidau/echo-go-templates"
+	"golang.org/x/net/html"
+)
+
+var update = flag.Bool("update", false, "update golden files")
+
+// Render renders the named template with the data, failing the test if it returns an error.
+func Render(t testing.TB, render *templates.TemplateRenderer, name string, data any) string {
+	t.Helper()
+
+	out, err := render.RenderString(name, data)
+	if err != nil {
+		t.Fatalf("render %s: %v", name, err)
+	}
+
+	return out
+}
+
+// AssertGolden compares the output with the golden file, failing the test if they differ. When the tests
+// are run with -update the golden file is written instead.
+func AssertGolden(t testing.TB, golden, got string) {
+	t.Helper()
+
+	if *update {
+		err := os.MkdirAll(filepath.Dir(golden), 0o755)
+		if err == nil {
+			err = os.WriteFile(golden, []byte(got), 0o600)
+		}
+
+		if err != nil {
+			t.Fatalf("update golden file %s: %v", golden, err)
+		}
+
+		return
+	}
+
+	want, err := os.ReadFile(golden)
+	if err != nil {
+		t.Fatalf("read golden file %s, run with -update to create it: %v", golden, err)
+	}
+
+	if string(want) != got {
+		t.Errorf("output differs from golden file %s, run with -update to accept it:\n%s", golden, diff(string(want), got))
+	}
+}
+
+// RenderGolden renders the named template with the data and compares the output with the golden file.
+func RenderGolden(t testing.TB, render *templates.TemplateRenderer, name string, data any, golden string) {
+	t.Helper()
+
+	AssertGolden(t, golden, Render(t, render, name, data))
+}
+
+// diff returns the first line which differs between want and got.
+func diff(want, got string) string {
+	wantLines, gotLines := strings.Split(want, "\n"), strings.Split(got, "\n")
+
+	for i := 0; i < len(wantLines) || i < len(gotLines); i++ {
+		var w, g string
+		if i < len(wantLines) {
+			w = wantLines[i]
+		}
+
+		if i < len(gotLines) {
+			g = gotLines[i]
+		}
+
+		if w != g {
+			return fmt.Sprintf("line %d:\n- %s\n+ %s", i+1, w, g)
+		}
+	}
+
+	return ""
+}
+
+// ParseHTML parses the output as an HTML document, failing the test if it can't be parsed.
+func ParseHTML(t testing.TB, out string) *html.Node {
+	t.Helper()
+
+	doc, err := html.Parse(strings.NewReader(out))
+	if err != nil {
+		t.Fatalf("parse html: %v", err)
+	}
+
+	return doc
+}
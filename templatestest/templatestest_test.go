@@ -0,0 +1,67 @@
+package templatestest_test
+
+import (
+	"flag"
+	"fmt"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/require"
+	templates "github.com/wolfeidau/echo-go-templates"
+	"github.com/wolfeidau/echo-go-templates/templatestest"
+)
+
+type recordingT struct {
+	testing.TB
+	errors []string
+}
+
+func (r *recordingT) Helper() {}
+
+func (r *recordingT) Errorf(format string, args ...any) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func newRenderer(t *testing.T) *templates.TemplateRenderer {
+	fsys := fstest.MapFS{
+		"layout.html": {Data: []byte("<html>\n<body>\n{{block \"content\" .}}{{end}}\n</body>\n</html>\n")},
+		"index.html":  {Data: []byte(`{{define "content"}}<h1 id="title">{{.Title}}</h1><nav><a class="link current" href="/">Home</a> <a class="link" href="/about">About</a></nav>{{end}}`)},
+	}
+
+	render := templates.New()
+
+	err := render.AddWithLayout(fsys, "layout.html", "index.html")
+	require.NoError(t, err)
+
+	return render
+}
+
+func Test_RenderGolden(t *testing.T) {
+	render := newRenderer(t)
+
+	templatestest.RenderGolden(t, render, "index.html", map[string]string{"Title": "Home"}, "testdata/index.golden.html")
+
+	if flag.Lookup("update").Value.String() == "true" {
+		return
+	}
+
+	rt := &recordingT{TB: t}
+	templatestest.AssertGolden(rt, "testdata/index.golden.html", templatestest.Render(t, render, "index.html", map[string]string{"Title": "Changed"}))
+	require.Len(t, rt.errors, 1)
+	require.Contains(t, rt.errors[0], "line 3:\n- <h1 id=\"title\">Home</h1>")
+}
+
+func Test_ParseHTML(t *testing.T) {
+	assert := require.New(t)
+
+	out := templatestest.Render(t, newRenderer(t), "index.html", map[string]string{"Title": "Home"})
+	doc := templatestest.ParseHTML(t, out)
+
+	assert.Equal("Home", templatestest.Text(templatestest.Find(doc, "#title")))
+	assert.Equal("Home", templatestest.Text(templatestest.Find(doc, "h1#title")))
+	assert.Len(templatestest.FindAll(doc, "nav a.link"), 2)
+	assert.Equal("/", templatestest.Attr(templatestest.Find(doc, "nav .link.current"), "href"))
+	assert.Equal("Home About", templatestest.Text(templatestest.Find(doc, "nav")))
+	assert.Nil(templatestest.Find(doc, "table"))
+	assert.Empty(templatestest.Text(nil))
+}
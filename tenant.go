@@ -0,0 +1,80 @@
+package templates
+
+import (
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/labstack/echo/v4"
+)
+
+// MultiTenant is an echo.Renderer holding a TemplateRenderer for each tenant, selecting the renderer for
+// each request using a resolver. The renderer registered for the empty tenant name is the default, used
+// for tenants without their own templates.
+//
+//	tenants := templates.NewMultiTenant(func(c echo.Context) string {
+//		return c.Request().Host
+//	})
+//	tenants.Set("", defaultRender)
+//	tenants.Set("acme.example.com", acmeRender)
+//
+//	e.Renderer = tenants
+type MultiTenant struct {
+	resolve func(c echo.Context) string
+
+	mu      sync.RWMutex
+	tenants map[string]*TemplateRenderer
+}
+
+// NewMultiTenant creates a new MultiTenant using resolve to select the tenant for each request.
+func NewMultiTenant(resolve func(c echo.Context) string) *MultiTenant {
+	return &MultiTenant{
+		resolve: resolve,
+		tenants: make(map[string]*TemplateRenderer),
+	}
+}
+
+// Set sets the renderer for the tenant, replacing any existing renderer.
+func (m *MultiTenant) Set(tenant string, render *TemplateRenderer) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.tenants[tenant] = render
+}
+
+// Remove removes the renderer for the tenant, returning false if there wasn't one.
+func (m *MultiTenant) Remove(tenant string) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	_, ok := m.tenants[tenant]
+	delete(m.tenants, tenant)
+
+	return ok
+}
+
+// Get returns the renderer for the tenant, falling back to the default renderer.
+func (m *MultiTenant) Get(tenant string) (*TemplateRenderer, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if render, ok := m.tenants[tenant]; ok {
+		return render, true
+	}
+
+	render, ok := m.tenants[""]
+
+	return render, ok
+}
+
+// Render renders the template using the renderer for the tenant of the request.
+func (m *MultiTenant) Render(w io.Writer, name string, data interface{}, c echo.Context) error {
+	tenant := m.resolve(c)
+
+	render, ok := m.Get(tenant)
+	if !ok {
+		return fmt.Errorf("template: no templates for tenant %#q", tenant)
+	}
+
+	return render.Render(w, name, data, c)
+}
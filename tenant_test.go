@@ -0,0 +1,62 @@
+package templates_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"testing/fstest"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/require"
+	templates "github.com/wolfeidau/echo-go-templates"
+)
+
+func Test_MultiTenant(t *testing.T) {
+	assert := require.New(t)
+
+	base := fstest.MapFS{
+		"pages/index.html": {Data: []byte(`default {{.}}`)},
+	}
+
+	acme := fstest.MapFS{
+		"pages/index.html": {Data: []byte(`acme {{.}}`)},
+	}
+
+	defaultRender := templates.New()
+	assert.NoError(defaultRender.Add(base, "pages/*.html"))
+
+	acmeRender := templates.New()
+	assert.NoError(acmeRender.Add(acme, "pages/*.html"))
+
+	tenants := templates.NewMultiTenant(func(c echo.Context) string {
+		return c.Request().Host
+	})
+	tenants.Set("acme.example.com", acmeRender)
+
+	e := echo.New()
+	e.Renderer = tenants
+	e.GET("/", func(c echo.Context) error {
+		return c.Render(http.StatusOK, "index.html", "home")
+	})
+
+	serve := func(host string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/", http.NoBody)
+		req.Host = host
+
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+
+		return rec
+	}
+
+	assert.Equal("acme home", serve("acme.example.com").Body.String())
+	assert.Equal(http.StatusInternalServerError, serve("other.example.com").Code)
+
+	tenants.Set("", defaultRender)
+
+	assert.Equal("default home", serve("other.example.com").Body.String())
+
+	assert.True(tenants.Remove("acme.example.com"))
+	assert.False(tenants.Remove("acme.example.com"))
+	assert.Equal("default home", serve("acme.example.com").Body.String())
+}
@@ -2,5 +2,5 @@ package views
 
 import "embed"
 
-//go:embed pages/* pages2/* includes/* *.html fragments/*
+//go:embed pages/* pages2/* admin/* theme/pages/* includes/* *.html fragments/*
 var Content embed.FS
@@ -0,0 +1,59 @@
+package templates
+
+import (
+	"context"
+	"io"
+	"time"
+)
+
+// RenderInfo describes a completed render.
+type RenderInfo struct {
+	Name     string
+	Layout   string
+	Block    string
+	Bytes    int64
+	Duration time.Duration
+	Err      error
+}
+
+// StartSpanFunc starts a tracing span for a render as a child of the span in ctx, returning the context
+// containing the new span and a function which ends it. The context is used as the request context
+// while the template executes, so spans started by template functions are children of the render.
+type StartSpanFunc func(ctx context.Context, spanName string) (context.Context, func(info RenderInfo))
+
+// WithTracing creates a span for each render, this takes a function rather than a tracer so it works
+// with any tracing library without adding a dependency, for example with OpenTelemetry:
+//
+//	templates.WithTracing(func(ctx context.Context, spanName string) (context.Context, func(templates.RenderInfo)) {
+//		ctx, span := tracer.Start(ctx, spanName)
+//		return ctx, func(info templates.RenderInfo) {
+//			span.SetAttributes(
+//				attribute.String("template.name", info.Name),
+//				attribute.String("template.layout", info.Layout),
+//				attribute.Int64("template.bytes", info.Bytes),
+//			)
+//			if info.Err != nil {
+//				span.RecordError(info.Err)
+//				span.SetStatus(codes.Error, info.Err.Error())
+//			}
+//			span.End()
+//		}
+//	})
+func WithTracing(start StartSpanFunc) Option {
+	return func(t *TemplateRenderer) {
+		t.startSpan = start
+	}
+}
+
+// countingWriter counts the bytes written to the underlying writer.
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (cw *countingWriter) Write(p []byte) (int, error) {
+	n, err := cw.w.Write(p)
+	cw.n += int64(n)
+
+	return n, err
+}
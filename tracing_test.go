@@ -0,0 +1,66 @@
+package templates_test
+
+import (
+	"bytes"
+	"context"
+	"html/template"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"testing/fstest"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/require"
+	templates "github.com/wolfeidau/echo-go-templates"
+)
+
+type spanKey struct{}
+
+func Test_WithTracing(t *testing.T) {
+	assert := require.New(t)
+
+	fsys := fstest.MapFS{
+		"layout.html":      {Data: []byte(`<body>{{block "content" .}}{{end}}</body>`)},
+		"pages/index.html": {Data: []byte(`{{define "content"}}{{span}}{{end}}`)},
+	}
+
+	var (
+		spanNames []string
+		infos     []templates.RenderInfo
+	)
+
+	render := templates.New(
+		templates.WithTracing(func(ctx context.Context, spanName string) (context.Context, func(templates.RenderInfo)) {
+			spanNames = append(spanNames, spanName)
+			return context.WithValue(ctx, spanKey{}, spanName), func(info templates.RenderInfo) {
+				infos = append(infos, info)
+			}
+		}),
+		templates.WithContextFuncs(func(c echo.Context) template.FuncMap {
+			return template.FuncMap{
+				"span": func() string { return c.Request().Context().Value(spanKey{}).(string) },
+			}
+		}),
+	)
+
+	err := render.AddWithLayout(fsys, "layout.html", "pages/*.html")
+	assert.NoError(err)
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/", http.NoBody)
+	c := e.NewContext(req, httptest.NewRecorder())
+
+	output := bytes.NewBufferString("")
+	err = render.Render(output, "index.html", nil, c)
+	assert.NoError(err)
+	assert.Equal("<body>render index.html</body>", output.String())
+
+	assert.Equal([]string{"render index.html"}, spanNames)
+	assert.Len(infos, 1)
+	assert.Equal("index.html", infos[0].Name)
+	assert.Equal("layout.html", infos[0].Layout)
+	assert.Equal(int64(output.Len()), infos[0].Bytes)
+	assert.NoError(infos[0].Err)
+
+	assert.Equal(req, c.Request())
+}
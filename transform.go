@@ -0,0 +1,88 @@
+package templates
+
+import (
+	"bytes"
+	"io"
+
+	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
+)
+
+// Transformer post-processes the output of a template, such as minifying it or rewriting links. The
+// output is buffered when transformers are configured, so nothing is written if a render fails.
+type Transformer interface {
+	Transform(name string, in []byte) ([]byte, error)
+}
+
+// ContextTransformer is a Transformer which uses the echo.Context of the request being rendered, such as
+// InjectCSPNonce. TransformContext is called in place of Transform when rendering a request.
+type ContextTransformer interface {
+	Transformer
+	TransformContext(c echo.Context, name string, in []byte) ([]byte, error)
+}
+
+// TransformerFunc is an adapter to allow the use of ordinary functions as a Transformer.
+type TransformerFunc func(name string, in []byte) ([]byte, error)
+
+// Transform calls f(name, in).
+func (f TransformerFunc) Transform(name string, in []byte) ([]byte, error) {
+	return f(name, in)
+}
+
+// WithTransformer adds a Transformer applied to the output of every render, transformers are applied in
+// the order they are added.
+func WithTransformer(tr Transformer) Option {
+	return func(t *TemplateRenderer) {
+		t.transformers = append(t.transformers, tr)
+	}
+}
+
+// executeBuffered executes the template into a buffer, applying the transformers before writing it. If
+// the render fails the output is discarded, and the error page written in debug mode.
+func (t *TemplateRenderer) executeBuffered(w io.Writer, tmpl *Template, block string, data interface{}, c echo.Context) error {
+	buf := new(bytes.Buffer)
+
+	err := t.executeTemplate(buf, tmpl, block, data, c)
+	if err == nil {
+		err = t.transform(tmpl, buf, c)
+	}
+
+	if err != nil {
+		if t.debug {
+			t.writeDebugPage(c, tmpl, err)
+		}
+
+		return err
+	}
+
+	_, err = buf.WriteTo(w)
+
+	return err
+}
+
+func (t *TemplateRenderer) transform(tmpl *Template, buf *bytes.Buffer, c echo.Context) error {
+	if len(t.transformers) == 0 {
+		return nil
+	}
+
+	out := buf.Bytes()
+
+	for _, tr := range t.transformers {
+		var err error
+
+		if ct, ok := tr.(ContextTransformer); ok && c != nil {
+			out, err = ct.TransformContext(c, tmpl.name, out)
+		} else {
+			out, err = tr.Transform(tmpl.name, out)
+		}
+
+		if err != nil {
+			return errors.Wrapf(err, "failed to transform template %s", tmpl.name)
+		}
+	}
+
+	buf.Reset()
+	buf.Write(out)
+
+	return nil
+}
@@ -0,0 +1,62 @@
+package templates_test
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/require"
+	templates "github.com/wolfeidau/echo-go-templates"
+)
+
+func Test_WithTransformer(t *testing.T) {
+	assert := require.New(t)
+
+	fsys := fstest.MapFS{
+		"pages/index.html": {Data: []byte("<p>\n  {{.}}\n</p>")},
+	}
+
+	var names []string
+
+	render := templates.New(
+		templates.WithTransformer(templates.TransformerFunc(func(name string, in []byte) ([]byte, error) {
+			names = append(names, name)
+			return bytes.ReplaceAll(in, []byte("\n  "), nil), nil
+		})),
+		templates.WithTransformer(templates.TransformerFunc(func(name string, in []byte) ([]byte, error) {
+			return bytes.ReplaceAll(in, []byte("\n"), nil), nil
+		})),
+	)
+
+	err := render.Add(fsys, "pages/*.html")
+	assert.NoError(err)
+
+	e := echo.New()
+	c := e.NewContext(httptest.NewRequest(http.MethodGet, "/", http.NoBody), httptest.NewRecorder())
+
+	buf := new(bytes.Buffer)
+
+	err = render.Render(buf, "index.html", "hello", c)
+	assert.NoError(err)
+	assert.Equal("<p>hello</p>", buf.String())
+	assert.Equal([]string{"index.html"}, names)
+
+	failing := render.Group(templates.WithTransformer(templates.TransformerFunc(func(name string, in []byte) ([]byte, error) {
+		return nil, errors.New("bad output")
+	})))
+
+	buf.Reset()
+
+	err = failing.Render(buf, "index.html", "hello", c)
+	assert.EqualError(err, "failed to transform template index.html: bad output")
+	assert.Empty(buf.String())
+
+	s, err := render.RenderString("index.html", strings.Repeat("a", 3))
+	assert.NoError(err)
+	assert.Equal("<p>aaa</p>", s)
+}
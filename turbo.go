@@ -0,0 +1,122 @@
+package templates
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+
+	"github.com/labstack/echo/v4"
+)
+
+// MIMETurboStream is the content type of a Turbo Stream response.
+const MIMETurboStream = "text/vnd.turbo-stream.html"
+
+// TurboStreams composes a Turbo Stream response from rendered templates, see TemplateRenderer.TurboStreams.
+type TurboStreams struct {
+	render  *TemplateRenderer
+	actions []turboAction
+}
+
+type turboAction struct {
+	action string
+	target string
+	name   string
+	data   any
+}
+
+// TurboStreams returns a builder composing a Turbo Stream response, each action renders a template, which
+// supports the same "#" block suffix as Render, wrapped in a <turbo-stream> element:
+//
+//	return render.TurboStreams().
+//		Append("messages", "messages.html#message", msg).
+//		Update("unread", "badge.html", count).
+//		Render(c, http.StatusOK)
+func (t *TemplateRenderer) TurboStreams() *TurboStreams {
+	return &TurboStreams{render: t}
+}
+
+// Append appends the rendered template to the element with the target id.
+func (s *TurboStreams) Append(target, name string, data any) *TurboStreams {
+	return s.add("append", target, name, data)
+}
+
+// Prepend prepends the rendered template to the element with the target id.
+func (s *TurboStreams) Prepend(target, name string, data any) *TurboStreams {
+	return s.add("prepend", target, name, data)
+}
+
+// Replace replaces the element with the target id with the rendered template.
+func (s *TurboStreams) Replace(target, name string, data any) *TurboStreams {
+	return s.add("replace", target, name, data)
+}
+
+// Update replaces the content of the element with the target id with the rendered template.
+func (s *TurboStreams) Update(target, name string, data any) *TurboStreams {
+	return s.add("update", target, name, data)
+}
+
+// Before inserts the rendered template before the element with the target id.
+func (s *TurboStreams) Before(target, name string, data any) *TurboStreams {
+	return s.add("before", target, name, data)
+}
+
+// After inserts the rendered template after the element with the target id.
+func (s *TurboStreams) After(target, name string, data any) *TurboStreams {
+	return s.add("after", target, name, data)
+}
+
+// Remove removes the element with the target id.
+func (s *TurboStreams) Remove(target string) *TurboStreams {
+	return s.add("remove", target, "", nil)
+}
+
+func (s *TurboStreams) add(action, target, name string, data any) *TurboStreams {
+	s.actions = append(s.actions, turboAction{action: action, target: target, name: name, data: data})
+	return s
+}
+
+// Render writes the Turbo Stream response with the status code.
+func (s *TurboStreams) Render(c echo.Context, code int) error {
+	buf := new(bytes.Buffer)
+
+	err := s.write(buf, c)
+	if err != nil {
+		return err
+	}
+
+	return s.render.blob(c, code, MIMETurboStream, buf.Bytes())
+}
+
+// String renders the Turbo Stream elements without a request, for example to broadcast them over a
+// WebSocket.
+func (s *TurboStreams) String() (string, error) {
+	buf := new(bytes.Buffer)
+
+	err := s.write(buf, nil)
+	if err != nil {
+		return "", err
+	}
+
+	return buf.String(), nil
+}
+
+func (s *TurboStreams) write(buf *bytes.Buffer, c echo.Context) error {
+	for _, a := range s.actions {
+		fmt.Fprintf(buf, `<turbo-stream action="%s" target="%s">`, a.action, html.EscapeString(a.target))
+
+		if a.name != "" {
+			buf.WriteString("<template>")
+
+			err := s.render.render(buf, a.name, a.data, c)
+			if err != nil {
+				return err
+			}
+
+			buf.WriteString("</template>")
+		}
+
+		buf.WriteString("</turbo-stream>\n")
+	}
+
+	return nil
+}
@@ -0,0 +1,46 @@
+package templates_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"testing/fstest"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/require"
+	templates "github.com/wolfeidau/echo-go-templates"
+)
+
+func Test_TurboStreams(t *testing.T) {
+	assert := require.New(t)
+
+	fsys := fstest.MapFS{
+		"messages.html": {Data: []byte(`<ul id="messages">{{range .}}{{block "message" .}}<li>{{.}}</li>{{end}}{{end}}</ul>`)},
+		"badge.html":    {Data: []byte(`<span>{{.}}</span>`)},
+	}
+
+	render := templates.New()
+
+	err := render.Add(fsys, "*.html")
+	assert.NoError(err)
+
+	e := echo.New()
+	rec := httptest.NewRecorder()
+	c := e.NewContext(httptest.NewRequest(http.MethodPost, "/messages", nil), rec)
+
+	err = render.TurboStreams().
+		Append("messages", "messages.html#message", "hello").
+		Update("unread", "badge.html", 3).
+		Remove(`draft"1`).
+		Render(c, http.StatusOK)
+	assert.NoError(err)
+
+	assert.Equal(templates.MIMETurboStream, rec.Header().Get(echo.HeaderContentType))
+	assert.Equal(`<turbo-stream action="append" target="messages"><template><li>hello</li></template></turbo-stream>
+<turbo-stream action="update" target="unread"><template><span>3</span></template></turbo-stream>
+<turbo-stream action="remove" target="draft&#34;1"></turbo-stream>
+`, rec.Body.String())
+
+	_, err = render.TurboStreams().Replace("x", "missing.html", nil).String()
+	assert.ErrorContains(err, "no template named")
+}
@@ -0,0 +1,56 @@
+package templates
+
+import (
+	"bytes"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+// TypedTemplate renders a template with data of type T, so handlers are checked at compile time to pass
+// the view model the template expects, see Typed.
+type TypedTemplate[T any] struct {
+	render *TemplateRenderer
+	name   string
+}
+
+// Typed returns a handle rendering the named template with data of type T, the type is also registered
+// using Expect so renders by name are checked:
+//
+//	var indexPage = templates.Typed[IndexData](render, "index.html")
+//
+//	return indexPage.Render(c, IndexData{Title: "Home"})
+func Typed[T any](render *TemplateRenderer, name string) *TypedTemplate[T] {
+	var zero T
+
+	render.Expect(name, zero)
+
+	return &TypedTemplate[T]{render: render, name: name}
+}
+
+// Name returns the name of the template.
+func (tt *TypedTemplate[T]) Name() string {
+	return tt.name
+}
+
+// Render writes the rendered template to the response with the 200 OK status code.
+func (tt *TypedTemplate[T]) Render(c echo.Context, data T) error {
+	return tt.RenderCode(c, http.StatusOK, data)
+}
+
+// RenderCode writes the rendered template to the response with the status code.
+func (tt *TypedTemplate[T]) RenderCode(c echo.Context, code int, data T) error {
+	buf := new(bytes.Buffer)
+
+	err := tt.render.render(buf, tt.name, data, c)
+	if err != nil {
+		return err
+	}
+
+	return tt.render.blob(c, code, echo.MIMETextHTMLCharsetUTF8, buf.Bytes())
+}
+
+// String renders the template without a request, see RenderString.
+func (tt *TypedTemplate[T]) String(data T) (string, error) {
+	return tt.render.RenderString(tt.name, data)
+}
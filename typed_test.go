@@ -0,0 +1,44 @@
+package templates_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"testing/fstest"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/require"
+	templates "github.com/wolfeidau/echo-go-templates"
+)
+
+func Test_Typed(t *testing.T) {
+	assert := require.New(t)
+
+	fsys := fstest.MapFS{
+		"index.html": {Data: []byte(`<h1>{{.Title}}</h1>`)},
+	}
+
+	render := templates.New()
+
+	err := render.Add(fsys, "*.html")
+	assert.NoError(err)
+
+	index := templates.Typed[indexData](render, "index.html")
+	assert.Equal("index.html", index.Name())
+
+	e := echo.New()
+	rec := httptest.NewRecorder()
+	c := e.NewContext(httptest.NewRequest(http.MethodGet, "/", nil), rec)
+
+	err = index.RenderCode(c, http.StatusCreated, indexData{Title: "Home"})
+	assert.NoError(err)
+	assert.Equal(http.StatusCreated, rec.Code)
+	assert.Equal("<h1>Home</h1>", rec.Body.String())
+
+	s, err := index.String(indexData{Title: "About"})
+	assert.NoError(err)
+	assert.Equal("<h1>About</h1>", s)
+
+	_, err = render.RenderString("index.html", "wrong")
+	assert.ErrorContains(err, "expects data of type templates_test.indexData")
+}
@@ -0,0 +1,72 @@
+package templates
+
+import (
+	"sort"
+	"text/template/parse"
+)
+
+// Unused returns the templates which look to be dead, in sorted order. This is made up of the registered
+// templates which haven't been rendered since the renderer was created, along with the templates defined
+// in layouts, pages and includes which aren't referenced by any {{template}} action. Defined templates are
+// returned as the file they are defined in and their name separated by #, such as "footer.html#footer".
+//
+// As renders are only tracked in memory, check this after the application has been serving traffic for a
+// while before deleting anything.
+func (t *TemplateRenderer) Unused() []string {
+	var unused []string
+
+	for _, ts := range t.Stats() {
+		if ts.Renders == 0 {
+			unused = append(unused, ts.Name)
+		}
+	}
+
+	return append(unused, t.unreferenced()...)
+}
+
+// unreferenced returns the defined templates which aren't referenced by a {{template}} action in any of
+// the registered templates.
+func (t *TemplateRenderer) unreferenced() []string {
+	var (
+		referenced = make(map[string]bool)
+		defined    = make(map[string]string)
+	)
+
+	for _, name := range t.Names() {
+		tmpl, ok := t.lookup(name)
+		if !ok || tmpl.ensureParsed() != nil {
+			continue
+		}
+
+		for name, tree := range tmpl.template.Trees() {
+			if tree == nil {
+				continue
+			}
+
+			walkNodes(tree.Root, func(node parse.Node) {
+				if ref, ok := node.(*parse.TemplateNode); ok {
+					referenced[ref.Name] = true
+				}
+			})
+
+			// the tree of each file is executed directly rather than referenced
+			if tree.ParseName == name || name == tmpl.layout || name == tmpl.filename {
+				continue
+			}
+
+			defined[name] = tree.ParseName
+		}
+	}
+
+	var unused []string
+
+	for name, file := range defined {
+		if !referenced[name] {
+			unused = append(unused, file+"#"+name)
+		}
+	}
+
+	sort.Strings(unused)
+
+	return unused
+}
@@ -0,0 +1,37 @@
+package templates_test
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"testing/fstest"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/require"
+	templates "github.com/wolfeidau/echo-go-templates"
+)
+
+func Test_Unused(t *testing.T) {
+	assert := require.New(t)
+
+	fsys := fstest.MapFS{
+		"layout.html":          {Data: []byte(`<html>{{template "header" .}}{{block "content" .}}{{end}}</html>`)},
+		"includes/header.html": {Data: []byte(`{{define "header"}}<h1>{{template "logo"}}</h1>{{end}}{{define "logo"}}logo{{end}}`)},
+		"includes/old.html":    {Data: []byte(`{{define "old"}}old{{end}}`)},
+		"pages/index.html":     {Data: []byte(`{{define "content"}}index{{end}}`)},
+		"pages/about.html":     {Data: []byte(`{{define "content"}}about{{end}}{{define "sidebar"}}sidebar{{end}}`)},
+	}
+
+	render := templates.New()
+
+	err := render.AddWithLayoutAndIncludes(fsys, "layout.html", "includes/*.html", "pages/*.html")
+	assert.NoError(err)
+
+	e := echo.New()
+	c := e.NewContext(httptest.NewRequest(http.MethodGet, "/", http.NoBody), httptest.NewRecorder())
+
+	assert.NoError(render.Render(bytes.NewBufferString(""), "index.html", nil, c))
+
+	assert.Equal([]string{"about.html", "about.html#sidebar", "old.html#old"}, render.Unused())
+}
@@ -0,0 +1,135 @@
+package templates
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/template/parse"
+
+	"github.com/pkg/errors"
+)
+
+// Validate checks every {{template}} action in the registered templates, including their layouts and
+// includes, refers to a defined template, returning an error listing all the missing references. This
+// is intended to be called at startup, or in a test, as missing partials otherwise only fail at runtime.
+func (t *TemplateRenderer) Validate() error {
+	var problems []string
+
+	for _, name := range t.Names() {
+		tmpl, ok := t.lookup(name)
+		if !ok {
+			continue
+		}
+
+		err := tmpl.ensureParsed()
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("template %s: %v", name, err))
+			continue
+		}
+
+		for _, missing := range tmpl.undefinedReferences() {
+			problems = append(problems, fmt.Sprintf("template %s: %s", name, missing))
+		}
+	}
+
+	if len(problems) > 0 {
+		return errors.Errorf("template validation failed:\n%s", strings.Join(problems, "\n"))
+	}
+
+	return nil
+}
+
+// undefinedReferences returns a description of each {{template}} action referring to an undefined template.
+func (tmpl *Template) undefinedReferences() []string {
+	trees := tmpl.template.Trees()
+
+	var missing []string
+
+	for name, tree := range trees {
+		if tree == nil {
+			continue
+		}
+
+		walkNodes(tree.Root, func(node parse.Node) {
+			ref, ok := node.(*parse.TemplateNode)
+			if !ok || trees[ref.Name] != nil {
+				return
+			}
+
+			missing = append(missing, fmt.Sprintf("%s references undefined template %q", name, ref.Name))
+		})
+	}
+
+	sort.Strings(missing)
+
+	return missing
+}
+
+// walkNodes calls fn for every node in the tree.
+func walkNodes(node parse.Node, fn func(parse.Node)) {
+	if node == nil {
+		return
+	}
+
+	fn(node)
+
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return
+		}
+		for _, child := range n.Nodes {
+			walkNodes(child, fn)
+		}
+	case *parse.IfNode:
+		walkNodes(n.List, fn)
+		walkNodes(n.ElseList, fn)
+	case *parse.RangeNode:
+		walkNodes(n.List, fn)
+		walkNodes(n.ElseList, fn)
+	case *parse.WithNode:
+		walkNodes(n.List, fn)
+		walkNodes(n.ElseList, fn)
+	}
+}
+
+// ValidateWithData executes every registered template with the sample data for its name, or nil if
+// there is none, discarding the output, returning an error listing all the templates which failed. This
+// catches runtime errors such as calling a function with the wrong arguments before deployment.
+//
+// Templates are executed without a request, so request scoped functions, decorators and hooks aren't
+// applied, and panics in template functions are reported as errors.
+func (t *TemplateRenderer) ValidateWithData(samples map[string]any) error {
+	var problems []string
+
+	for _, name := range t.Names() {
+		tmpl, ok := t.lookup(name)
+		if !ok {
+			continue
+		}
+
+		err := tmpl.dryRun(samples[name])
+		if err != nil {
+			problems = append(problems, err.Error())
+		}
+	}
+
+	if len(problems) > 0 {
+		return errors.Errorf("template validation failed:\n%s", strings.Join(problems, "\n"))
+	}
+
+	return nil
+}
+
+// dryRun executes the template with the data, discarding the output.
+func (tmpl *Template) dryRun(data any) (err error) {
+	defer tmpl.recoverPanic(&err)
+
+	exec, err := tmpl.executable(nil)
+	if err != nil {
+		return err
+	}
+
+	return tmpl.renderError(exec.ExecuteTemplate(io.Discard, tmpl.execName(), data))
+}
@@ -0,0 +1,67 @@
+package templates_test
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/require"
+	templates "github.com/wolfeidau/echo-go-templates"
+	"github.com/wolfeidau/echo-go-templates/test/views"
+)
+
+func Test_Validate(t *testing.T) {
+	assert := require.New(t)
+
+	render := templates.New()
+
+	err := render.AddWithLayoutAndIncludes(views.Content, "layout.html", "includes/*.html", "pages/*.html")
+	assert.NoError(err)
+
+	assert.NoError(render.Validate())
+
+	fsys := fstest.MapFS{
+		"layout.html":      {Data: []byte(`{{template "header" .}}{{block "content" .}}{{end}}`)},
+		"pages/index.html": {Data: []byte(`{{define "content"}}{{if .}}{{range .}}{{template "row" .}}{{end}}{{end}}{{end}}`)},
+	}
+
+	err = render.AddWithLayout(fsys, "layout.html", "pages/*.html")
+	assert.NoError(err)
+
+	err = render.Validate()
+	assert.EqualError(err, "template validation failed:\n"+
+		"template index.html: content references undefined template \"row\"\n"+
+		"template index.html: layout.html references undefined template \"header\"")
+}
+
+func Test_ValidateWithData(t *testing.T) {
+	assert := require.New(t)
+
+	fsys := fstest.MapFS{
+		"layout.html":      {Data: []byte(`{{block "content" .}}{{end}}`)},
+		"pages/index.html": {Data: []byte(`{{define "content"}}{{.Title}}{{end}}`)},
+		"pages/list.html":  {Data: []byte(`{{define "content"}}{{index .Items 5}}{{end}}`)},
+		"pages/user.html":  {Data: []byte(`{{define "content"}}{{upper .Name}}{{end}}`)},
+	}
+
+	render := templates.New(templates.WithFuncs(templates.CommonFuncs()))
+
+	err := render.AddWithLayout(fsys, "layout.html", "pages/*.html")
+	assert.NoError(err)
+
+	err = render.ValidateWithData(map[string]any{
+		"index.html": map[string]any{"Title": "home"},
+		"list.html":  map[string]any{"Items": []string{"one"}},
+		"user.html":  map[string]any{"Name": 1},
+	})
+	assert.ErrorContains(err, "template validation failed:\nrender template list.html failed at pages/list.html:1: ")
+	assert.ErrorContains(err, "index out of range: 5")
+	assert.ErrorContains(err, "\nrender template user.html failed at pages/user.html:1: ")
+	assert.NotContains(err.Error(), "index.html")
+
+	err = render.ValidateWithData(map[string]any{
+		"index.html": map[string]any{"Title": "home"},
+		"list.html":  map[string]any{"Items": []string{"0", "1", "2", "3", "4", "5"}},
+		"user.html":  map[string]any{"Name": "mark"},
+	})
+	assert.NoError(err)
+}
@@ -0,0 +1,73 @@
+package templates
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+// ViewModel is the standard envelope data is wrapped in when WithViewModel is enabled, giving every
+// template the same top level shape:
+//
+//	<title>{{.Meta.title}} - {{.Globals.AppName}}</title>
+//	{{range .Flash}}<p>{{.Message}}</p>{{end}}
+//	{{.Data.Name}}
+type ViewModel struct {
+	// Data is the data passed to Render.
+	Data any
+	// Request is the request being rendered, this is nil when rendering without a request.
+	Request *http.Request
+	// Globals are the values configured using WithGlobalData.
+	Globals map[string]any
+	// Meta is the front matter of the template being rendered.
+	Meta map[string]any
+
+	c echo.Context
+}
+
+// Flash returns the flash messages for the request, they are only read, and cleared, when this is called.
+func (v *ViewModel) Flash() []FlashMessage {
+	if v.c == nil {
+		return nil
+	}
+
+	return Flashes(v.c)
+}
+
+// CSRF returns the token set by Echo's CSRF middleware using the default CSRFContextKey.
+func (v *ViewModel) CSRF() string {
+	if v.c == nil {
+		return ""
+	}
+
+	token, _ := v.c.Get(CSRFContextKey).(string)
+
+	return token
+}
+
+// Context returns the echo.Context of the request being rendered.
+func (v *ViewModel) Context() echo.Context {
+	return v.c
+}
+
+// WithViewModel wraps the data passed to every template in a ViewModel.
+func WithViewModel() Option {
+	return func(t *TemplateRenderer) {
+		t.viewModel = true
+	}
+}
+
+func (t *TemplateRenderer) newViewModel(c echo.Context, tmpl *Template, data any) *ViewModel {
+	v := &ViewModel{
+		Data:    data,
+		Globals: t.globals,
+		Meta:    tmpl.meta,
+		c:       c,
+	}
+
+	if c != nil {
+		v.Request = c.Request()
+	}
+
+	return v
+}
@@ -0,0 +1,42 @@
+package templates_test
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"testing/fstest"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/require"
+	templates "github.com/wolfeidau/echo-go-templates"
+)
+
+func Test_WithViewModel(t *testing.T) {
+	assert := require.New(t)
+
+	fsys := fstest.MapFS{
+		"layout.html": {Data: []byte(`<title>{{.Meta.title}} - {{.Globals.AppName}}</title>{{block "content" .}}{{end}}`)},
+		"pages/user.html": {Data: []byte(`---
+title: User
+---
+{{define "content"}}{{.Data.Name}} {{.Request.URL.Path}} {{.CSRF}} {{len .Flash}}{{end}}`)},
+	}
+
+	render := templates.New(
+		templates.WithGlobalData(map[string]any{"AppName": "app"}),
+		templates.WithViewModel(),
+	)
+
+	err := render.AddWithLayout(fsys, "layout.html", "pages/*.html")
+	assert.NoError(err)
+
+	e := echo.New()
+	c := e.NewContext(httptest.NewRequest(http.MethodGet, "/users/1", http.NoBody), httptest.NewRecorder())
+	c.Set(templates.CSRFContextKey, "token")
+
+	output := bytes.NewBufferString("")
+	err = render.Render(output, "user.html", struct{ Name string }{Name: "mark"}, c)
+	assert.NoError(err)
+	assert.Equal("<title>User - app</title>mark /users/1 token 0", output.String())
+}
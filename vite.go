@@ -0,0 +1,156 @@
+package templates
+
+import (
+	"encoding/json"
+	"fmt"
+	"html"
+	"html/template"
+	"io/fs"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// ViteManifest is the manifest.json produced by a Vite build, which maps each source file to the hashed
+// files built from it. The flat name to path manifest produced by webpack-manifest-plugin is also supported.
+type ViteManifest map[string]ViteChunk
+
+// ViteChunk is an entry in a ViteManifest.
+type ViteChunk struct {
+	File    string   `json:"file"`
+	Src     string   `json:"src,omitempty"`
+	IsEntry bool     `json:"isEntry,omitempty"`
+	Imports []string `json:"imports,omitempty"`
+	CSS     []string `json:"css,omitempty"`
+}
+
+// LoadViteManifest reads the manifest file from fsys, typically ".vite/manifest.json" in the build output.
+func LoadViteManifest(fsys fs.FS, name string) (ViteManifest, error) {
+	data, err := fs.ReadFile(fsys, name)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read manifest %s", name)
+	}
+
+	var raw map[string]json.RawMessage
+
+	err = json.Unmarshal(data, &raw)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to parse manifest %s", name)
+	}
+
+	manifest := make(ViteManifest, len(raw))
+
+	for key, value := range raw {
+		var chunk ViteChunk
+
+		// webpack manifests map the name directly to the file
+		var file string
+		if json.Unmarshal(value, &file) == nil {
+			chunk.File = file
+		} else if err := json.Unmarshal(value, &chunk); err != nil {
+			return nil, errors.Wrapf(err, "failed to parse manifest %s entry %s", name, key)
+		}
+
+		manifest[key] = chunk
+	}
+
+	return manifest, nil
+}
+
+// ViteConfig configures the Vite template functions.
+type ViteConfig struct {
+	// Manifest is the manifest of the production build.
+	Manifest ViteManifest
+	// Base is the URL path the build output is served from, such as "/static/".
+	Base string
+	// DevServer is the URL of the Vite dev server, such as "http://localhost:5173", when set the
+	// functions return URLs on the dev server and the manifest isn't used.
+	DevServer string
+}
+
+// WithVite adds the viteAsset and viteTags template functions. viteAsset returns the URL of the file
+// built from a source file, and viteTags returns the script, stylesheet and module preload tags for an
+// entry point:
+//
+//	<head>{{viteTags "src/main.ts"}}</head>
+//	<img src="{{viteAsset "src/logo.svg"}}">
+func WithVite(cfg ViteConfig) Option {
+	return WithFuncs(template.FuncMap{
+		"viteAsset": cfg.asset,
+		"viteTags":  cfg.tags,
+	})
+}
+
+func (cfg ViteConfig) asset(name string) (string, error) {
+	if cfg.DevServer != "" {
+		return cfg.devURL(name), nil
+	}
+
+	chunk, ok := cfg.Manifest[name]
+	if !ok {
+		return "", fmt.Errorf("vite: no manifest entry for %#q", name)
+	}
+
+	return cfg.url(chunk.File), nil
+}
+
+func (cfg ViteConfig) tags(entry string) (template.HTML, error) {
+	if cfg.DevServer != "" {
+		return template.HTML(scriptTag(cfg.devURL("@vite/client")) + scriptTag(cfg.devURL(entry))), nil //nolint:gosec // urls are escaped
+	}
+
+	chunk, ok := cfg.Manifest[entry]
+	if !ok {
+		return "", fmt.Errorf("vite: no manifest entry for %#q", entry)
+	}
+
+	var (
+		sb   strings.Builder
+		seen = make(map[string]bool)
+	)
+
+	for _, css := range cfg.css(entry, seen) {
+		fmt.Fprintf(&sb, `<link rel="stylesheet" href="%s">`, html.EscapeString(cfg.url(css)))
+	}
+
+	sb.WriteString(scriptTag(cfg.url(chunk.File)))
+
+	for _, imp := range chunk.Imports {
+		if c, ok := cfg.Manifest[imp]; ok {
+			fmt.Fprintf(&sb, `<link rel="modulepreload" href="%s">`, html.EscapeString(cfg.url(c.File)))
+		}
+	}
+
+	return template.HTML(sb.String()), nil //nolint:gosec // urls are escaped
+}
+
+// css returns the stylesheets of the chunk and the chunks it imports.
+func (cfg ViteConfig) css(name string, seen map[string]bool) []string {
+	if seen[name] {
+		return nil
+	}
+
+	seen[name] = true
+
+	chunk := cfg.Manifest[name]
+
+	var css []string
+
+	for _, imp := range chunk.Imports {
+		css = append(css, cfg.css(imp, seen)...)
+	}
+
+	return append(css, chunk.CSS...)
+}
+
+func (cfg ViteConfig) url(file string) string {
+	return strings.TrimSuffix(cfg.Base, "/") + "/" + strings.TrimPrefix(file, "/")
+}
+
+func (cfg ViteConfig) devURL(name string) string {
+	return strings.TrimSuffix(cfg.DevServer, "/") + "/" + strings.TrimPrefix(name, "/")
+}
+
+func scriptTag(src string) string {
+	return fmt.Sprintf(`<script type="module" src="%s"></script>`, html.EscapeString(src))
+}
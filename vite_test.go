@@ -0,0 +1,72 @@
+package templates_test
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/require"
+	templates "github.com/wolfeidau/echo-go-templates"
+)
+
+const viteManifest = `{
+  "_shared.83069a53.js": {"file": "assets/shared.83069a53.js", "css": ["assets/shared.a834bfc3.css"]},
+  "src/main.ts": {
+    "file": "assets/main.4889e940.js",
+    "src": "src/main.ts",
+    "isEntry": true,
+    "imports": ["_shared.83069a53.js"],
+    "css": ["assets/main.b82dbe22.css"]
+  },
+  "src/logo.svg": {"file": "assets/logo.cdb8d2f1.svg", "src": "src/logo.svg"}
+}`
+
+func Test_WithVite(t *testing.T) {
+	assert := require.New(t)
+
+	manifest, err := templates.LoadViteManifest(fstest.MapFS{
+		".vite/manifest.json": {Data: []byte(viteManifest)},
+	}, ".vite/manifest.json")
+	assert.NoError(err)
+
+	fsys := fstest.MapFS{
+		"pages/index.html": {Data: []byte(`{{viteTags "src/main.ts"}}<img src="{{viteAsset "src/logo.svg"}}">`)},
+		"pages/bad.html":   {Data: []byte(`{{viteTags "src/missing.ts"}}`)},
+	}
+
+	render := templates.New(templates.WithVite(templates.ViteConfig{Manifest: manifest, Base: "/static/"}))
+
+	err = render.Add(fsys, "pages/*.html")
+	assert.NoError(err)
+
+	s, err := render.RenderString("index.html", nil)
+	assert.NoError(err)
+	assert.Equal(`<link rel="stylesheet" href="/static/assets/shared.a834bfc3.css">`+
+		`<link rel="stylesheet" href="/static/assets/main.b82dbe22.css">`+
+		`<script type="module" src="/static/assets/main.4889e940.js"></script>`+
+		`<link rel="modulepreload" href="/static/assets/shared.83069a53.js">`+
+		`<img src="/static/assets/logo.cdb8d2f1.svg">`, s)
+
+	_, err = render.RenderString("bad.html", nil)
+	assert.ErrorContains(err, "vite: no manifest entry for `src/missing.ts`")
+
+	dev := templates.New(templates.WithVite(templates.ViteConfig{DevServer: "http://localhost:5173/"}))
+
+	err = dev.Add(fsys, "pages/index.html")
+	assert.NoError(err)
+
+	s, err = dev.RenderString("index.html", nil)
+	assert.NoError(err)
+	assert.Equal(`<script type="module" src="http://localhost:5173/@vite/client"></script>`+
+		`<script type="module" src="http://localhost:5173/src/main.ts"></script>`+
+		`<img src="http://localhost:5173/src/logo.svg">`, s)
+}
+
+func Test_LoadViteManifest_Webpack(t *testing.T) {
+	assert := require.New(t)
+
+	manifest, err := templates.LoadViteManifest(fstest.MapFS{
+		"manifest.json": {Data: []byte(`{"main.js": "main.1a2b3c.js"}`)},
+	}, "manifest.json")
+	assert.NoError(err)
+	assert.Equal(templates.ViteManifest{"main.js": {File: "main.1a2b3c.js"}}, manifest)
+}